@@ -0,0 +1,70 @@
+import io
+import json
+import os
+from typing import List
+
+import numpy as np
+import torch
+from PIL import Image
+from transformers import AutoFeatureExtractor, AutoModelForObjectDetection
+
+from triton_python_backend_utils import get_input_tensor_by_name
+from c_python_backend_utils import Tensor, InferenceResponse, InferenceRequest
+
+
+class TritonPythonModel(object):
+    def __init__(self):
+        self.model = None
+
+    def initialize(self, args):
+        dir_path = os.path.dirname(os.path.realpath(__file__))
+        self.feature_extractor = AutoFeatureExtractor.from_pretrained(dir_path)
+        self.model = AutoModelForObjectDetection.from_pretrained(dir_path)
+        self.model.eval()
+        parameters = json.loads(args['model_config'])['parameters']
+        self.score_threshold = float(parameters['score_threshold']['string_value'])
+
+    def execute(self, inference_requests: List[InferenceRequest]) -> List[InferenceResponse]:
+        input_name = 'input'
+
+        responses = []
+        for request in inference_requests:
+            batch_in_tensor: Tensor = get_input_tensor_by_name(request, input_name)
+            if batch_in_tensor is None:
+                raise ValueError(f'Input tensor {input_name} not found '
+                                 f'in request {request.request_id()}')
+
+            batch_in = batch_in_tensor.as_numpy()  # shape (batch_size, 1)
+            if batch_in.dtype.type is not np.object_:
+                raise ValueError(f'Input datatype must be np.object_, '
+                                 f'got {batch_in.dtype.type}')
+
+            images = [Image.open(io.BytesIO(img[0])).convert('RGB') for img in batch_in]
+            inputs = self.feature_extractor(images=images, return_tensors='pt')
+            with torch.no_grad():
+                outputs = self.model(**inputs)
+            target_sizes = torch.tensor([image.size[::-1] for image in images])
+            results = self.feature_extractor.post_process(outputs, target_sizes=target_sizes)
+
+            batch_bboxes, batch_labels = [], []
+            for result in results:
+                keep = result['scores'] > self.score_threshold
+                bboxes = torch.cat([result['boxes'][keep], result['scores'][keep].unsqueeze(-1)], dim=-1)
+                labels = [self.model.config.id2label[int(label)] for label in result['labels'][keep]]
+                batch_bboxes.append(bboxes.numpy().astype(np.float32))
+                batch_labels.append(labels)
+
+            # Non-meaningful bboxes are added with coords [-1, -1, -1, -1, -1] and label "0" for Triton to be able to batch Tensors
+            max_objects = max([1] + [len(labels) for labels in batch_labels])
+            out_bboxes = np.full((len(images), max_objects, 5), -1, dtype=np.float32)
+            out_labels = np.full((len(images), max_objects), '0', dtype=object)
+            for i, (bboxes, labels) in enumerate(zip(batch_bboxes, batch_labels)):
+                out_bboxes[i, :len(labels)] = bboxes
+                out_labels[i, :len(labels)] = labels
+
+            responses.append(InferenceResponse([
+                Tensor('output_bboxes', out_bboxes),
+                Tensor('output_labels', out_labels.astype(np.bytes_)),
+            ]))
+
+        return responses
@@ -0,0 +1,49 @@
+import os
+from typing import List
+
+import numpy as np
+import torch
+from transformers import AutoModel, AutoTokenizer
+
+from triton_python_backend_utils import get_input_tensor_by_name
+from c_python_backend_utils import Tensor, InferenceResponse, InferenceRequest
+
+
+class TritonPythonModel(object):
+    def __init__(self):
+        self.model = None
+
+    def initialize(self, args):
+        dir_path = os.path.dirname(os.path.realpath(__file__))
+        self.tokenizer = AutoTokenizer.from_pretrained(dir_path)
+        self.model = AutoModel.from_pretrained(dir_path)
+        self.model.eval()
+
+    def execute(self, inference_requests: List[InferenceRequest]) -> List[InferenceResponse]:
+        input_name = 'input'
+
+        responses = []
+        for request in inference_requests:
+            batch_in_tensor: Tensor = get_input_tensor_by_name(request, input_name)
+            if batch_in_tensor is None:
+                raise ValueError(f'Input tensor {input_name} not found '
+                                 f'in request {request.request_id()}')
+
+            batch_in = batch_in_tensor.as_numpy()  # shape (batch_size, 1)
+            if batch_in.dtype.type is not np.object_:
+                raise ValueError(f'Input datatype must be np.object_, '
+                                 f'got {batch_in.dtype.type}')
+
+            texts = [text[0].decode('utf-8') for text in batch_in]
+            inputs = self.tokenizer(texts, padding=True, truncation=True, return_tensors='pt')
+            with torch.no_grad():
+                hidden_state = self.model(**inputs).last_hidden_state
+            # the embedding is the mean of the token states, leaving out the padding tokens
+            mask = inputs['attention_mask'].unsqueeze(-1).float()
+            embedding = (hidden_state * mask).sum(dim=1) / mask.sum(dim=1).clamp(min=1e-9)
+
+            responses.append(InferenceResponse([
+                Tensor('embedding', embedding.numpy().astype(np.float32)),
+            ]))
+
+        return responses
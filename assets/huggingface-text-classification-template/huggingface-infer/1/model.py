@@ -0,0 +1,53 @@
+import os
+from typing import List
+
+import numpy as np
+import torch
+from transformers import AutoModelForSequenceClassification, AutoTokenizer
+
+from triton_python_backend_utils import get_input_tensor_by_name
+from c_python_backend_utils import Tensor, InferenceResponse, InferenceRequest
+
+
+class TritonPythonModel(object):
+    def __init__(self):
+        self.model = None
+
+    def initialize(self, args):
+        dir_path = os.path.dirname(os.path.realpath(__file__))
+        self.tokenizer = AutoTokenizer.from_pretrained(dir_path)
+        self.model = AutoModelForSequenceClassification.from_pretrained(dir_path)
+        self.model.eval()
+        self.labels = [self.model.config.id2label[i] for i in range(self.model.config.num_labels)]
+
+    def execute(self, inference_requests: List[InferenceRequest]) -> List[InferenceResponse]:
+        input_name = 'input'
+
+        responses = []
+        for request in inference_requests:
+            batch_in_tensor: Tensor = get_input_tensor_by_name(request, input_name)
+            if batch_in_tensor is None:
+                raise ValueError(f'Input tensor {input_name} not found '
+                                 f'in request {request.request_id()}')
+
+            batch_in = batch_in_tensor.as_numpy()  # shape (batch_size, 1)
+            if batch_in.dtype.type is not np.object_:
+                raise ValueError(f'Input datatype must be np.object_, '
+                                 f'got {batch_in.dtype.type}')
+
+            texts = [text[0].decode('utf-8') for text in batch_in]
+            inputs = self.tokenizer(texts, padding=True, truncation=True, return_tensors='pt')
+            with torch.no_grad():
+                logits = self.model(**inputs).logits
+            if self.model.config.problem_type == 'multi_label_classification':
+                scores = torch.sigmoid(logits)
+            else:
+                scores = torch.softmax(logits, dim=-1)
+
+            labels = np.array([self.labels] * len(texts), dtype=object)
+            responses.append(InferenceResponse([
+                Tensor('scores', scores.numpy().astype(np.float32)),
+                Tensor('labels', labels.astype(np.bytes_)),
+            ]))
+
+        return responses
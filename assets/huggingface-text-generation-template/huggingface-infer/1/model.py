@@ -0,0 +1,64 @@
+import os
+from typing import List
+
+import numpy as np
+import torch
+from transformers import AutoModelForCausalLM, AutoTokenizer
+
+from triton_python_backend_utils import get_input_tensor_by_name
+from c_python_backend_utils import Tensor, InferenceResponse, InferenceRequest
+
+
+class TritonPythonModel(object):
+    def __init__(self):
+        self.model = None
+
+    def initialize(self, args):
+        dir_path = os.path.dirname(os.path.realpath(__file__))
+        self.tokenizer = AutoTokenizer.from_pretrained(dir_path)
+        self.model = AutoModelForCausalLM.from_pretrained(dir_path)
+        self.model.eval()
+
+    @staticmethod
+    def get_input(request: InferenceRequest, name: str):
+        tensor: Tensor = get_input_tensor_by_name(request, name)
+        if tensor is None:
+            raise ValueError(f'Input tensor {name} not found '
+                             f'in request {request.request_id()}')
+        return tensor.as_numpy()[0][0]
+
+    def get_words_ids(self, words: bytes):
+        words = [w.strip() for w in words.decode('utf-8').split(',') if w.strip()]
+        return [self.tokenizer(w, add_special_tokens=False).input_ids for w in words]
+
+    def execute(self, inference_requests: List[InferenceRequest]) -> List[InferenceResponse]:
+        responses = []
+        for request in inference_requests:
+            prompt = self.get_input(request, 'prompt').decode('utf-8')
+            output_len = int(self.get_input(request, 'output_len'))
+            bad_words_ids = self.get_words_ids(self.get_input(request, 'bad_words_list'))
+            stop_words = [w.strip() for w in self.get_input(request, 'stop_words_list').decode('utf-8').split(',') if w.strip()]
+            topk = int(self.get_input(request, 'topk'))
+            torch.manual_seed(int(self.get_input(request, 'random_seed')))
+
+            inputs = self.tokenizer(prompt, return_tensors='pt')
+            with torch.no_grad():
+                output_ids = self.model.generate(
+                    **inputs,
+                    max_new_tokens=output_len,
+                    do_sample=topk > 1,
+                    top_k=topk,
+                    bad_words_ids=bad_words_ids or None,
+                    pad_token_id=self.tokenizer.eos_token_id,
+                )
+            # only the generated tokens are checked for the stop words
+            generated = self.tokenizer.decode(output_ids[0][inputs.input_ids.shape[-1]:], skip_special_tokens=True)
+            for stop_word in stop_words:
+                if stop_word in generated:
+                    generated = generated[:generated.index(stop_word)]
+
+            responses.append(InferenceResponse([
+                Tensor('text', np.array([[prompt + generated]], dtype=np.bytes_)),
+            ]))
+
+        return responses
@@ -44,6 +44,8 @@ import (
 
 	database "github.com/instill-ai/model-backend/pkg/db"
 	custom_otel "github.com/instill-ai/model-backend/pkg/logger/otel"
+	modelWorker "github.com/instill-ai/model-backend/pkg/worker"
+	usagePB "github.com/instill-ai/protogen-go/base/usage/v1alpha"
 	modelPB "github.com/instill-ai/protogen-go/model/model/v1alpha"
 )
 
@@ -195,9 +197,21 @@ func main() {
 	}
 	defer temporalClient.Close()
 
+	// The operations are listed by owner and model through their search attributes
+	if err := modelWorker.RegisterSearchAttributes(ctx, temporalClient, config.Config.Temporal.Namespace); err != nil {
+		logger.Error(fmt.Sprintf("Unable to register the search attributes: %s", err))
+	}
+
+	var usageServiceClient usagePB.UsageServiceClient
+	if config.Config.Server.Usage.Enabled {
+		var usageServiceClientConn *grpc.ClientConn
+		usageServiceClient, usageServiceClientConn = external.InitUsageServiceClient(ctx)
+		defer usageServiceClientConn.Close()
+	}
+
 	repository := repository.NewRepository(db)
 
-	service := service.NewService(repository, triton, mgmtPrivateServiceClient, redisClient, temporalClient, controllerClient)
+	service := service.NewService(repository, triton, mgmtPrivateServiceClient, redisClient, temporalClient, controllerClient, usageServiceClient)
 
 	modelPB.RegisterModelPublicServiceServer(
 		publicGrpcS,
@@ -237,16 +251,116 @@ func main() {
 		panic(err)
 	}
 
-	// Register custom route for  POST /models/multipart which uploads model for REST multiple-part form-data
+	// Register custom route for GET /v1alpha/models/*/export which downloads the model package as a zip or tar.gz archive
+	if err := publicGwS.HandlePath("GET", "/v1alpha/{name=models/*}/export", middleware.AppendCustomHeaderMiddleware(service, handler.HandleExportModel)); err != nil {
+		panic(err)
+	}
+
+	// Register custom route for  POST /models/multipart which uploads model for REST multiple-part form-data,
+	// the validate_only field only runs the creation checks
 	if err := publicGwS.HandlePath("POST", "/v1alpha/models/multipart", middleware.AppendCustomHeaderMiddleware(service, handler.HandleCreateModelByMultiPartFormData)); err != nil {
 		panic(err)
 	}
 
+	// Register custom route for POST /models/validate which is a dry run of the creation of a model from the body of
+	// a CreateModel request, for every source but the local one
+	if err := publicGwS.HandlePath("POST", "/v1alpha/models/validate", middleware.AppendCustomHeaderMiddleware(service, handler.HandleValidateModel)); err != nil {
+		panic(err)
+	}
+
+	// Register custom routes for the resumable upload sessions of local models, created by POST /v1alpha/models/uploads,
+	// filled by PUT chunks and turned into a model by POST /v1alpha/uploads/*/finalize
+	if err := publicGwS.HandlePath("POST", "/v1alpha/models/uploads", middleware.AppendCustomHeaderMiddleware(service, handler.HandleCreateModelUploadSession)); err != nil {
+		panic(err)
+	}
+	if err := publicGwS.HandlePath("GET", "/v1alpha/{name=uploads/*}", middleware.AppendCustomHeaderMiddleware(service, handler.HandleGetModelUploadSession)); err != nil {
+		panic(err)
+	}
+	if err := publicGwS.HandlePath("PUT", "/v1alpha/{name=uploads/*}", middleware.AppendCustomHeaderMiddleware(service, handler.HandleUploadModelChunk)); err != nil {
+		panic(err)
+	}
+	if err := publicGwS.HandlePath("POST", "/v1alpha/{name=uploads/*}/finalize", middleware.AppendCustomHeaderMiddleware(service, handler.HandleFinalizeModelUploadSession)); err != nil {
+		panic(err)
+	}
+	if err := publicGwS.HandlePath("DELETE", "/v1alpha/{name=uploads/*}", middleware.AppendCustomHeaderMiddleware(service, handler.HandleDeleteModelUploadSession)); err != nil {
+		panic(err)
+	}
+
+	// Register custom routes for the model operations, listed by GET /v1alpha/operations, or for every owner by
+	// GET /v1alpha/admin/operations, and canceled by POST /v1alpha/operations/*/cancel
+	if err := publicGwS.HandlePath("GET", "/v1alpha/operations", middleware.AppendCustomHeaderMiddleware(service, handler.HandleListModelOperations)); err != nil {
+		panic(err)
+	}
+	if err := publicGwS.HandlePath("POST", "/v1alpha/{name=operations/*}/cancel", middleware.AppendCustomHeaderMiddleware(service, handler.HandleCancelModelOperation)); err != nil {
+		panic(err)
+	}
+	if err := privateGwS.HandlePath("GET", "/v1alpha/admin/operations", middleware.AppendCustomHeaderMiddleware(service, handler.HandleListModelOperationsAdmin)); err != nil {
+		panic(err)
+	}
+
+	// Register custom route for POST /v1alpha/models/bulk which deploys, undeploys, deletes, publishes or unpublishes
+	// many models in one operation
+	if err := publicGwS.HandlePath("POST", "/v1alpha/models/bulk", middleware.AppendCustomHeaderMiddleware(service, handler.HandleBulkModelOperation)); err != nil {
+		panic(err)
+	}
+
+	// Register custom routes for the model schedules deploying or undeploying a model on a cron, created by
+	// POST /v1alpha/models/*/schedules, listed by GET /v1alpha/schedules, paused, resumed and deleted by name
+	if err := publicGwS.HandlePath("POST", "/v1alpha/{name=models/*}/schedules", middleware.AppendCustomHeaderMiddleware(service, handler.HandleCreateModelSchedule)); err != nil {
+		panic(err)
+	}
+	if err := publicGwS.HandlePath("GET", "/v1alpha/schedules", middleware.AppendCustomHeaderMiddleware(service, handler.HandleListModelSchedules)); err != nil {
+		panic(err)
+	}
+	if err := publicGwS.HandlePath("POST", "/v1alpha/{name=schedules/*}/pause", middleware.AppendCustomHeaderMiddleware(service, handler.HandlePauseModelSchedule)); err != nil {
+		panic(err)
+	}
+	if err := publicGwS.HandlePath("POST", "/v1alpha/{name=schedules/*}/unpause", middleware.AppendCustomHeaderMiddleware(service, handler.HandleUnpauseModelSchedule)); err != nil {
+		panic(err)
+	}
+	if err := publicGwS.HandlePath("DELETE", "/v1alpha/{name=schedules/*}", middleware.AppendCustomHeaderMiddleware(service, handler.HandleDeleteModelSchedule)); err != nil {
+		panic(err)
+	}
+
+	// Register custom route for GET /v1alpha/admin/readiness which reports the readiness of every backing dependency
+	if err := privateGwS.HandlePath("GET", "/v1alpha/admin/readiness", middleware.AppendCustomHeaderMiddleware(service, handler.HandleReadinessReport)); err != nil {
+		panic(err)
+	}
+
+	// Register custom route for POST /v1alpha/{name=models/*}/idle-timeout which sets the idle timeout of a model
+	if err := publicGwS.HandlePath("POST", "/v1alpha/{name=models/*}/idle-timeout", middleware.AppendCustomHeaderMiddleware(service, handler.HandleSetModelIdleTimeout)); err != nil {
+		panic(err)
+	}
+
+	// Register custom route for POST /v1alpha/{name=models/*}/deploy-config which sets the deploy configuration of a model
+	if err := publicGwS.HandlePath("POST", "/v1alpha/{name=models/*}/deploy-config", middleware.AppendCustomHeaderMiddleware(service, handler.HandleSetModelDeployConfig)); err != nil {
+		panic(err)
+	}
+
+	// Register custom route for POST /v1alpha/admin/models/reconcile which reconciles the model states with Triton
+	if err := privateGwS.HandlePath("POST", "/v1alpha/admin/models/reconcile", middleware.AppendCustomHeaderMiddleware(service, handler.HandleReconcileModels)); err != nil {
+		panic(err)
+	}
+
+	// Register custom route for POST /v1alpha/admin/models/gc which removes the model files no longer owned by any model
+	if err := privateGwS.HandlePath("POST", "/v1alpha/admin/models/gc", middleware.AppendCustomHeaderMiddleware(service, handler.HandleCollectGarbage)); err != nil {
+		panic(err)
+	}
+
+	// Register custom routes for the model artifact cache, GET lists its entries and DELETE evicts one or every entry
+	if err := privateGwS.HandlePath("GET", "/v1alpha/admin/cache", middleware.AppendCustomHeaderMiddleware(service, handler.HandleListModelCache)); err != nil {
+		panic(err)
+	}
+	if err := privateGwS.HandlePath("DELETE", "/v1alpha/admin/cache", middleware.AppendCustomHeaderMiddleware(service, handler.HandleEvictModelCache)); err != nil {
+		panic(err)
+	}
+	if err := privateGwS.HandlePath("DELETE", "/v1alpha/admin/cache/{key}", middleware.AppendCustomHeaderMiddleware(service, handler.HandleEvictModelCache)); err != nil {
+		panic(err)
+	}
+
 	// Start usage reporter
 	var usg usage.Usage
 	if config.Config.Server.Usage.Enabled {
-		usageServiceClient, usageServiceClientConn := external.InitUsageServiceClient(ctx)
-		defer usageServiceClientConn.Close()
 		logger.Info("try to start usage reporter")
 		go func() {
 			for {
@@ -262,6 +376,66 @@ func main() {
 		}()
 	}
 
+	// Start unloading the models idle for longer than their idle timeout
+	if config.Config.IdleUndeploy.Enabled {
+		go func() {
+			ticker := time.NewTicker(config.Config.IdleUndeploy.CheckInterval)
+			defer ticker.Stop()
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case <-ticker.C:
+					if err := service.UnloadIdleModels(ctx); err != nil {
+						logger.Error(fmt.Sprintf("unable to unload idle models: %s", err))
+					}
+				}
+			}
+		}()
+	}
+
+	// Start writing the times of the latest inferences to redis in batches
+	flushInterval := config.Config.IdleUndeploy.TriggerTimeFlushInterval
+	if flushInterval <= 0 {
+		flushInterval = 15 * time.Second
+	}
+	go func() {
+		ticker := time.NewTicker(flushInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if err := service.FlushModelTriggerTimes(ctx); err != nil {
+					logger.Error(fmt.Sprintf("unable to write the last trigger times: %s", err))
+				}
+			}
+		}
+	}()
+
+	// Start removing the expired upload sessions
+	cleanupInterval := config.Config.Upload.CleanupInterval
+	if cleanupInterval <= 0 {
+		cleanupInterval = 10 * time.Minute
+	}
+	go func() {
+		ticker := time.NewTicker(cleanupInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if removed, err := util.CleanupUploadSessions(); err != nil {
+					logger.Error(fmt.Sprintf("unable to remove the expired upload sessions: %s", err))
+				} else if removed > 0 {
+					logger.Info(fmt.Sprintf("removed %d expired upload session(s)", removed))
+				}
+			}
+		}
+	}()
+
 	var dialOpts []grpc.DialOption
 	if config.Config.Server.HTTPS.Cert != "" && config.Config.Server.HTTPS.Key != "" {
 		dialOpts = []grpc.DialOption{grpc.WithTransportCredentials(creds)}
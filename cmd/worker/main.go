@@ -4,15 +4,19 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"time"
 
 	"go.opentelemetry.io/otel"
+	"go.temporal.io/sdk/activity"
 	"go.temporal.io/sdk/client"
 	"go.temporal.io/sdk/worker"
+	"go.temporal.io/sdk/workflow"
 
 	"github.com/instill-ai/model-backend/config"
 	"github.com/instill-ai/model-backend/pkg/external"
 	"github.com/instill-ai/model-backend/pkg/logger"
 	"github.com/instill-ai/model-backend/pkg/repository"
+	"github.com/instill-ai/model-backend/pkg/service"
 	"github.com/instill-ai/model-backend/pkg/triton"
 	"github.com/instill-ai/x/temporal"
 	"github.com/instill-ai/x/zapadapter"
@@ -66,8 +70,6 @@ func main() {
 	controllerClient, controllerClientConn := external.InitControllerPrivateServiceClient(ctx)
 	defer controllerClientConn.Close()
 
-	cw := modelWorker.NewWorker(repository.NewRepository(db), triton, controllerClient)
-
 	var temporalClientOptions client.Options
 	var err error
 	if config.Config.Temporal.Ca != "" && config.Config.Temporal.Cert != "" && config.Config.Temporal.Key != "" {
@@ -98,17 +100,83 @@ func main() {
 	}
 	defer temporalClient.Close()
 
-	w := worker.New(temporalClient, modelWorker.TaskQueue, worker.Options{})
+	// The operations are listed by owner and model through their search attributes
+	if err := modelWorker.RegisterSearchAttributes(ctx, temporalClient, config.Config.Temporal.Namespace); err != nil {
+		logger.Error(fmt.Sprintf("Unable to register the search attributes: %s", err))
+	}
+
+	// Remove what the activities of a previous worker process left behind before taking new ones
+	if err := modelWorker.CleanupStaleStagingDirs(ctx, temporalClient, logger); err != nil {
+		logger.Error(fmt.Sprintf("Unable to clean up the stale staging folders: %s", err))
+	}
+
+	// The bulk and scheduled deletes run the checks and the clean-up of the service
+	repository := repository.NewRepository(db)
+	service := service.NewService(repository, triton, nil, nil, temporalClient, controllerClient, nil)
+	cw := modelWorker.NewWorker(repository, triton, controllerClient, service)
+
+	// The sessions pin the fetch and the stage of a deployment to the host of the staging folder
+	w := worker.New(temporalClient, modelWorker.TaskQueue, worker.Options{
+		MaxConcurrentActivityExecutionSize: config.Config.Worker.MaxConcurrentActivities,
+		WorkerStopTimeout:                  config.Config.Worker.StopTimeout,
+		EnableSessionWorker:                true,
+	})
 
 	w.RegisterWorkflow(cw.DeployModelWorkflow)
 	w.RegisterActivity(cw.DeployModelActivity)
+	w.RegisterActivity(cw.CheckCompatibilityActivity)
+	w.RegisterActivity(cw.FetchModelActivity)
+	w.RegisterActivity(cw.StageModelActivity)
+	w.RegisterActivity(cw.ValidateModelActivity)
+	w.RegisterActivity(cw.LoadModelActivity)
+	w.RegisterActivity(cw.WarmupModelActivity)
+	w.RegisterActivity(cw.PublishModelStateActivity)
+	w.RegisterActivity(cw.CleanupDeployActivity)
 	w.RegisterWorkflow(cw.UnDeployModelWorkflow)
 	w.RegisterActivity(cw.UnDeployModelActivity)
 	w.RegisterWorkflow(cw.CreateModelWorkflow)
+	w.RegisterActivity(cw.CleanupCreateActivity)
+	w.RegisterWorkflow(cw.DeleteModelWorkflow)
+	w.RegisterWorkflow(cw.ModelOperationWorkflow)
+	w.RegisterActivity(cw.PrepareModelOperationActivity)
+	// The schedules created before the scheduled operations became model operations start them by their former names
+	w.RegisterWorkflowWithOptions(cw.ModelOperationWorkflow, workflow.RegisterOptions{Name: "ScheduledOperationWorkflow"})
+	w.RegisterActivityWithOptions(cw.PrepareModelOperationActivity, activity.RegisterOptions{Name: "PrepareScheduledOperationActivity"})
+	w.RegisterActivity(cw.ModelActionActivity)
+	w.RegisterWorkflow(cw.BulkModelWorkflow)
+	w.RegisterWorkflow(cw.ReconcileModelsWorkflow)
+	w.RegisterActivity(cw.ReconcileModelsActivity)
+
+	// Start the reconciliation cron workflow, or restart it when its settings changed
+	if err := modelWorker.StartReconcileWorkflow(ctx, temporalClient); err != nil {
+		logger.Error(fmt.Sprintf("Unable to start the reconciliation workflow: %s", err))
+	}
+
+	// Start removing the stale temporary files of this host, the API hosts do not see them
+	if interval := config.Config.GarbageCollector.Interval; interval > 0 {
+		go func() {
+			ticker := time.NewTicker(interval)
+			defer ticker.Stop()
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case <-ticker.C:
+					modelWorker.CollectStaleTmpEntries(logger)
+				}
+			}
+		}()
+	}
 
 	span.End()
-	if err := w.Run(worker.InterruptCh()); err != nil {
+	if err := w.Start(); err != nil {
 		logger.Fatal(fmt.Sprintf("Unable to start worker: %s", err))
 	}
 
+	// Stop polling on SIGINT or SIGTERM and wait for the in-flight activities to finish, up to the stop timeout
+	<-worker.InterruptCh()
+	logger.Info("Shutting down the worker, draining the in-flight activities")
+	w.Stop()
+	logger.Info("Worker stopped")
+
 }
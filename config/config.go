@@ -54,8 +54,9 @@ type DatabaseConfig struct {
 
 // TritonServerConfig related to Triton server
 type TritonServerConfig struct {
-	GrpcURI    string `koanf:"grpcuri"`
-	ModelStore string `koanf:"modelstore"`
+	GrpcURI    string   `koanf:"grpcuri"`
+	ModelStore string   `koanf:"modelstore"`
+	Backends   []string `koanf:"backends"`
 }
 
 // MgmtBackendConfig related to mgmt-backend
@@ -68,12 +69,14 @@ type MgmtBackendConfig struct {
 	}
 }
 
-// CacheConfig related to Redis
+// CacheConfig related to Redis and the model artifact cache, whose directory is shared by the workers of a host
 type CacheConfig struct {
 	Redis struct {
 		RedisOptions redis.Options `koanf:"redisoptions"`
 	}
-	Model bool `koanf:"model"`
+	Model          bool   `koanf:"model"`
+	ModelDir       string `koanf:"modeldir"`
+	ModelSizeLimit int64  `koanf:"modelsizelimit"`
 }
 
 // ControllerConfig related to controller
@@ -113,6 +116,97 @@ type TemporalConfig struct {
 	ServerName string `koanf:"servername"`
 }
 
+// ReconcilerConfig related to the periodic reconciliation between model states and Triton
+type ReconcilerConfig struct {
+	Enabled     bool          `koanf:"enabled"`
+	Schedule    string        `koanf:"schedule"`
+	DryRun      bool          `koanf:"dryrun"`
+	GracePeriod time.Duration `koanf:"graceperiod"`
+}
+
+// GarbageCollectorConfig related to the cleanup of orphaned model files
+type GarbageCollectorConfig struct {
+	GracePeriod time.Duration `koanf:"graceperiod"`
+	Interval    time.Duration `koanf:"interval"`
+}
+
+// WorkerConfig related to the Temporal workers running the model operations
+type WorkerConfig struct {
+	MaxConcurrentActivities int           `koanf:"maxconcurrentactivities"`
+	StopTimeout             time.Duration `koanf:"stoptimeout"`
+}
+
+// WarmupConfig related to the inference run on the deployed models before they are online
+type WarmupConfig struct {
+	Enabled   bool   `koanf:"enabled"`
+	SampleDir string `koanf:"sampledir"`
+}
+
+// IdleUndeployConfig related to unloading idle models from Triton
+type IdleUndeployConfig struct {
+	Enabled                  bool          `koanf:"enabled"`
+	CheckInterval            time.Duration `koanf:"checkinterval"`
+	ColdStartTimeout         time.Duration `koanf:"coldstarttimeout"`
+	TriggerTimeFlushInterval time.Duration `koanf:"triggertimeflushinterval"`
+}
+
+// UploadConfig related to the resumable model upload sessions, which are stored on the API server handling them
+type UploadConfig struct {
+	SessionTTL      time.Duration `koanf:"sessionttl"`
+	CleanupInterval time.Duration `koanf:"cleanupinterval"`
+	MaxSize         int64         `koanf:"maxsize"`
+	MaxChunkSize    int64         `koanf:"maxchunksize"`
+}
+
+// S3Config related to the S3 compatible object storages models are imported from
+type S3Config struct {
+	Credentials map[string]S3CredentialConfig `koanf:"credentials"`
+}
+
+// S3CredentialConfig is an access key referenced by name from the s3 model configurations
+type S3CredentialConfig struct {
+	AccessKeyID     string `koanf:"accesskeyid"`
+	SecretAccessKey string `koanf:"secretaccesskey"`
+	SessionToken    string `koanf:"sessiontoken"`
+}
+
+// ArchiveConfig related to the HTTP(S) servers model archives are downloaded from
+type ArchiveConfig struct {
+	Credentials map[string]ArchiveCredentialConfig `koanf:"credentials"`
+}
+
+// ArchiveCredentialConfig holds the HTTP headers, e.g. Authorization, sent to an archive server, referenced by name
+// from the url model configurations
+type ArchiveCredentialConfig struct {
+	Headers map[string]string `koanf:"headers"`
+}
+
+// GitConfig related to the Git hosts models are cloned from
+type GitConfig struct {
+	Credentials map[string]GitCredentialConfig `koanf:"credentials"`
+}
+
+// GitCredentialConfig is an access token for HTTP(S) remotes or a deploy key for SSH remotes, referenced by name
+// from the github and git model configurations
+type GitCredentialConfig struct {
+	Username         string `koanf:"username"`
+	Token            string `koanf:"token"`
+	SSHKey           string `koanf:"sshkey"`
+	SSHKeyPassphrase string `koanf:"sshkeypassphrase"`
+	KnownHostsFile   string `koanf:"knownhostsfile"`
+}
+
+// HuggingFaceConfig related to the Hugging Face Hub models are downloaded from
+type HuggingFaceConfig struct {
+	Credentials map[string]HuggingFaceCredentialConfig `koanf:"credentials"`
+}
+
+// HuggingFaceCredentialConfig is an access token for private and gated repositories, referenced by name from the
+// huggingface model configurations
+type HuggingFaceCredentialConfig struct {
+	Token string `koanf:"token"`
+}
+
 // LogConfig related to logging
 type LogConfig struct {
 	External      bool `koanf:"external"`
@@ -133,6 +227,16 @@ type AppConfig struct {
 	Temporal               TemporalConfig        `koanf:"temporal"`
 	Controller             ControllerConfig      `koanf:"controller"`
 	InitModel              InitModelConfig       `koanf:"initmodel"`
+	Reconciler             ReconcilerConfig      `koanf:"reconciler"`
+	GarbageCollector       GarbageCollectorConfig `koanf:"garbagecollector"`
+	IdleUndeploy           IdleUndeployConfig     `koanf:"idleundeploy"`
+	Worker                 WorkerConfig           `koanf:"worker"`
+	Warmup                 WarmupConfig           `koanf:"warmup"`
+	S3                     S3Config               `koanf:"s3"`
+	Archive                ArchiveConfig          `koanf:"archive"`
+	Git                    GitConfig              `koanf:"git"`
+	HuggingFace            HuggingFaceConfig      `koanf:"huggingface"`
+	Upload                 UploadConfig           `koanf:"upload"`
 	Log                    LogConfig             `koanf:"log"`
 }
 
@@ -6,3 +6,13 @@ const HeaderOwnerUIDKey = "jwt-sub"
 const HeaderOwnerIDKey = "owner-id"
 const HeaderAuthorization = "Authorization"
 const AccessTokenKeyFormat = "access_token:%s:owner_permalink"
+
+// ModelLastTriggerTimeKeyFormat is the Redis key holding the Unix time of the latest inference of a model
+const ModelLastTriggerTimeKeyFormat = "model:%s:last_trigger_time"
+
+// ModelScalingLockKeyFormat is the Redis key of the lock held while a model is loaded back or unloaded
+const ModelScalingLockKeyFormat = "model:%s:scaling_lock"
+
+// Response metadata reporting that a scaled-to-zero model was loaded back to serve the request
+const HeaderModelColdStartKey = "x-model-cold-start"
+const HeaderModelColdStartDurationKey = "x-model-cold-start-duration-ms"
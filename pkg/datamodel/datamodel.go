@@ -90,6 +90,15 @@ type Model struct {
 	// Model state
 	State ModelState `json:"state,omitempty"`
 
+	// Minutes without inference after which a deployed model is unloaded from Triton, 0 disables it
+	IdleTimeout int `json:"idle_timeout,omitempty"`
+
+	// Whether the deployed model has been unloaded from Triton after being idle
+	ScaledToZero bool `json:"scaled_to_zero,omitempty"`
+
+	// Resource profile applied on top of the shipped Triton model configurations at deploy time
+	DeployConfig datatypes.JSON `json:"deploy_config,omitempty"`
+
 	// Not stored in DB, only used for processing
 	TritonModels []TritonModel `gorm:"foreignKey:ModelUID;references:UID;constraint:OnDelete:CASCADE;"`
 }
@@ -160,9 +169,16 @@ type ModelInferResult struct {
 
 // Model configuration
 type GitHubModelConfiguration struct {
-	Repository string `json:"repository,omitempty"`
-	Tag        string `json:"tag,omitempty"`
-	HtmlUrl    string `json:"html_url,omitempty"`
+	Repository    string `json:"repository,omitempty"`
+	Tag           string `json:"tag,omitempty"`
+	HtmlUrl       string `json:"html_url,omitempty"`
+	CredentialRef string `json:"credential_ref,omitempty"`
+}
+
+type GitModelConfiguration struct {
+	Url           string `json:"url,omitempty"`
+	Tag           string `json:"tag,omitempty"`
+	CredentialRef string `json:"credential_ref,omitempty"`
 }
 
 type ArtiVCModelConfiguration struct {
@@ -172,9 +188,30 @@ type ArtiVCModelConfiguration struct {
 }
 
 type HuggingFaceModelConfiguration struct {
-	RepoId  string `json:"repo_id,omitempty"`
-	Tag     string `json:"tag,omitempty"`
-	HtmlUrl string `json:"html_url,omitempty"`
+	RepoId        string   `json:"repo_id,omitempty"`
+	Tag           string   `json:"tag,omitempty"`
+	HtmlUrl       string   `json:"html_url,omitempty"`
+	Revision      string   `json:"revision,omitempty"`
+	Commit        string   `json:"commit,omitempty"`
+	CredentialRef string   `json:"credential_ref,omitempty"`
+	AllowPatterns []string `json:"allow_patterns,omitempty"`
+	Pipeline      string   `json:"pipeline,omitempty"`
+}
+
+type S3ModelConfiguration struct {
+	Endpoint      string `json:"endpoint,omitempty"`
+	Bucket        string `json:"bucket,omitempty"`
+	Prefix        string `json:"prefix,omitempty"`
+	Region        string `json:"region,omitempty"`
+	CredentialRef string `json:"credential_ref,omitempty"`
+	Tag           string `json:"tag,omitempty"`
+}
+
+type URLModelConfiguration struct {
+	Url           string `json:"url,omitempty"`
+	Checksum      string `json:"checksum,omitempty"`
+	CredentialRef string `json:"credential_ref,omitempty"`
+	Tag           string `json:"tag,omitempty"`
 }
 
 type LocalModelConfiguration struct {
@@ -182,6 +219,33 @@ type LocalModelConfiguration struct {
 	Tag     string `json:"tag,omitempty"`
 }
 
+// ModelDeployConfiguration overrides the resource settings of the shipped Triton model configurations,
+// unset fields keep the shipped values
+type ModelDeployConfiguration struct {
+	InstanceGroup   *ModelDeployInstanceGroup   `json:"instance_group,omitempty"`
+	MaxBatchSize    *int32                      `json:"max_batch_size,omitempty"`
+	DynamicBatching *ModelDeployDynamicBatching `json:"dynamic_batching,omitempty"`
+	Warmup          *ModelDeployWarmup          `json:"warmup,omitempty"`
+}
+
+type ModelDeployInstanceGroup struct {
+	Kind  string  `json:"kind"`
+	Count int32   `json:"count"`
+	Gpus  []int32 `json:"gpus,omitempty"`
+}
+
+type ModelDeployDynamicBatching struct {
+	PreferredBatchSize        []int32 `json:"preferred_batch_size,omitempty"`
+	MaxQueueDelayMicroseconds uint64  `json:"max_queue_delay_microseconds,omitempty"`
+}
+
+// ModelDeployWarmup turns the warm-up inference of a deployed model on or off, unset keeps the server default, and
+// names the sample input file in the folder of its ensemble Triton model, the bundled sample of its task when empty
+type ModelDeployWarmup struct {
+	Enabled *bool  `json:"enabled,omitempty"`
+	Sample  string `json:"sample,omitempty"`
+}
+
 type ListModelQuery struct {
 	Owner string
 }
@@ -22,6 +22,9 @@ var ModelJSONSchema *jsonschema.Schema
 // ModelCardJSONSchema represents the Model Instance Card JSON Schema for validating the payload
 var ModelCardJSONSchema *jsonschema.Schema
 
+// ModelDeployConfigJSONSchema represents the Model deploy configuration JSON Schema for validating the payload
+var ModelDeployConfigJSONSchema *jsonschema.Schema
+
 // GCSUserAccountJSONSchema represents the GCS User Account JSON Schema for validating the payload
 var GCSUserAccountJSONSchema *jsonschema.Schema
 
@@ -67,6 +70,14 @@ func InitJSONSchema(ctx context.Context) {
 		}
 	}
 
+	if r, err := os.Open("config/model/model_deploy_config.json"); err != nil {
+		logger.Fatal(fmt.Sprintf("%#v\n", err.Error()))
+	} else {
+		if err := compiler.AddResource("https://github.com/instill-ai/model-backend/blob/main/config/model/model_deploy_config.json", r); err != nil {
+			logger.Fatal(fmt.Sprintf("%#v\n", err.Error()))
+		}
+	}
+
 	if r, err := os.Open("config/credential/gcs_user_account.json"); err != nil {
 		logger.Fatal(fmt.Sprintf("%#v\n", err.Error()))
 	} else {
@@ -99,6 +110,11 @@ func InitJSONSchema(ctx context.Context) {
 		logger.Fatal(fmt.Sprintf("%#v\n", err.Error()))
 	}
 
+	ModelDeployConfigJSONSchema, err = compiler.Compile("config/model/model_deploy_config.json")
+	if err != nil {
+		logger.Fatal(fmt.Sprintf("%#v\n", err.Error()))
+	}
+
 	GCSUserAccountJSONSchema, err = compiler.Compile("config/credential/gcs_user_account.json")
 	if err != nil {
 		logger.Fatal(fmt.Sprintf("%#v\n", err.Error()))
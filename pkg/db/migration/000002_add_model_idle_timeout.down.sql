@@ -0,0 +1,6 @@
+BEGIN;
+
+ALTER TABLE "model" DROP COLUMN IF EXISTS "scaled_to_zero";
+ALTER TABLE "model" DROP COLUMN IF EXISTS "idle_timeout";
+
+COMMIT;
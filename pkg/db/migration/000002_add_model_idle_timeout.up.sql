@@ -0,0 +1,6 @@
+BEGIN;
+
+ALTER TABLE "model" ADD COLUMN IF NOT EXISTS "idle_timeout" INT DEFAULT 0 NOT NULL;
+ALTER TABLE "model" ADD COLUMN IF NOT EXISTS "scaled_to_zero" BOOLEAN DEFAULT FALSE NOT NULL;
+
+COMMIT;
@@ -0,0 +1,5 @@
+BEGIN;
+
+ALTER TABLE "model" DROP COLUMN IF EXISTS "deploy_config";
+
+COMMIT;
@@ -0,0 +1,5 @@
+BEGIN;
+
+ALTER TABLE "model" ADD COLUMN IF NOT EXISTS "deploy_config" JSONB NULL;
+
+COMMIT;
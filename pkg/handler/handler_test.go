@@ -15,7 +15,7 @@ const NAMESPACE = "instill-ai"
 
 // 		mockTriton.
 // 			EXPECT().
-// 			IsTritonServerReady().
+// 			IsTritonServerReady(gomock.Any()).
 // 			Return(true)
 
 // 		ctx, cancel := context.WithTimeout(context.Background(), time.Second*1000)
@@ -36,7 +36,7 @@ const NAMESPACE = "instill-ai"
 
 // 		mockTriton.
 // 			EXPECT().
-// 			IsTritonServerReady().
+// 			IsTritonServerReady(gomock.Any()).
 // 			Return(true)
 
 // 		ctx, cancel := context.WithTimeout(context.Background(), time.Second*1000)
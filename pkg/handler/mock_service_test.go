@@ -5,14 +5,18 @@
 package handler_test
 
 import (
+	context "context"
 	reflect "reflect"
 
 	longrunningpb "cloud.google.com/go/longrunning/autogen/longrunningpb"
+	redis "github.com/go-redis/redis/v9"
 	uuid "github.com/gofrs/uuid"
 	gomock "github.com/golang/mock/gomock"
 	datamodel "github.com/instill-ai/model-backend/pkg/datamodel"
+	repository "github.com/instill-ai/model-backend/pkg/repository"
 	service "github.com/instill-ai/model-backend/pkg/service"
-	controllerv1alpha "github.com/instill-ai/protogen-go/model/controller/v1alpha"
+	worker "github.com/instill-ai/model-backend/pkg/worker"
+	mgmtv1alpha "github.com/instill-ai/protogen-go/base/mgmt/v1alpha"
 	modelv1alpha "github.com/instill-ai/protogen-go/model/model/v1alpha"
 )
 
@@ -39,233 +43,424 @@ func (m *MockService) EXPECT() *MockServiceMockRecorder {
 	return m.recorder
 }
 
+// BulkModelOperation mocks base method.
+func (m *MockService) BulkModelOperation(arg0 context.Context, arg1, arg2 string, arg3 service.BulkModelSelector, arg4 int) (*longrunningpb.Operation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BulkModelOperation", arg0, arg1, arg2, arg3, arg4)
+	ret0, _ := ret[0].(*longrunningpb.Operation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// BulkModelOperation indicates an expected call of BulkModelOperation.
+func (mr *MockServiceMockRecorder) BulkModelOperation(arg0, arg1, arg2, arg3, arg4 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BulkModelOperation", reflect.TypeOf((*MockService)(nil).BulkModelOperation), arg0, arg1, arg2, arg3, arg4)
+}
+
+// CancelOperation mocks base method.
+func (m *MockService) CancelOperation(arg0 context.Context, arg1, arg2 string) (*longrunningpb.Operation, *datamodel.Model, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CancelOperation", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*longrunningpb.Operation)
+	ret1, _ := ret[1].(*datamodel.Model)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// CancelOperation indicates an expected call of CancelOperation.
+func (mr *MockServiceMockRecorder) CancelOperation(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CancelOperation", reflect.TypeOf((*MockService)(nil).CancelOperation), arg0, arg1, arg2)
+}
+
 // CheckModel mocks base method.
-func (m *MockService) CheckModel(arg0 uuid.UUID) (*modelv1alpha.Model_State, error) {
+func (m *MockService) CheckModel(arg0 context.Context, arg1 uuid.UUID) (*modelv1alpha.Model_State, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CheckModel", arg0)
+	ret := m.ctrl.Call(m, "CheckModel", arg0, arg1)
 	ret0, _ := ret[0].(*modelv1alpha.Model_State)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // CheckModel indicates an expected call of CheckModel.
-func (mr *MockServiceMockRecorder) CheckModel(arg0 interface{}) *gomock.Call {
+func (mr *MockServiceMockRecorder) CheckModel(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckModel", reflect.TypeOf((*MockService)(nil).CheckModel), arg0, arg1)
+}
+
+// CheckReadiness mocks base method.
+func (m *MockService) CheckReadiness(arg0 context.Context) service.ReadinessReport {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CheckReadiness", arg0)
+	ret0, _ := ret[0].(service.ReadinessReport)
+	return ret0
+}
+
+// CheckReadiness indicates an expected call of CheckReadiness.
+func (mr *MockServiceMockRecorder) CheckReadiness(arg0 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckModel", reflect.TypeOf((*MockService)(nil).CheckModel), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckReadiness", reflect.TypeOf((*MockService)(nil).CheckReadiness), arg0)
+}
+
+// CollectGarbage mocks base method.
+func (m *MockService) CollectGarbage(arg0 context.Context, arg1 bool) (*service.GarbageReport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CollectGarbage", arg0, arg1)
+	ret0, _ := ret[0].(*service.GarbageReport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CollectGarbage indicates an expected call of CollectGarbage.
+func (mr *MockServiceMockRecorder) CollectGarbage(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CollectGarbage", reflect.TypeOf((*MockService)(nil).CollectGarbage), arg0, arg1)
 }
 
 // CreateModelAsync mocks base method.
-func (m *MockService) CreateModelAsync(arg0 string, arg1 *datamodel.Model) (string, error) {
+func (m *MockService) CreateModelAsync(arg0 context.Context, arg1 string, arg2 *datamodel.Model) (string, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateModelAsync", arg0, arg1)
+	ret := m.ctrl.Call(m, "CreateModelAsync", arg0, arg1, arg2)
 	ret0, _ := ret[0].(string)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // CreateModelAsync indicates an expected call of CreateModelAsync.
-func (mr *MockServiceMockRecorder) CreateModelAsync(arg0, arg1 interface{}) *gomock.Call {
+func (mr *MockServiceMockRecorder) CreateModelAsync(arg0, arg1, arg2 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateModelAsync", reflect.TypeOf((*MockService)(nil).CreateModelAsync), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateModelAsync", reflect.TypeOf((*MockService)(nil).CreateModelAsync), arg0, arg1, arg2)
+}
+
+// CreateModelSchedule mocks base method.
+func (m *MockService) CreateModelSchedule(arg0 context.Context, arg1, arg2, arg3, arg4, arg5 string) (*service.ModelSchedule, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateModelSchedule", arg0, arg1, arg2, arg3, arg4, arg5)
+	ret0, _ := ret[0].(*service.ModelSchedule)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateModelSchedule indicates an expected call of CreateModelSchedule.
+func (mr *MockServiceMockRecorder) CreateModelSchedule(arg0, arg1, arg2, arg3, arg4, arg5 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateModelSchedule", reflect.TypeOf((*MockService)(nil).CreateModelSchedule), arg0, arg1, arg2, arg3, arg4, arg5)
 }
 
 // DeleteModel mocks base method.
-func (m *MockService) DeleteModel(arg0, arg1 string) error {
+func (m *MockService) DeleteModel(arg0 context.Context, arg1, arg2 string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeleteModel", arg0, arg1)
+	ret := m.ctrl.Call(m, "DeleteModel", arg0, arg1, arg2)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // DeleteModel indicates an expected call of DeleteModel.
-func (mr *MockServiceMockRecorder) DeleteModel(arg0, arg1 interface{}) *gomock.Call {
+func (mr *MockServiceMockRecorder) DeleteModel(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteModel", reflect.TypeOf((*MockService)(nil).DeleteModel), arg0, arg1, arg2)
+}
+
+// DeleteModelSchedule mocks base method.
+func (m *MockService) DeleteModelSchedule(arg0 context.Context, arg1, arg2 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteModelSchedule", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteModelSchedule indicates an expected call of DeleteModelSchedule.
+func (mr *MockServiceMockRecorder) DeleteModelSchedule(arg0, arg1, arg2 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteModel", reflect.TypeOf((*MockService)(nil).DeleteModel), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteModelSchedule", reflect.TypeOf((*MockService)(nil).DeleteModelSchedule), arg0, arg1, arg2)
 }
 
 // DeleteResourceState mocks base method.
-func (m *MockService) DeleteResourceState(arg0 string) error {
+func (m *MockService) DeleteResourceState(arg0 context.Context, arg1 uuid.UUID) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeleteResourceState", arg0)
+	ret := m.ctrl.Call(m, "DeleteResourceState", arg0, arg1)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // DeleteResourceState indicates an expected call of DeleteResourceState.
-func (mr *MockServiceMockRecorder) DeleteResourceState(arg0 interface{}) *gomock.Call {
+func (mr *MockServiceMockRecorder) DeleteResourceState(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteResourceState", reflect.TypeOf((*MockService)(nil).DeleteResourceState), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteResourceState", reflect.TypeOf((*MockService)(nil).DeleteResourceState), arg0, arg1)
 }
 
 // DeployModelAsync mocks base method.
-func (m *MockService) DeployModelAsync(arg0 string, arg1 uuid.UUID) (string, error) {
+func (m *MockService) DeployModelAsync(arg0 context.Context, arg1 string, arg2 uuid.UUID) (string, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeployModelAsync", arg0, arg1)
+	ret := m.ctrl.Call(m, "DeployModelAsync", arg0, arg1, arg2)
 	ret0, _ := ret[0].(string)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // DeployModelAsync indicates an expected call of DeployModelAsync.
-func (mr *MockServiceMockRecorder) DeployModelAsync(arg0, arg1 interface{}) *gomock.Call {
+func (mr *MockServiceMockRecorder) DeployModelAsync(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeployModelAsync", reflect.TypeOf((*MockService)(nil).DeployModelAsync), arg0, arg1, arg2)
+}
+
+// EnsureModelLoaded mocks base method.
+func (m *MockService) EnsureModelLoaded(arg0 context.Context, arg1 *datamodel.Model) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EnsureModelLoaded", arg0, arg1)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// EnsureModelLoaded indicates an expected call of EnsureModelLoaded.
+func (mr *MockServiceMockRecorder) EnsureModelLoaded(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EnsureModelLoaded", reflect.TypeOf((*MockService)(nil).EnsureModelLoaded), arg0, arg1)
+}
+
+// EvictModelCache mocks base method.
+func (m *MockService) EvictModelCache(arg0 context.Context, arg1 string) (*service.ModelCacheReport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EvictModelCache", arg0, arg1)
+	ret0, _ := ret[0].(*service.ModelCacheReport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// EvictModelCache indicates an expected call of EvictModelCache.
+func (mr *MockServiceMockRecorder) EvictModelCache(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeployModelAsync", reflect.TypeOf((*MockService)(nil).DeployModelAsync), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EvictModelCache", reflect.TypeOf((*MockService)(nil).EvictModelCache), arg0, arg1)
+}
+
+// FlushModelTriggerTimes mocks base method.
+func (m *MockService) FlushModelTriggerTimes(arg0 context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FlushModelTriggerTimes", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// FlushModelTriggerTimes indicates an expected call of FlushModelTriggerTimes.
+func (mr *MockServiceMockRecorder) FlushModelTriggerTimes(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FlushModelTriggerTimes", reflect.TypeOf((*MockService)(nil).FlushModelTriggerTimes), arg0)
+}
+
+// GetMgmtPrivateServiceClient mocks base method.
+func (m *MockService) GetMgmtPrivateServiceClient() mgmtv1alpha.MgmtPrivateServiceClient {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMgmtPrivateServiceClient")
+	ret0, _ := ret[0].(mgmtv1alpha.MgmtPrivateServiceClient)
+	return ret0
+}
+
+// GetMgmtPrivateServiceClient indicates an expected call of GetMgmtPrivateServiceClient.
+func (mr *MockServiceMockRecorder) GetMgmtPrivateServiceClient() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMgmtPrivateServiceClient", reflect.TypeOf((*MockService)(nil).GetMgmtPrivateServiceClient))
 }
 
 // GetModelByID mocks base method.
-func (m *MockService) GetModelByID(arg0, arg1 string, arg2 modelv1alpha.View) (datamodel.Model, error) {
+func (m *MockService) GetModelByID(arg0 context.Context, arg1, arg2 string, arg3 modelv1alpha.View) (datamodel.Model, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetModelByID", arg0, arg1, arg2)
+	ret := m.ctrl.Call(m, "GetModelByID", arg0, arg1, arg2, arg3)
 	ret0, _ := ret[0].(datamodel.Model)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetModelByID indicates an expected call of GetModelByID.
-func (mr *MockServiceMockRecorder) GetModelByID(arg0, arg1, arg2 interface{}) *gomock.Call {
+func (mr *MockServiceMockRecorder) GetModelByID(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetModelByID", reflect.TypeOf((*MockService)(nil).GetModelByID), arg0, arg1, arg2)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetModelByID", reflect.TypeOf((*MockService)(nil).GetModelByID), arg0, arg1, arg2, arg3)
 }
 
 // GetModelByIDAdmin mocks base method.
-func (m *MockService) GetModelByIDAdmin(arg0 string, arg1 modelv1alpha.View) (datamodel.Model, error) {
+func (m *MockService) GetModelByIDAdmin(arg0 context.Context, arg1 string, arg2 modelv1alpha.View) (datamodel.Model, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetModelByIDAdmin", arg0, arg1)
+	ret := m.ctrl.Call(m, "GetModelByIDAdmin", arg0, arg1, arg2)
 	ret0, _ := ret[0].(datamodel.Model)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetModelByIDAdmin indicates an expected call of GetModelByIDAdmin.
-func (mr *MockServiceMockRecorder) GetModelByIDAdmin(arg0, arg1 interface{}) *gomock.Call {
+func (mr *MockServiceMockRecorder) GetModelByIDAdmin(arg0, arg1, arg2 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetModelByIDAdmin", reflect.TypeOf((*MockService)(nil).GetModelByIDAdmin), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetModelByIDAdmin", reflect.TypeOf((*MockService)(nil).GetModelByIDAdmin), arg0, arg1, arg2)
 }
 
 // GetModelByUID mocks base method.
-func (m *MockService) GetModelByUID(arg0 string, arg1 uuid.UUID, arg2 modelv1alpha.View) (datamodel.Model, error) {
+func (m *MockService) GetModelByUID(arg0 context.Context, arg1 string, arg2 uuid.UUID, arg3 modelv1alpha.View) (datamodel.Model, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetModelByUID", arg0, arg1, arg2)
+	ret := m.ctrl.Call(m, "GetModelByUID", arg0, arg1, arg2, arg3)
 	ret0, _ := ret[0].(datamodel.Model)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetModelByUID indicates an expected call of GetModelByUID.
-func (mr *MockServiceMockRecorder) GetModelByUID(arg0, arg1, arg2 interface{}) *gomock.Call {
+func (mr *MockServiceMockRecorder) GetModelByUID(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetModelByUID", reflect.TypeOf((*MockService)(nil).GetModelByUID), arg0, arg1, arg2)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetModelByUID", reflect.TypeOf((*MockService)(nil).GetModelByUID), arg0, arg1, arg2, arg3)
 }
 
 // GetModelByUIDAdmin mocks base method.
-func (m *MockService) GetModelByUIDAdmin(arg0 uuid.UUID, arg1 modelv1alpha.View) (datamodel.Model, error) {
+func (m *MockService) GetModelByUIDAdmin(arg0 context.Context, arg1 uuid.UUID, arg2 modelv1alpha.View) (datamodel.Model, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetModelByUIDAdmin", arg0, arg1)
+	ret := m.ctrl.Call(m, "GetModelByUIDAdmin", arg0, arg1, arg2)
 	ret0, _ := ret[0].(datamodel.Model)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetModelByUIDAdmin indicates an expected call of GetModelByUIDAdmin.
-func (mr *MockServiceMockRecorder) GetModelByUIDAdmin(arg0, arg1 interface{}) *gomock.Call {
+func (mr *MockServiceMockRecorder) GetModelByUIDAdmin(arg0, arg1, arg2 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetModelByUIDAdmin", reflect.TypeOf((*MockService)(nil).GetModelByUIDAdmin), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetModelByUIDAdmin", reflect.TypeOf((*MockService)(nil).GetModelByUIDAdmin), arg0, arg1, arg2)
 }
 
 // GetModelDefinition mocks base method.
-func (m *MockService) GetModelDefinition(arg0 string) (datamodel.ModelDefinition, error) {
+func (m *MockService) GetModelDefinition(arg0 context.Context, arg1 string) (datamodel.ModelDefinition, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetModelDefinition", arg0)
+	ret := m.ctrl.Call(m, "GetModelDefinition", arg0, arg1)
 	ret0, _ := ret[0].(datamodel.ModelDefinition)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetModelDefinition indicates an expected call of GetModelDefinition.
-func (mr *MockServiceMockRecorder) GetModelDefinition(arg0 interface{}) *gomock.Call {
+func (mr *MockServiceMockRecorder) GetModelDefinition(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetModelDefinition", reflect.TypeOf((*MockService)(nil).GetModelDefinition), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetModelDefinition", reflect.TypeOf((*MockService)(nil).GetModelDefinition), arg0, arg1)
 }
 
 // GetModelDefinitionByUID mocks base method.
-func (m *MockService) GetModelDefinitionByUID(arg0 uuid.UUID) (datamodel.ModelDefinition, error) {
+func (m *MockService) GetModelDefinitionByUID(arg0 context.Context, arg1 uuid.UUID) (datamodel.ModelDefinition, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetModelDefinitionByUID", arg0)
+	ret := m.ctrl.Call(m, "GetModelDefinitionByUID", arg0, arg1)
 	ret0, _ := ret[0].(datamodel.ModelDefinition)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetModelDefinitionByUID indicates an expected call of GetModelDefinitionByUID.
-func (mr *MockServiceMockRecorder) GetModelDefinitionByUID(arg0 interface{}) *gomock.Call {
+func (mr *MockServiceMockRecorder) GetModelDefinitionByUID(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetModelDefinitionByUID", reflect.TypeOf((*MockService)(nil).GetModelDefinitionByUID), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetModelDefinitionByUID", reflect.TypeOf((*MockService)(nil).GetModelDefinitionByUID), arg0, arg1)
 }
 
 // GetOperation mocks base method.
-func (m *MockService) GetOperation(arg0 string) (*longrunningpb.Operation, error) {
+func (m *MockService) GetOperation(arg0 context.Context, arg1, arg2 string) (*longrunningpb.Operation, *datamodel.Model, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetOperation", arg0)
+	ret := m.ctrl.Call(m, "GetOperation", arg0, arg1, arg2)
 	ret0, _ := ret[0].(*longrunningpb.Operation)
-	ret1, _ := ret[1].(error)
-	return ret0, ret1
+	ret1, _ := ret[1].(*datamodel.Model)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
 }
 
 // GetOperation indicates an expected call of GetOperation.
-func (mr *MockServiceMockRecorder) GetOperation(arg0 interface{}) *gomock.Call {
+func (mr *MockServiceMockRecorder) GetOperation(arg0, arg1, arg2 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOperation", reflect.TypeOf((*MockService)(nil).GetOperation), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOperation", reflect.TypeOf((*MockService)(nil).GetOperation), arg0, arg1, arg2)
+}
+
+// GetRedisClient mocks base method.
+func (m *MockService) GetRedisClient() *redis.Client {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRedisClient")
+	ret0, _ := ret[0].(*redis.Client)
+	return ret0
+}
+
+// GetRedisClient indicates an expected call of GetRedisClient.
+func (mr *MockServiceMockRecorder) GetRedisClient() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRedisClient", reflect.TypeOf((*MockService)(nil).GetRedisClient))
+}
+
+// GetRepository mocks base method.
+func (m *MockService) GetRepository() repository.Repository {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRepository")
+	ret0, _ := ret[0].(repository.Repository)
+	return ret0
+}
+
+// GetRepository indicates an expected call of GetRepository.
+func (mr *MockServiceMockRecorder) GetRepository() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRepository", reflect.TypeOf((*MockService)(nil).GetRepository))
 }
 
 // GetResourceState mocks base method.
-func (m *MockService) GetResourceState(arg0 string) (*modelv1alpha.Model_State, error) {
+func (m *MockService) GetResourceState(arg0 context.Context, arg1 uuid.UUID) (*modelv1alpha.Model_State, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetResourceState", arg0)
+	ret := m.ctrl.Call(m, "GetResourceState", arg0, arg1)
 	ret0, _ := ret[0].(*modelv1alpha.Model_State)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetResourceState indicates an expected call of GetResourceState.
-func (mr *MockServiceMockRecorder) GetResourceState(arg0 interface{}) *gomock.Call {
+func (mr *MockServiceMockRecorder) GetResourceState(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetResourceState", reflect.TypeOf((*MockService)(nil).GetResourceState), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetResourceState", reflect.TypeOf((*MockService)(nil).GetResourceState), arg0, arg1)
 }
 
 // GetTritonEnsembleModel mocks base method.
-func (m *MockService) GetTritonEnsembleModel(arg0 uuid.UUID) (datamodel.TritonModel, error) {
+func (m *MockService) GetTritonEnsembleModel(arg0 context.Context, arg1 uuid.UUID) (datamodel.TritonModel, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetTritonEnsembleModel", arg0)
+	ret := m.ctrl.Call(m, "GetTritonEnsembleModel", arg0, arg1)
 	ret0, _ := ret[0].(datamodel.TritonModel)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetTritonEnsembleModel indicates an expected call of GetTritonEnsembleModel.
-func (mr *MockServiceMockRecorder) GetTritonEnsembleModel(arg0 interface{}) *gomock.Call {
+func (mr *MockServiceMockRecorder) GetTritonEnsembleModel(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTritonEnsembleModel", reflect.TypeOf((*MockService)(nil).GetTritonEnsembleModel), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTritonEnsembleModel", reflect.TypeOf((*MockService)(nil).GetTritonEnsembleModel), arg0, arg1)
 }
 
 // GetTritonModels mocks base method.
-func (m *MockService) GetTritonModels(arg0 uuid.UUID) ([]datamodel.TritonModel, error) {
+func (m *MockService) GetTritonModels(arg0 context.Context, arg1 uuid.UUID) ([]datamodel.TritonModel, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetTritonModels", arg0)
+	ret := m.ctrl.Call(m, "GetTritonModels", arg0, arg1)
 	ret0, _ := ret[0].([]datamodel.TritonModel)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetTritonModels indicates an expected call of GetTritonModels.
-func (mr *MockServiceMockRecorder) GetTritonModels(arg0 interface{}) *gomock.Call {
+func (mr *MockServiceMockRecorder) GetTritonModels(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTritonModels", reflect.TypeOf((*MockService)(nil).GetTritonModels), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTritonModels", reflect.TypeOf((*MockService)(nil).GetTritonModels), arg0, arg1)
+}
+
+// ListModelCache mocks base method.
+func (m *MockService) ListModelCache(arg0 context.Context) (*service.ModelCacheReport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListModelCache", arg0)
+	ret0, _ := ret[0].(*service.ModelCacheReport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListModelCache indicates an expected call of ListModelCache.
+func (mr *MockServiceMockRecorder) ListModelCache(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListModelCache", reflect.TypeOf((*MockService)(nil).ListModelCache), arg0)
 }
 
 // ListModelDefinitions mocks base method.
-func (m *MockService) ListModelDefinitions(arg0 modelv1alpha.View, arg1 int, arg2 string) ([]datamodel.ModelDefinition, string, int64, error) {
+func (m *MockService) ListModelDefinitions(arg0 context.Context, arg1 modelv1alpha.View, arg2 int, arg3 string) ([]datamodel.ModelDefinition, string, int64, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ListModelDefinitions", arg0, arg1, arg2)
+	ret := m.ctrl.Call(m, "ListModelDefinitions", arg0, arg1, arg2, arg3)
 	ret0, _ := ret[0].([]datamodel.ModelDefinition)
 	ret1, _ := ret[1].(string)
 	ret2, _ := ret[2].(int64)
@@ -274,15 +469,30 @@ func (m *MockService) ListModelDefinitions(arg0 modelv1alpha.View, arg1 int, arg
 }
 
 // ListModelDefinitions indicates an expected call of ListModelDefinitions.
-func (mr *MockServiceMockRecorder) ListModelDefinitions(arg0, arg1, arg2 interface{}) *gomock.Call {
+func (mr *MockServiceMockRecorder) ListModelDefinitions(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListModelDefinitions", reflect.TypeOf((*MockService)(nil).ListModelDefinitions), arg0, arg1, arg2, arg3)
+}
+
+// ListModelSchedules mocks base method.
+func (m *MockService) ListModelSchedules(arg0 context.Context, arg1, arg2 string) ([]service.ModelSchedule, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListModelSchedules", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]service.ModelSchedule)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListModelSchedules indicates an expected call of ListModelSchedules.
+func (mr *MockServiceMockRecorder) ListModelSchedules(arg0, arg1, arg2 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListModelDefinitions", reflect.TypeOf((*MockService)(nil).ListModelDefinitions), arg0, arg1, arg2)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListModelSchedules", reflect.TypeOf((*MockService)(nil).ListModelSchedules), arg0, arg1, arg2)
 }
 
 // ListModels mocks base method.
-func (m *MockService) ListModels(arg0 string, arg1 modelv1alpha.View, arg2 int, arg3 string) ([]datamodel.Model, string, int64, error) {
+func (m *MockService) ListModels(arg0 context.Context, arg1 string, arg2 modelv1alpha.View, arg3 int, arg4 string) ([]datamodel.Model, string, int64, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ListModels", arg0, arg1, arg2, arg3)
+	ret := m.ctrl.Call(m, "ListModels", arg0, arg1, arg2, arg3, arg4)
 	ret0, _ := ret[0].([]datamodel.Model)
 	ret1, _ := ret[1].(string)
 	ret2, _ := ret[2].(int64)
@@ -291,15 +501,15 @@ func (m *MockService) ListModels(arg0 string, arg1 modelv1alpha.View, arg2 int,
 }
 
 // ListModels indicates an expected call of ListModels.
-func (mr *MockServiceMockRecorder) ListModels(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+func (mr *MockServiceMockRecorder) ListModels(arg0, arg1, arg2, arg3, arg4 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListModels", reflect.TypeOf((*MockService)(nil).ListModels), arg0, arg1, arg2, arg3)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListModels", reflect.TypeOf((*MockService)(nil).ListModels), arg0, arg1, arg2, arg3, arg4)
 }
 
 // ListModelsAdmin mocks base method.
-func (m *MockService) ListModelsAdmin(arg0 modelv1alpha.View, arg1 int, arg2 string) ([]datamodel.Model, string, int64, error) {
+func (m *MockService) ListModelsAdmin(arg0 context.Context, arg1 modelv1alpha.View, arg2 int, arg3 string) ([]datamodel.Model, string, int64, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ListModelsAdmin", arg0, arg1, arg2)
+	ret := m.ctrl.Call(m, "ListModelsAdmin", arg0, arg1, arg2, arg3)
 	ret0, _ := ret[0].([]datamodel.Model)
 	ret1, _ := ret[1].(string)
 	ret2, _ := ret[2].(int64)
@@ -308,156 +518,231 @@ func (m *MockService) ListModelsAdmin(arg0 modelv1alpha.View, arg1 int, arg2 str
 }
 
 // ListModelsAdmin indicates an expected call of ListModelsAdmin.
-func (mr *MockServiceMockRecorder) ListModelsAdmin(arg0, arg1, arg2 interface{}) *gomock.Call {
+func (mr *MockServiceMockRecorder) ListModelsAdmin(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListModelsAdmin", reflect.TypeOf((*MockService)(nil).ListModelsAdmin), arg0, arg1, arg2)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListModelsAdmin", reflect.TypeOf((*MockService)(nil).ListModelsAdmin), arg0, arg1, arg2, arg3)
+}
+
+// ListOperations mocks base method.
+func (m *MockService) ListOperations(arg0 context.Context, arg1 service.OperationFilter, arg2 int, arg3 string) ([]service.ModelOperation, string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListOperations", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].([]service.ModelOperation)
+	ret1, _ := ret[1].(string)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ListOperations indicates an expected call of ListOperations.
+func (mr *MockServiceMockRecorder) ListOperations(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListOperations", reflect.TypeOf((*MockService)(nil).ListOperations), arg0, arg1, arg2, arg3)
 }
 
 // ModelInfer mocks base method.
-func (m *MockService) ModelInfer(arg0 uuid.UUID, arg1 service.InferInput, arg2 modelv1alpha.Model_Task) ([]*modelv1alpha.TaskOutput, error) {
+func (m *MockService) ModelInfer(arg0 context.Context, arg1 uuid.UUID, arg2 service.InferInput, arg3 modelv1alpha.Model_Task) ([]*modelv1alpha.TaskOutput, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ModelInfer", arg0, arg1, arg2)
+	ret := m.ctrl.Call(m, "ModelInfer", arg0, arg1, arg2, arg3)
 	ret0, _ := ret[0].([]*modelv1alpha.TaskOutput)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // ModelInfer indicates an expected call of ModelInfer.
-func (mr *MockServiceMockRecorder) ModelInfer(arg0, arg1, arg2 interface{}) *gomock.Call {
+func (mr *MockServiceMockRecorder) ModelInfer(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ModelInfer", reflect.TypeOf((*MockService)(nil).ModelInfer), arg0, arg1, arg2)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ModelInfer", reflect.TypeOf((*MockService)(nil).ModelInfer), arg0, arg1, arg2, arg3)
 }
 
 // ModelInferTestMode mocks base method.
-func (m *MockService) ModelInferTestMode(arg0 string, arg1 uuid.UUID, arg2 service.InferInput, arg3 modelv1alpha.Model_Task) ([]*modelv1alpha.TaskOutput, error) {
+func (m *MockService) ModelInferTestMode(arg0 context.Context, arg1 string, arg2 uuid.UUID, arg3 service.InferInput, arg4 modelv1alpha.Model_Task) ([]*modelv1alpha.TaskOutput, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ModelInferTestMode", arg0, arg1, arg2, arg3)
+	ret := m.ctrl.Call(m, "ModelInferTestMode", arg0, arg1, arg2, arg3, arg4)
 	ret0, _ := ret[0].([]*modelv1alpha.TaskOutput)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // ModelInferTestMode indicates an expected call of ModelInferTestMode.
-func (mr *MockServiceMockRecorder) ModelInferTestMode(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+func (mr *MockServiceMockRecorder) ModelInferTestMode(arg0, arg1, arg2, arg3, arg4 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ModelInferTestMode", reflect.TypeOf((*MockService)(nil).ModelInferTestMode), arg0, arg1, arg2, arg3, arg4)
+}
+
+// PauseModelSchedule mocks base method.
+func (m *MockService) PauseModelSchedule(arg0 context.Context, arg1, arg2 string, arg3 bool) (*service.ModelSchedule, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PauseModelSchedule", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(*service.ModelSchedule)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PauseModelSchedule indicates an expected call of PauseModelSchedule.
+func (mr *MockServiceMockRecorder) PauseModelSchedule(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ModelInferTestMode", reflect.TypeOf((*MockService)(nil).ModelInferTestMode), arg0, arg1, arg2, arg3)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PauseModelSchedule", reflect.TypeOf((*MockService)(nil).PauseModelSchedule), arg0, arg1, arg2, arg3)
 }
 
 // PublishModel mocks base method.
-func (m *MockService) PublishModel(arg0, arg1 string) (datamodel.Model, error) {
+func (m *MockService) PublishModel(arg0 context.Context, arg1, arg2 string) (datamodel.Model, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "PublishModel", arg0, arg1)
+	ret := m.ctrl.Call(m, "PublishModel", arg0, arg1, arg2)
 	ret0, _ := ret[0].(datamodel.Model)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // PublishModel indicates an expected call of PublishModel.
-func (mr *MockServiceMockRecorder) PublishModel(arg0, arg1 interface{}) *gomock.Call {
+func (mr *MockServiceMockRecorder) PublishModel(arg0, arg1, arg2 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PublishModel", reflect.TypeOf((*MockService)(nil).PublishModel), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PublishModel", reflect.TypeOf((*MockService)(nil).PublishModel), arg0, arg1, arg2)
+}
+
+// ReconcileModels mocks base method.
+func (m *MockService) ReconcileModels(arg0 context.Context, arg1 bool) (*worker.ReconcileReport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReconcileModels", arg0, arg1)
+	ret0, _ := ret[0].(*worker.ReconcileReport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReconcileModels indicates an expected call of ReconcileModels.
+func (mr *MockServiceMockRecorder) ReconcileModels(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReconcileModels", reflect.TypeOf((*MockService)(nil).ReconcileModels), arg0, arg1)
 }
 
 // RenameModel mocks base method.
-func (m *MockService) RenameModel(arg0, arg1, arg2 string) (datamodel.Model, error) {
+func (m *MockService) RenameModel(arg0 context.Context, arg1, arg2, arg3 string) (datamodel.Model, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "RenameModel", arg0, arg1, arg2)
+	ret := m.ctrl.Call(m, "RenameModel", arg0, arg1, arg2, arg3)
 	ret0, _ := ret[0].(datamodel.Model)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // RenameModel indicates an expected call of RenameModel.
-func (mr *MockServiceMockRecorder) RenameModel(arg0, arg1, arg2 interface{}) *gomock.Call {
+func (mr *MockServiceMockRecorder) RenameModel(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RenameModel", reflect.TypeOf((*MockService)(nil).RenameModel), arg0, arg1, arg2)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RenameModel", reflect.TypeOf((*MockService)(nil).RenameModel), arg0, arg1, arg2, arg3)
+}
+
+// SetModelDeployConfig mocks base method.
+func (m *MockService) SetModelDeployConfig(arg0 context.Context, arg1, arg2 string, arg3 datamodel.ModelDeployConfiguration) (datamodel.Model, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetModelDeployConfig", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(datamodel.Model)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetModelDeployConfig indicates an expected call of SetModelDeployConfig.
+func (mr *MockServiceMockRecorder) SetModelDeployConfig(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetModelDeployConfig", reflect.TypeOf((*MockService)(nil).SetModelDeployConfig), arg0, arg1, arg2, arg3)
+}
+
+// SetModelIdleTimeout mocks base method.
+func (m *MockService) SetModelIdleTimeout(arg0 context.Context, arg1, arg2 string, arg3 int) (datamodel.Model, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetModelIdleTimeout", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(datamodel.Model)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetModelIdleTimeout indicates an expected call of SetModelIdleTimeout.
+func (mr *MockServiceMockRecorder) SetModelIdleTimeout(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetModelIdleTimeout", reflect.TypeOf((*MockService)(nil).SetModelIdleTimeout), arg0, arg1, arg2, arg3)
 }
 
 // UndeployModelAsync mocks base method.
-func (m *MockService) UndeployModelAsync(arg0 string, arg1 uuid.UUID) (string, error) {
+func (m *MockService) UndeployModelAsync(arg0 context.Context, arg1 string, arg2 uuid.UUID) (string, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UndeployModelAsync", arg0, arg1)
+	ret := m.ctrl.Call(m, "UndeployModelAsync", arg0, arg1, arg2)
 	ret0, _ := ret[0].(string)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // UndeployModelAsync indicates an expected call of UndeployModelAsync.
-func (mr *MockServiceMockRecorder) UndeployModelAsync(arg0, arg1 interface{}) *gomock.Call {
+func (mr *MockServiceMockRecorder) UndeployModelAsync(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UndeployModelAsync", reflect.TypeOf((*MockService)(nil).UndeployModelAsync), arg0, arg1, arg2)
+}
+
+// UnloadIdleModels mocks base method.
+func (m *MockService) UnloadIdleModels(arg0 context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UnloadIdleModels", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UnloadIdleModels indicates an expected call of UnloadIdleModels.
+func (mr *MockServiceMockRecorder) UnloadIdleModels(arg0 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UndeployModelAsync", reflect.TypeOf((*MockService)(nil).UndeployModelAsync), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnloadIdleModels", reflect.TypeOf((*MockService)(nil).UnloadIdleModels), arg0)
 }
 
 // UnpublishModel mocks base method.
-func (m *MockService) UnpublishModel(arg0, arg1 string) (datamodel.Model, error) {
+func (m *MockService) UnpublishModel(arg0 context.Context, arg1, arg2 string) (datamodel.Model, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UnpublishModel", arg0, arg1)
+	ret := m.ctrl.Call(m, "UnpublishModel", arg0, arg1, arg2)
 	ret0, _ := ret[0].(datamodel.Model)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // UnpublishModel indicates an expected call of UnpublishModel.
-func (mr *MockServiceMockRecorder) UnpublishModel(arg0, arg1 interface{}) *gomock.Call {
+func (mr *MockServiceMockRecorder) UnpublishModel(arg0, arg1, arg2 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnpublishModel", reflect.TypeOf((*MockService)(nil).UnpublishModel), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnpublishModel", reflect.TypeOf((*MockService)(nil).UnpublishModel), arg0, arg1, arg2)
 }
 
 // UpdateModel mocks base method.
-func (m *MockService) UpdateModel(arg0 uuid.UUID, arg1 *datamodel.Model) (datamodel.Model, error) {
+func (m *MockService) UpdateModel(arg0 context.Context, arg1 uuid.UUID, arg2 *datamodel.Model) (datamodel.Model, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UpdateModel", arg0, arg1)
+	ret := m.ctrl.Call(m, "UpdateModel", arg0, arg1, arg2)
 	ret0, _ := ret[0].(datamodel.Model)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // UpdateModel indicates an expected call of UpdateModel.
-func (mr *MockServiceMockRecorder) UpdateModel(arg0, arg1 interface{}) *gomock.Call {
+func (mr *MockServiceMockRecorder) UpdateModel(arg0, arg1, arg2 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateModel", reflect.TypeOf((*MockService)(nil).UpdateModel), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateModel", reflect.TypeOf((*MockService)(nil).UpdateModel), arg0, arg1, arg2)
 }
 
 // UpdateModelState mocks base method.
-func (m *MockService) UpdateModelState(arg0 uuid.UUID, arg1 *datamodel.Model, arg2 datamodel.ModelState) (datamodel.Model, error) {
+func (m *MockService) UpdateModelState(arg0 context.Context, arg1 uuid.UUID, arg2 *datamodel.Model, arg3 datamodel.ModelState) (datamodel.Model, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UpdateModelState", arg0, arg1, arg2)
+	ret := m.ctrl.Call(m, "UpdateModelState", arg0, arg1, arg2, arg3)
 	ret0, _ := ret[0].(datamodel.Model)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // UpdateModelState indicates an expected call of UpdateModelState.
-func (mr *MockServiceMockRecorder) UpdateModelState(arg0, arg1, arg2 interface{}) *gomock.Call {
+func (mr *MockServiceMockRecorder) UpdateModelState(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateModelState", reflect.TypeOf((*MockService)(nil).UpdateModelState), arg0, arg1, arg2)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateModelState", reflect.TypeOf((*MockService)(nil).UpdateModelState), arg0, arg1, arg2, arg3)
 }
 
 // UpdateResourceState mocks base method.
-func (m *MockService) UpdateResourceState(arg0 string, arg1 modelv1alpha.Model_State, arg2 *int32, arg3 *string) error {
+func (m *MockService) UpdateResourceState(arg0 context.Context, arg1 uuid.UUID, arg2 modelv1alpha.Model_State, arg3 *int32, arg4 *string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UpdateResourceState", arg0, arg1, arg2, arg3)
+	ret := m.ctrl.Call(m, "UpdateResourceState", arg0, arg1, arg2, arg3, arg4)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // UpdateResourceState indicates an expected call of UpdateResourceState.
-func (mr *MockServiceMockRecorder) UpdateResourceState(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
-	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateResourceState", reflect.TypeOf((*MockService)(nil).UpdateResourceState), arg0, arg1, arg2, arg3)
-}
-
-// WatchModel mocks base method.
-func (m *MockService) WatchModel(arg0 string) (*controllerv1alpha.GetResourceResponse, error) {
-	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "WatchModel", arg0)
-	ret0, _ := ret[0].(*controllerv1alpha.GetResourceResponse)
-	ret1, _ := ret[1].(error)
-	return ret0, ret1
-}
-
-// WatchModel indicates an expected call of WatchModel.
-func (mr *MockServiceMockRecorder) WatchModel(arg0 interface{}) *gomock.Call {
+func (mr *MockServiceMockRecorder) UpdateResourceState(arg0, arg1, arg2, arg3, arg4 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WatchModel", reflect.TypeOf((*MockService)(nil).WatchModel), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateResourceState", reflect.TypeOf((*MockService)(nil).UpdateResourceState), arg0, arg1, arg2, arg3, arg4)
 }
@@ -0,0 +1,241 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"cloud.google.com/go/longrunning/autogen/longrunningpb"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/anypb"
+
+	"github.com/instill-ai/model-backend/internal/resource"
+	"github.com/instill-ai/model-backend/pkg/datamodel"
+	"github.com/instill-ai/model-backend/pkg/service"
+
+	modelPB "github.com/instill-ai/protogen-go/model/model/v1alpha"
+)
+
+// setOperationResponse sets the model a successful operation created or (un)deployed as its response, a bulk
+// operation already has its report as response
+func setOperationResponse(ctx context.Context, s service.Service, operation *longrunningpb.Operation, dbModel *datamodel.Model) error {
+	if !operation.Done || operation.Result != nil {
+		return nil
+	}
+	response := &anypb.Any{}
+	if dbModel != nil {
+		modelDef, err := s.GetModelDefinitionByUID(ctx, dbModel.ModelDefinitionUid)
+		if err != nil {
+			return err
+		}
+		if response, err = anypb.New(DBModelToPBModel(ctx, &modelDef, dbModel, dbModel.Owner)); err != nil {
+			return err
+		}
+	}
+	operation.Result = &longrunningpb.Operation_Response{Response: response}
+	return nil
+}
+
+func writeOperationError(w http.ResponseWriter, span trace.Span, err error) {
+	sta := status.Convert(err)
+	switch sta.Code() {
+	case codes.InvalidArgument:
+		makeJSONResponse(w, 400, "Parameter invalid", sta.Message())
+	case codes.NotFound:
+		makeJSONResponse(w, 404, "Not found", sta.Message())
+	case codes.FailedPrecondition, codes.Aborted:
+		makeJSONResponse(w, 409, "Operation Error", sta.Message())
+	default:
+		makeJSONResponse(w, 500, "Internal Error", sta.Message())
+	}
+	span.SetStatus(1, sta.Message())
+}
+
+func writeOperationMessage(w http.ResponseWriter, span trace.Span, m proto.Message) {
+	b, err := protojson.MarshalOptions{UseProtoNames: true, UseEnumNumbers: false, EmitUnpopulated: true}.Marshal(m)
+	if err != nil {
+		makeJSONResponse(w, 500, "Internal Error", err.Error())
+		span.SetStatus(1, err.Error())
+		return
+	}
+	w.Header().Add("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(b)
+}
+
+// listOperations writes a page of the operations selected by the filter and the page_size and page_token query
+// parameters
+func listOperations(s service.Service, w http.ResponseWriter, req *http.Request, span trace.Span, filter service.OperationFilter) {
+	ctx := req.Context()
+	pageSize := 10
+	if v := req.URL.Query().Get("page_size"); v != "" {
+		var err error
+		if pageSize, err = strconv.Atoi(v); err != nil || pageSize < 1 || pageSize > 100 {
+			makeJSONResponse(w, 400, "Parameter invalid", fmt.Sprintf("invalid page_size value: %s, it must be between 1 and 100", v))
+			span.SetStatus(1, "invalid page_size")
+			return
+		}
+	}
+
+	operations, nextPageToken, err := s.ListOperations(ctx, filter, pageSize, req.URL.Query().Get("page_token"))
+	if err != nil {
+		writeOperationError(w, span, err)
+		return
+	}
+	resp := &longrunningpb.ListOperationsResponse{
+		Operations:    []*longrunningpb.Operation{},
+		NextPageToken: nextPageToken,
+	}
+	for _, op := range operations {
+		if err := setOperationResponse(ctx, s, op.Operation, op.Model); err != nil {
+			writeOperationError(w, span, err)
+			return
+		}
+		resp.Operations = append(resp.Operations, op.Operation)
+	}
+	writeOperationMessage(w, span, resp)
+}
+
+// HandleListModelOperations is a custom handler that lists the operations of the models of the requester, newest
+// first, filtered by the model, kind (create, deploy, undeploy or bulk) and state (running, completed, failed, canceled,
+// terminated or timed_out) query parameters. A page may hold fewer operations than page_size.
+func HandleListModelOperations(s service.Service, w http.ResponseWriter, req *http.Request, pathParams map[string]string) {
+
+	eventName := "HandleListModelOperations"
+
+	_, span := tracer.Start(req.Context(), eventName,
+		trace.WithSpanKind(trace.SpanKindServer))
+	defer span.End()
+
+	owner, ok := getCustomOwner(s, w, req, span)
+	if !ok {
+		return
+	}
+
+	query := req.URL.Query()
+	listOperations(s, w, req, span, service.OperationFilter{
+		Owner:   GenOwnerPermalink(owner),
+		ModelID: strings.TrimPrefix(query.Get("model"), "models/"),
+		Kind:    query.Get("kind"),
+		State:   query.Get("state"),
+	})
+}
+
+// HandleListModelOperationsAdmin is a custom handler that lists the operations of the models of every owner, or of
+// the owner query parameter, with the same filters as HandleListModelOperations
+func HandleListModelOperationsAdmin(s service.Service, w http.ResponseWriter, req *http.Request, pathParams map[string]string) {
+
+	eventName := "HandleListModelOperationsAdmin"
+
+	_, span := tracer.Start(req.Context(), eventName,
+		trace.WithSpanKind(trace.SpanKindServer))
+	defer span.End()
+
+	query := req.URL.Query()
+	listOperations(s, w, req, span, service.OperationFilter{
+		Owner:   query.Get("owner"),
+		ModelID: strings.TrimPrefix(query.Get("model"), "models/"),
+		Kind:    query.Get("kind"),
+		State:   query.Get("state"),
+	})
+}
+
+// HandleCancelModelOperation is a custom handler that cancels a running deploy, create or bulk operation of the
+// requester, a deployment is cleaned up and the model left offline
+func HandleCancelModelOperation(s service.Service, w http.ResponseWriter, req *http.Request, pathParams map[string]string) {
+
+	eventName := "HandleCancelModelOperation"
+
+	ctx, span := tracer.Start(req.Context(), eventName,
+		trace.WithSpanKind(trace.SpanKindServer))
+	defer span.End()
+
+	owner, ok := getCustomOwner(s, w, req, span)
+	if !ok {
+		return
+	}
+
+	operationID := strings.TrimPrefix(pathParams["name"], "operations/")
+	operation, dbModel, err := s.CancelOperation(ctx, GenOwnerPermalink(owner), operationID)
+	if err != nil {
+		writeOperationError(w, span, err)
+		return
+	}
+	if err := setOperationResponse(ctx, s, operation, dbModel); err != nil {
+		writeOperationError(w, span, err)
+		return
+	}
+	writeOperationMessage(w, span, &modelPB.GetModelOperationResponse{Operation: operation})
+}
+
+// bulkModelRequest is the body of a request starting a bulk operation, the models are selected by name or by the
+// filter, or by both
+type bulkModelRequest struct {
+	Action string   `json:"action"`
+	Models []string `json:"models"`
+	Filter struct {
+		State           string `json:"state"`
+		Visibility      string `json:"visibility"`
+		ModelDefinition string `json:"model_definition"`
+	} `json:"filter"`
+	Concurrency int `json:"concurrency"`
+}
+
+// HandleBulkModelOperation is a custom handler that deploys, undeploys, deletes, publishes or unpublishes many models
+// of the requester in one operation, whose response reports the outcome on every model
+func HandleBulkModelOperation(s service.Service, w http.ResponseWriter, req *http.Request, pathParams map[string]string) {
+
+	eventName := "HandleBulkModelOperation"
+
+	ctx, span := tracer.Start(req.Context(), eventName,
+		trace.WithSpanKind(trace.SpanKindServer))
+	defer span.End()
+
+	owner, ok := getCustomOwner(s, w, req, span)
+	if !ok {
+		return
+	}
+
+	body, err := io.ReadAll(req.Body)
+	if err != nil {
+		makeJSONResponse(w, 400, "Parameter invalid", fmt.Sprintf("Unable to read the request body: %s", err))
+		span.SetStatus(1, err.Error())
+		return
+	}
+	var bulkReq bulkModelRequest
+	if err := json.Unmarshal(body, &bulkReq); err != nil {
+		makeJSONResponse(w, 400, "Parameter invalid", fmt.Sprintf("Unable to parse the request body: %s", err))
+		span.SetStatus(1, err.Error())
+		return
+	}
+
+	selector := service.BulkModelSelector{
+		State:           bulkReq.Filter.State,
+		Visibility:      bulkReq.Filter.Visibility,
+		ModelDefinition: bulkReq.Filter.ModelDefinition,
+	}
+	for _, name := range bulkReq.Models {
+		modelID, err := resource.GetModelID(name)
+		if err != nil {
+			makeJSONResponse(w, 400, "Parameter invalid", fmt.Sprintf("Model name %q is invalid", name))
+			span.SetStatus(1, err.Error())
+			return
+		}
+		selector.ModelIDs = append(selector.ModelIDs, modelID)
+	}
+
+	operation, err := s.BulkModelOperation(ctx, GenOwnerPermalink(owner), bulkReq.Action, selector, bulkReq.Concurrency)
+	if err != nil {
+		writeOperationError(w, span, err)
+		return
+	}
+	writeOperationMessage(w, span, &modelPB.GetModelOperationResponse{Operation: operation})
+}
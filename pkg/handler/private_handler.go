@@ -2,6 +2,10 @@ package handler
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
 
 	"github.com/gofrs/uuid"
 	"google.golang.org/grpc/codes"
@@ -105,3 +109,121 @@ func (h *PrivateHandler) CheckModel(ctx context.Context, req *modelPB.CheckModel
 		State: *state,
 	}, nil
 }
+
+// HandleReadinessReport is a custom handler that reports the readiness of every backing dependency
+func HandleReadinessReport(s service.Service, w http.ResponseWriter, req *http.Request, pathParams map[string]string) {
+	report := s.CheckReadiness(req.Context())
+
+	obj, err := json.Marshal(report)
+	if err != nil {
+		makeJSONResponse(w, 500, "Internal Error", err.Error())
+		return
+	}
+
+	w.Header().Add("Content-Type", "application/json")
+	if report.Ready {
+		w.WriteHeader(http.StatusOK)
+	} else {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+	_, _ = w.Write(obj)
+}
+
+// HandleReconcileModels is a custom handler that runs one reconciliation pass between the model states and Triton,
+// nothing is changed when the dry_run query parameter is true
+func HandleReconcileModels(s service.Service, w http.ResponseWriter, req *http.Request, pathParams map[string]string) {
+	dryRun := false
+	if v := req.URL.Query().Get("dry_run"); v != "" {
+		var err error
+		if dryRun, err = strconv.ParseBool(v); err != nil {
+			makeJSONResponse(w, 400, "Bad Request", fmt.Sprintf("invalid dry_run value: %s", v))
+			return
+		}
+	}
+
+	report, err := s.ReconcileModels(req.Context(), dryRun)
+	if err != nil {
+		makeJSONResponse(w, 500, "Internal Error", err.Error())
+		return
+	}
+
+	obj, err := json.Marshal(report)
+	if err != nil {
+		makeJSONResponse(w, 500, "Internal Error", err.Error())
+		return
+	}
+
+	w.Header().Add("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(obj)
+}
+
+// HandleCollectGarbage is a custom handler that removes the model files no longer owned by any model,
+// nothing is removed when the dry_run query parameter is true
+func HandleCollectGarbage(s service.Service, w http.ResponseWriter, req *http.Request, pathParams map[string]string) {
+	dryRun := false
+	if v := req.URL.Query().Get("dry_run"); v != "" {
+		var err error
+		if dryRun, err = strconv.ParseBool(v); err != nil {
+			makeJSONResponse(w, 400, "Bad Request", fmt.Sprintf("invalid dry_run value: %s", v))
+			return
+		}
+	}
+
+	report, err := s.CollectGarbage(req.Context(), dryRun)
+	if err != nil {
+		makeJSONResponse(w, 500, "Internal Error", err.Error())
+		return
+	}
+
+	obj, err := json.Marshal(report)
+	if err != nil {
+		makeJSONResponse(w, 500, "Internal Error", err.Error())
+		return
+	}
+
+	w.Header().Add("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(obj)
+}
+
+// HandleListModelCache is a custom handler that lists the entries of the model artifact cache
+func HandleListModelCache(s service.Service, w http.ResponseWriter, req *http.Request, pathParams map[string]string) {
+	report, err := s.ListModelCache(req.Context())
+	if err != nil {
+		makeJSONResponse(w, 500, "Internal Error", status.Convert(err).Message())
+		return
+	}
+	writeModelCacheReport(w, report)
+}
+
+// HandleEvictModelCache is a custom handler that evicts the model artifact cache entry of the key path parameter,
+// or every entry not in use when there is none
+func HandleEvictModelCache(s service.Service, w http.ResponseWriter, req *http.Request, pathParams map[string]string) {
+	report, err := s.EvictModelCache(req.Context(), pathParams["key"])
+	if err != nil {
+		sta := status.Convert(err)
+		switch sta.Code() {
+		case codes.NotFound:
+			makeJSONResponse(w, 404, "Not found", sta.Message())
+		case codes.FailedPrecondition:
+			makeJSONResponse(w, 409, "Conflict", sta.Message())
+		default:
+			makeJSONResponse(w, 500, "Internal Error", sta.Message())
+		}
+		return
+	}
+	writeModelCacheReport(w, report)
+}
+
+func writeModelCacheReport(w http.ResponseWriter, report *service.ModelCacheReport) {
+	obj, err := json.Marshal(report)
+	if err != nil {
+		makeJSONResponse(w, 500, "Internal Error", err.Error())
+		return
+	}
+
+	w.Header().Add("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(obj)
+}
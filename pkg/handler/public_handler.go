@@ -16,12 +16,12 @@ import (
 	"path"
 	"strconv"
 	"strings"
+	"time"
 
 	"cloud.google.com/go/longrunning/autogen/longrunningpb"
 	"github.com/go-redis/redis/v9"
 	"github.com/gofrs/uuid"
 	"github.com/pkg/errors"
-	"github.com/santhosh-tekuri/jsonschema/v5"
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/trace"
 	"google.golang.org/genproto/googleapis/rpc/errdetails"
@@ -40,7 +40,7 @@ import (
 	"github.com/instill-ai/model-backend/pkg/service"
 	"github.com/instill-ai/model-backend/pkg/triton"
 	"github.com/instill-ai/model-backend/pkg/util"
-	"github.com/instill-ai/x/checkfield"
+	"github.com/instill-ai/model-backend/pkg/worker"
 	"github.com/instill-ai/x/sterr"
 
 	custom_otel "github.com/instill-ai/model-backend/pkg/logger/otel"
@@ -325,7 +325,7 @@ func makeJSONResponse(w http.ResponseWriter, status int, title string, detail st
 }
 
 func (h *PublicHandler) Liveness(ctx context.Context, pb *modelPB.LivenessRequest) (*modelPB.LivenessResponse, error) {
-	if !h.triton.IsTritonServerReady() {
+	if !h.triton.IsTritonServerReady(ctx) {
 		return &modelPB.LivenessResponse{
 			HealthCheckResponse: &healthcheckPB.HealthCheckResponse{
 				Status: healthcheckPB.HealthCheckResponse_SERVING_STATUS_NOT_SERVING,
@@ -336,8 +336,10 @@ func (h *PublicHandler) Liveness(ctx context.Context, pb *modelPB.LivenessReques
 	return &modelPB.LivenessResponse{HealthCheckResponse: &healthcheckPB.HealthCheckResponse{Status: healthcheckPB.HealthCheckResponse_SERVING_STATUS_SERVING}}, nil
 }
 
+// Readiness reports SERVING only when all the required dependencies are reachable,
+// the per-dependency report is served by HandleReadinessReport on the private port
 func (h *PublicHandler) Readiness(ctx context.Context, pb *modelPB.ReadinessRequest) (*modelPB.ReadinessResponse, error) {
-	if !h.triton.IsTritonServerReady() {
+	if !h.service.CheckReadiness(ctx).Ready {
 		return &modelPB.ReadinessResponse{
 			HealthCheckResponse: &healthcheckPB.HealthCheckResponse{
 				Status: healthcheckPB.HealthCheckResponse_SERVING_STATUS_NOT_SERVING,
@@ -463,140 +465,30 @@ func HandleCreateModelByMultiPartFormData(s service.Service, w http.ResponseWrit
 		return
 	}
 
-	// validate model configuration
-	localModelDefinition, err := s.GetRepository().GetModelDefinition(modelDefinitionID)
-	if err != nil {
-		makeJSONResponse(w, 400, "Parameter invalid", "ModelDefinitionId not found")
-		span.SetStatus(1, "ModelDefinitionId not found")
-		return
-	}
-	rs := &jsonschema.Schema{}
-	if err := json.Unmarshal([]byte(localModelDefinition.ModelSpec.String()), rs); err != nil {
-		makeJSONResponse(w, 500, "Add Model Error", "Could not get model definition")
-		span.SetStatus(1, "Could not get model definition")
-		return
-	}
-	modelConfiguration := datamodel.LocalModelConfiguration{
-		Content: fileHeader.Filename,
-	}
-
-	if err := datamodel.ValidateJSONSchema(rs, modelConfiguration, true); err != nil {
-		makeJSONResponse(w, 400, "Add Model Error", fmt.Sprintf("Model configuration is invalid %v", err.Error()))
-		span.SetStatus(1, fmt.Sprintf("Model configuration is invalid %v", err.Error()))
-		return
-	}
-	modelConfiguration.Tag = "latest" // Set after validation. Because the model definition do not contain tag.
-
-	bModelConfig, _ := json.Marshal(modelConfiguration)
-	var uploadedModel = datamodel.Model{
-		ID:                 modelID,
-		ModelDefinitionUid: localModelDefinition.UID,
-		Owner:              ownerPermalink,
-		Visibility:         datamodel.ModelVisibility(visibility),
-		State:              datamodel.ModelState(modelPB.Model_STATE_OFFLINE),
-		Description: sql.NullString{
-			String: req.FormValue("description"),
-			Valid:  true,
-		},
-		Configuration: bModelConfig,
-	}
-
-	// Validate ModelDefinition JSON Schema
-	if err := datamodel.ValidateJSONSchema(datamodel.ModelJSONSchema, DBModelToPBModel(ctx, &localModelDefinition, &uploadedModel, ownerPermalink), true); err != nil {
-		makeJSONResponse(w, 400, "Add Model Error", fmt.Sprintf("Model definition is invalid %v", err.Error()))
-		span.SetStatus(1, fmt.Sprintf("Model definition is invalid %v", err.Error()))
-		return
-	}
-
-	_, err = s.GetModelByID(req.Context(), ownerPermalink, uploadedModel.ID, modelPB.View_VIEW_FULL)
-	if err == nil {
-		makeJSONResponse(w, 409, "Add Model Error", fmt.Sprintf("The model %v already existed", uploadedModel.ID))
-		span.SetStatus(1, fmt.Sprintf("The model %v already existed", uploadedModel.ID))
+	uploadedModel, e := newUploadedModel(ctx, s, ownerPermalink, modelID, modelDefinitionID, visibility, req.FormValue("description"), fileHeader.Filename)
+	if e != nil {
+		_ = os.Remove(tmpFile)
+		makeJSONResponse(w, e.status, e.title, e.detail)
+		span.SetStatus(1, e.detail)
 		return
 	}
 
-	readmeFilePath, ensembleFilePath, err := util.Unzip(tmpFile, config.Config.TritonServer.ModelStore, ownerPermalink, &uploadedModel)
-	_ = os.Remove(tmpFile) // remove uploaded temporary zip file
-	if err != nil {
-		util.RemoveModelRepository(config.Config.TritonServer.ModelStore, ownerPermalink, uploadedModel.ID, modelConfiguration.Tag)
-		makeJSONResponse(w, 400, "Add Model Error", err.Error())
-		span.SetStatus(1, err.Error())
-		return
-	}
-	if _, err := os.Stat(readmeFilePath); err == nil {
-		modelMeta, err := util.GetModelMetaFromReadme(readmeFilePath)
-		if err != nil {
-			util.RemoveModelRepository(config.Config.TritonServer.ModelStore, ownerPermalink, uploadedModel.ID, modelConfiguration.Tag)
-			makeJSONResponse(w, 400, "Add Model Error", err.Error())
-			span.SetStatus(1, err.Error())
-			return
-		}
-		if modelMeta.Task == "" {
-			uploadedModel.Task = datamodel.ModelTask(modelPB.Model_TASK_UNSPECIFIED)
-		} else {
-			if val, ok := util.Tasks[fmt.Sprintf("TASK_%v", strings.ToUpper(modelMeta.Task))]; ok {
-				uploadedModel.Task = datamodel.ModelTask(val)
-			} else {
-				util.RemoveModelRepository(config.Config.TritonServer.ModelStore, ownerPermalink, uploadedModel.ID, modelConfiguration.Tag)
-				makeJSONResponse(w, 400, "Add Model Error", "README.md contains unsupported task")
-				span.SetStatus(1, "README.md contains unsupported task")
-				return
-			}
-		}
-	} else {
-		uploadedModel.Task = datamodel.ModelTask(modelPB.Model_TASK_UNSPECIFIED)
-	}
-
-	maxBatchSize := 0
-	if ensembleFilePath != "" {
-		maxBatchSize, err = util.GetMaxBatchSize(ensembleFilePath)
-		if err != nil {
-			st, e := sterr.CreateErrorResourceInfo(
-				codes.FailedPrecondition,
-				"[handler] create a model error",
-				"Local model",
-				"Missing ensemble model",
-				"",
-				"err.Error()",
-			)
-			if e != nil {
-				logger.Error(e.Error())
-			}
-			obj, _ := json.Marshal(st.Details())
-			makeJSONResponse(w, 400, st.Message(), string(obj))
-			util.RemoveModelRepository(config.Config.TritonServer.ModelStore, ownerPermalink, uploadedModel.ID, modelConfiguration.Tag)
-			span.SetStatus(1, err.Error())
-			return
-		}
-	}
-
-	allowedMaxBatchSize := util.GetSupportedBatchSize(uploadedModel.Task)
-
-	if maxBatchSize > allowedMaxBatchSize {
-		st, e := sterr.CreateErrorPreconditionFailure(
-			"[handler] create a model",
-			[]*errdetails.PreconditionFailure_Violation{
-				{
-					Type:        "MAX BATCH SIZE LIMITATION",
-					Subject:     "Create a model error",
-					Description: fmt.Sprintf("The max_batch_size in config.pbtxt exceeded the limitation %v, please try with a smaller max_batch_size", allowedMaxBatchSize),
-				},
-			})
+	// validate_only is a dry run of the creation checks, the model is not created
+	if validateOnly, _ := strconv.ParseBool(req.FormValue("validate_only")); validateOnly {
+		e = validateModelArchive(ctx, w, ownerPermalink, uploadedModel, tmpFile)
+		_ = os.Remove(tmpFile)
 		if e != nil {
-			logger.Error(e.Error())
+			makeJSONResponse(w, e.status, e.title, e.detail)
+			span.SetStatus(1, e.detail)
 		}
-		util.RemoveModelRepository(config.Config.TritonServer.ModelStore, ownerPermalink, uploadedModel.ID, modelConfiguration.Tag)
-		obj, _ := json.Marshal(st.Details())
-		makeJSONResponse(w, 400, st.Message(), string(obj))
-		span.SetStatus(1, string(obj))
 		return
 	}
 
-	wfId, err := s.CreateModelAsync(req.Context(), ownerPermalink, &uploadedModel)
-	if err != nil {
-		util.RemoveModelRepository(config.Config.TritonServer.ModelStore, ownerPermalink, uploadedModel.ID, modelConfiguration.Tag)
-		makeJSONResponse(w, 500, "Add Model Error", err.Error())
-		span.SetStatus(1, err.Error())
+	wfId, e := createModelFromArchive(ctx, s, ownerPermalink, uploadedModel, tmpFile)
+	_ = os.Remove(tmpFile) // remove uploaded temporary archive file
+	if e != nil {
+		makeJSONResponse(w, e.status, e.title, e.detail)
+		span.SetStatus(1, e.detail)
 		return
 	}
 
@@ -612,7 +504,7 @@ func HandleCreateModelByMultiPartFormData(s service.Service, w http.ResponseWrit
 		},
 	}})
 	if err != nil {
-		util.RemoveModelRepository(config.Config.TritonServer.ModelStore, ownerPermalink, uploadedModel.ID, modelConfiguration.Tag)
+		util.RemoveModelRepository(config.Config.TritonServer.ModelStore, ownerPermalink, uploadedModel.ID, "latest")
 		makeJSONResponse(w, 500, "Add Model Error", err.Error())
 		span.SetStatus(1, err.Error())
 		return
@@ -623,7 +515,7 @@ func HandleCreateModelByMultiPartFormData(s service.Service, w http.ResponseWrit
 		logUUID.String(),
 		owner,
 		eventName,
-		custom_otel.SetEventResource(uploadedModel),
+		custom_otel.SetEventResource(*uploadedModel),
 	)))
 
 	_, _ = w.Write(b)
@@ -676,9 +568,9 @@ func (h *PublicHandler) CreateModelBinaryFileUpload(stream modelPB.ModelPublicSe
 
 	uploadedModel.Owner = ownerPermalink
 
-	// extract zip file from tmp to models directory
-	readmeFilePath, ensembleFilePath, err := util.Unzip(tmpFile, config.Config.TritonServer.ModelStore, ownerPermalink, uploadedModel)
-	_ = os.Remove(tmpFile) // remove uploaded temporary zip file
+	// extract the model archive from tmp to models directory
+	readmeFilePath, ensembleFilePath, err := util.ExtractModelArchive(tmpFile, config.Config.TritonServer.ModelStore, ownerPermalink, uploadedModel)
+	_ = os.Remove(tmpFile) // remove uploaded temporary archive file
 	if err != nil {
 		util.RemoveModelRepository(config.Config.TritonServer.ModelStore, ownerPermalink, uploadedModel.ID, "latest")
 		span.SetStatus(1, err.Error())
@@ -706,6 +598,13 @@ func (h *PublicHandler) CreateModelBinaryFileUpload(stream modelPB.ModelPublicSe
 		uploadedModel.Task = datamodel.ModelTask(modelPB.Model_TASK_UNSPECIFIED)
 	}
 
+	if violations := util.ValidateModelPackage(config.Config.TritonServer.ModelStore, ownerPermalink, uploadedModel); len(violations) > 0 {
+		st := modelPackageError(violations)
+		util.RemoveModelRepository(config.Config.TritonServer.ModelStore, ownerPermalink, uploadedModel.ID, "latest")
+		span.SetStatus(1, st.Err().Error())
+		return st.Err()
+	}
+
 	maxBatchSize, err := util.GetMaxBatchSize(ensembleFilePath)
 	if err != nil {
 		st, e := sterr.CreateErrorResourceInfo(
@@ -786,19 +685,10 @@ func createGitHubModel(h *PublicHandler, ctx context.Context, req *modelPB.Creat
 
 	logger, _ := logger.GetZapLogger(ctx)
 
-	var modelConfig datamodel.GitHubModelConfiguration
-	b, err := req.Model.Configuration.MarshalJSON()
+	modelConfig, err := parseGitHubModelConfiguration(req.Model)
 	if err != nil {
 		span.SetStatus(1, err.Error())
-		return &modelPB.CreateModelResponse{}, status.Errorf(codes.InvalidArgument, err.Error())
-	}
-	if err := json.Unmarshal(b, &modelConfig); err != nil {
-		span.SetStatus(1, err.Error())
-		return &modelPB.CreateModelResponse{}, status.Errorf(codes.InvalidArgument, err.Error())
-	}
-	if modelConfig.Repository == "" {
-		span.SetStatus(1, "Invalid GitHub URL")
-		return &modelPB.CreateModelResponse{}, status.Errorf(codes.InvalidArgument, "Invalid GitHub URL")
+		return &modelPB.CreateModelResponse{}, err
 	}
 	var githubInfo *util.GitHubInfo
 	if config.Config.Server.ItMode.Enabled {
@@ -808,7 +698,7 @@ func createGitHubModel(h *PublicHandler, ctx context.Context, req *modelPB.Creat
 			Tags:        []util.Tag{{Name: "v1.0-cpu"}, {Name: "v1.1-cpu"}},
 		}
 	} else {
-		githubInfo, err = util.GetGitHubRepoInfo(modelConfig.Repository)
+		githubInfo, err = util.GetGitHubRepoInfo(modelConfig.Repository, modelConfig.CredentialRef)
 		if err != nil {
 			span.SetStatus(1, "Invalid GitHub Info")
 			return &modelPB.CreateModelResponse{}, status.Errorf(codes.InvalidArgument, "Invalid GitHub Info")
@@ -825,9 +715,10 @@ func createGitHubModel(h *PublicHandler, ctx context.Context, req *modelPB.Creat
 		visibility = modelPB.Model_VISIBILITY_PRIVATE
 	}
 	bModelConfig, _ := json.Marshal(datamodel.GitHubModelConfiguration{
-		Repository: modelConfig.Repository,
-		HtmlUrl:    "https://github.com/" + modelConfig.Repository,
-		Tag:        modelConfig.Tag,
+		Repository:    modelConfig.Repository,
+		HtmlUrl:       "https://github.com/" + modelConfig.Repository,
+		Tag:           modelConfig.Tag,
+		CredentialRef: modelConfig.CredentialRef,
 	})
 	description := ""
 	if req.Model.Description != nil {
@@ -849,9 +740,6 @@ func createGitHubModel(h *PublicHandler, ctx context.Context, req *modelPB.Creat
 
 	rdid, _ := uuid.NewV4()
 	modelSrcDir := fmt.Sprintf("/tmp/%v", rdid.String()) + ""
-	if config.Config.Cache.Model { // cache model into ~/.cache/instill/models
-		modelSrcDir = util.MODEL_CACHE_DIR + "/" + modelConfig.Repository + modelConfig.Tag
-	}
 
 	if config.Config.Server.ItMode.Enabled { // use local model for testing to remove internet connection issue while testing
 		cmd := exec.Command("/bin/sh", "-c", fmt.Sprintf("mkdir -p %s > /dev/null; cp -rf assets/model-dummy-cls/* %s", modelSrcDir, modelSrcDir))
@@ -861,7 +749,7 @@ func createGitHubModel(h *PublicHandler, ctx context.Context, req *modelPB.Creat
 			return &modelPB.CreateModelResponse{}, err
 		}
 	} else {
-		err = util.GitHubClone(modelSrcDir, modelConfig, false)
+		err = util.GitHubClone(ctx, modelSrcDir, modelConfig, false, nil)
 		if err != nil {
 			st, err := sterr.CreateErrorResourceInfo(
 				codes.FailedPrecondition,
@@ -880,9 +768,7 @@ func createGitHubModel(h *PublicHandler, ctx context.Context, req *modelPB.Creat
 		}
 	}
 	readmeFilePath, ensembleFilePath, err := util.UpdateModelPath(modelSrcDir, config.Config.TritonServer.ModelStore, owner, &githubModel)
-	if !config.Config.Cache.Model {
-		_ = os.RemoveAll(modelSrcDir) // remove uploaded temporary files
-	}
+	_ = os.RemoveAll(modelSrcDir) // remove uploaded temporary files
 
 	if err != nil {
 		st, err := sterr.CreateErrorResourceInfo(
@@ -943,6 +829,13 @@ func createGitHubModel(h *PublicHandler, ctx context.Context, req *modelPB.Creat
 	} else {
 		githubModel.Task = datamodel.ModelTask(modelPB.Model_TASK_UNSPECIFIED)
 	}
+	if violations := util.ValidateModelPackage(config.Config.TritonServer.ModelStore, owner, &githubModel); len(violations) > 0 {
+		st := modelPackageError(violations)
+		util.RemoveModelRepository(config.Config.TritonServer.ModelStore, owner, githubModel.ID, modelConfig.Tag)
+		span.SetStatus(1, st.Err().Error())
+		return &modelPB.CreateModelResponse{}, st.Err()
+	}
+
 	maxBatchSize, err := util.GetMaxBatchSize(ensembleFilePath)
 	if err != nil {
 		st, e := sterr.CreateErrorResourceInfo(
@@ -1045,22 +938,24 @@ func createHuggingFaceModel(h *PublicHandler, ctx context.Context, req *modelPB.
 
 	logger, _ := logger.GetZapLogger(ctx)
 
-	var modelConfig datamodel.HuggingFaceModelConfiguration
-	b, err := req.Model.GetConfiguration().MarshalJSON()
+	modelConfig, err := parseHuggingFaceModelConfiguration(req.Model)
 	if err != nil {
 		span.SetStatus(1, err.Error())
-		return &modelPB.CreateModelResponse{}, status.Errorf(codes.InvalidArgument, err.Error())
-	}
-	if err := json.Unmarshal(b, &modelConfig); err != nil {
-		span.SetStatus(1, err.Error())
-		return &modelPB.CreateModelResponse{}, status.Errorf(codes.InvalidArgument, err.Error())
+		return &modelPB.CreateModelResponse{}, err
 	}
-	if modelConfig.RepoId == "" {
-		span.SetStatus(1, "Invalid model ID")
-		return &modelPB.CreateModelResponse{}, status.Errorf(codes.InvalidArgument, "Invalid model ID")
+	pipelineTag := ""
+	if !config.Config.Server.ItMode.Enabled {
+		// pin the revision to its commit so that every deployment downloads the same files
+		repoInfo, err := util.GetHuggingFaceRepoInfo(ctx, modelConfig.RepoId, modelConfig.Revision, modelConfig.CredentialRef)
+		if err != nil {
+			span.SetStatus(1, err.Error())
+			return &modelPB.CreateModelResponse{}, status.Errorf(codes.FailedPrecondition, err.Error())
+		}
+		modelConfig.Commit = repoInfo.Sha
+		pipelineTag = repoInfo.PipelineTag
+	} else {
+		pipelineTag = "image-classification"
 	}
-	modelConfig.HtmlUrl = "https://huggingface.co/" + modelConfig.RepoId
-	modelConfig.Tag = "latest"
 
 	visibility := modelPB.Model_VISIBILITY_PRIVATE
 	if req.Model.Visibility == modelPB.Model_VISIBILITY_PUBLIC {
@@ -1093,7 +988,7 @@ func createHuggingFaceModel(h *PublicHandler, ctx context.Context, req *modelPB.
 			return &modelPB.CreateModelResponse{}, err
 		}
 	} else {
-		if err := util.HuggingFaceClone(configTmpDir, modelConfig); err != nil {
+		if err := util.HuggingFaceClone(ctx, configTmpDir, modelConfig); err != nil {
 			st, e := sterr.CreateErrorResourceInfo(
 				codes.FailedPrecondition,
 				fmt.Sprintf("[handler] create a model error: %s", err.Error()),
@@ -1110,9 +1005,21 @@ func createHuggingFaceModel(h *PublicHandler, ctx context.Context, req *modelPB.
 			return &modelPB.CreateModelResponse{}, st.Err()
 		}
 	}
+	// the template of the model is chosen from the pipeline tag of the repository or its architecture
+	pipeline, err := util.GetHuggingFacePipeline(pipelineTag, configTmpDir)
+	if err != nil {
+		_ = os.RemoveAll(configTmpDir)
+		span.SetStatus(1, err.Error())
+		return &modelPB.CreateModelResponse{}, status.Errorf(codes.InvalidArgument, err.Error())
+	}
+	template, _ := util.GetHuggingFaceTemplate(pipeline)
+	modelConfig.Pipeline = pipeline
+	huggingfaceModel.Configuration, _ = json.Marshal(modelConfig)
+	huggingfaceModel.Task = datamodel.ModelTask(template.Task)
+
 	rdid, _ = uuid.NewV4()
 	modelDir := fmt.Sprintf("/tmp/%s", rdid.String())
-	if err := util.GenerateHuggingFaceModel(configTmpDir, modelDir, req.Model.Id); err != nil {
+	if err := util.GenerateHuggingFaceModel(configTmpDir, modelDir, req.Model.Id, pipeline); err != nil {
 		st, e := sterr.CreateErrorResourceInfo(
 			codes.FailedPrecondition,
 			fmt.Sprintf("[handler] create a model error: %s", err.Error()),
@@ -1130,7 +1037,7 @@ func createHuggingFaceModel(h *PublicHandler, ctx context.Context, req *modelPB.
 	}
 	_ = os.RemoveAll(configTmpDir)
 
-	readmeFilePath, ensembleFilePath, err := util.UpdateModelPath(modelDir, config.Config.TritonServer.ModelStore, owner, &huggingfaceModel)
+	_, ensembleFilePath, err := util.UpdateModelPath(modelDir, config.Config.TritonServer.ModelStore, owner, &huggingfaceModel)
 
 	_ = os.RemoveAll(modelDir) // remove uploaded temporary files
 	if err != nil {
@@ -1149,60 +1056,6 @@ func createHuggingFaceModel(h *PublicHandler, ctx context.Context, req *modelPB.
 		span.SetStatus(1, st.Err().Error())
 		return &modelPB.CreateModelResponse{}, st.Err()
 	}
-	if _, err := os.Stat(readmeFilePath); err == nil {
-		modelMeta, err := util.GetModelMetaFromReadme(readmeFilePath)
-		if err != nil {
-			st, e := sterr.CreateErrorResourceInfo(
-				codes.FailedPrecondition,
-				fmt.Sprintf("[handler] create a model error: %s", err.Error()),
-				"README.md file",
-				"Could not get meta data from README.md file",
-				"",
-				err.Error(),
-			)
-			if e != nil {
-				logger.Error(e.Error())
-			}
-			util.RemoveModelRepository(config.Config.TritonServer.ModelStore, owner, huggingfaceModel.ID, "latest")
-			span.SetStatus(1, st.Err().Error())
-			return &modelPB.CreateModelResponse{}, st.Err()
-		}
-		if modelMeta.Task != "" {
-
-			if val, ok := util.Tasks[fmt.Sprintf("TASK_%v", strings.ToUpper(modelMeta.Task))]; ok {
-				huggingfaceModel.Task = datamodel.ModelTask(val)
-			} else {
-				st, err := sterr.CreateErrorResourceInfo(
-					codes.FailedPrecondition,
-					"[handler] create a model error",
-					"README.md file",
-					"README.md contains unsupported task",
-					"",
-					err.Error(),
-				)
-				if err != nil {
-					logger.Error(err.Error())
-				}
-				util.RemoveModelRepository(config.Config.TritonServer.ModelStore, owner, huggingfaceModel.ID, modelConfig.Tag)
-				span.SetStatus(1, st.Err().Error())
-				return &modelPB.CreateModelResponse{}, st.Err()
-			}
-		} else {
-			if len(modelMeta.Tags) == 0 {
-				huggingfaceModel.Task = datamodel.ModelTask(modelPB.Model_TASK_UNSPECIFIED)
-			} else { // check in tags also for HuggingFace model card README.md
-				huggingfaceModel.Task = datamodel.ModelTask(modelPB.Model_TASK_UNSPECIFIED)
-				for _, tag := range modelMeta.Tags {
-					if val, ok := util.Tags[strings.ToUpper(tag)]; ok {
-						huggingfaceModel.Task = datamodel.ModelTask(val)
-						break
-					}
-				}
-			}
-		}
-	} else {
-		huggingfaceModel.Task = datamodel.ModelTask(modelPB.Model_TASK_UNSPECIFIED)
-	}
 	maxBatchSize, err := util.GetMaxBatchSize(ensembleFilePath)
 	if err != nil {
 		st, e := sterr.CreateErrorResourceInfo(
@@ -1291,33 +1144,29 @@ func createHuggingFaceModel(h *PublicHandler, ctx context.Context, req *modelPB.
 	}}, nil
 }
 
-func createArtiVCModel(h *PublicHandler, ctx context.Context, req *modelPB.CreateModelRequest, owner string, modelDefinition *datamodel.ModelDefinition) (*modelPB.CreateModelResponse, error) {
-
-	eventName := "CreateArtiVCModel"
-
-	ctx, span := tracer.Start(ctx, eventName,
-		trace.WithSpanKind(trace.SpanKindServer))
-	defer span.End()
-
-	logUUID, _ := uuid.NewV4()
-
-	logger, _ := logger.GetZapLogger(ctx)
+// modelFetcher gets the files of a model created from a remote source
+type modelFetcher struct {
+	// source and action name the fetch in its errors
+	source string
+	action string
+	// stagingPath is where the files are fetched, a random folder removed on failure when empty
+	stagingPath string
+	// fetch downloads the model files into the staging path
+	fetch func(ctx context.Context, stagingPath string) error
+	// place writes the fetched files into the model repository and returns the paths of the README and of the
+	// ensemble configuration of the model, util.UpdateModelPath when nil
+	place func(stagingPath string, dstDir string, owner string, model *datamodel.Model) (string, string, error)
+	// allowNoEnsemble accepts the model packages made of a single Triton model
+	allowNoEnsemble bool
+}
 
-	var modelConfig datamodel.ArtiVCModelConfiguration
-	b, err := req.Model.GetConfiguration().MarshalJSON()
-	if err != nil {
-		span.SetStatus(1, err.Error())
-		return &modelPB.CreateModelResponse{}, status.Errorf(codes.InvalidArgument, err.Error())
-	}
-	if err := json.Unmarshal(b, &modelConfig); err != nil {
-		span.SetStatus(1, err.Error())
-		return &modelPB.CreateModelResponse{}, status.Errorf(codes.InvalidArgument, err.Error())
-	}
-	if modelConfig.Url == "" {
-		span.SetStatus(1, "Invalid GitHub URL")
-		return &modelPB.CreateModelResponse{}, status.Errorf(codes.InvalidArgument, "Invalid GitHub URL")
-	}
+// copyDummyModel fetches the bundled dummy model in place of a remote one in the integration tests
+func copyDummyModel(dir string) error {
+	return exec.Command("/bin/sh", "-c", fmt.Sprintf("mkdir -p %s > /dev/null; cp -rf assets/model-dummy-cls/* %s", dir, dir)).Run()
+}
 
+// newRemoteModel returns the model to create from a request and its source configuration
+func newRemoteModel(req *modelPB.CreateModelRequest, owner string, modelDefinition *datamodel.ModelDefinition, modelConfig interface{}) datamodel.Model {
 	visibility := modelPB.Model_VISIBILITY_PRIVATE
 	if req.Model.Visibility == modelPB.Model_VISIBILITY_PUBLIC {
 		visibility = modelPB.Model_VISIBILITY_PUBLIC
@@ -1327,7 +1176,7 @@ func createArtiVCModel(h *PublicHandler, ctx context.Context, req *modelPB.Creat
 	if req.Model.Description != nil {
 		description = *req.Model.Description
 	}
-	artivcModel := datamodel.Model{
+	return datamodel.Model{
 		ID:                 req.Model.Id,
 		ModelDefinitionUid: modelDefinition.UID,
 		Owner:              owner,
@@ -1339,118 +1188,89 @@ func createArtiVCModel(h *PublicHandler, ctx context.Context, req *modelPB.Creat
 		},
 		Configuration: bModelConfig,
 	}
+}
 
-	rdid, _ := uuid.NewV4()
-	modelSrcDir := fmt.Sprintf("/tmp/%v", rdid.String())
-	if config.Config.Server.ItMode.Enabled { // use local model for testing to remove internet connection issue while testing
-		cmd := exec.Command("/bin/sh", "-c", fmt.Sprintf("mkdir -p %s > /dev/null; cp -rf assets/model-dummy-cls/* %s", modelSrcDir, modelSrcDir))
-		if err := cmd.Run(); err != nil {
-			util.RemoveModelRepository(config.Config.TritonServer.ModelStore, owner, artivcModel.ID, modelConfig.Tag)
-			span.SetStatus(1, err.Error())
-			return &modelPB.CreateModelResponse{}, err
-		}
-	} else {
-		err = util.ArtiVCClone(modelSrcDir, modelConfig, false)
-		if err != nil {
-			st, e := sterr.CreateErrorResourceInfo(
-				codes.FailedPrecondition,
-				fmt.Sprintf("[handler] create a model error: %s", err.Error()),
-				"ArtiVC",
-				"Clone repository",
-				"",
-				err.Error(),
-			)
-			if e != nil {
-				logger.Error(e.Error())
-			}
-			_ = os.RemoveAll(modelSrcDir)
-			util.RemoveModelRepository(config.Config.TritonServer.ModelStore, owner, artivcModel.ID, modelConfig.Tag)
-			span.SetStatus(1, st.Err().Error())
-			return &modelPB.CreateModelResponse{}, st.Err()
-		}
-		util.AddMissingTritonModelFolder(ctx, modelSrcDir) // large files not pull then need to create triton model folder
-	}
+// createModelFromSource fetches the files of a model into the model repository, checks its README, package and
+// batch size, and starts its creation
+func createModelFromSource(h *PublicHandler, ctx context.Context, span trace.Span, eventName string, owner string, tag string, model *datamodel.Model, fetcher modelFetcher) (*modelPB.CreateModelResponse, error) {
 
-	readmeFilePath, ensembleFilePath, err := util.UpdateModelPath(modelSrcDir, config.Config.TritonServer.ModelStore, owner, &artivcModel)
-	_ = os.RemoveAll(modelSrcDir) // remove uploaded temporary files
-	if err != nil {
-		st, err := sterr.CreateErrorResourceInfo(
-			codes.FailedPrecondition,
+	logUUID, _ := uuid.NewV4()
+
+	logger, _ := logger.GetZapLogger(ctx)
+
+	modelStore := config.Config.TritonServer.ModelStore
+
+	resourceError := func(code codes.Code, resourceType string, resourceName string, err error) *status.Status {
+		st, e := sterr.CreateErrorResourceInfo(
+			code,
 			fmt.Sprintf("[handler] create a model error: %s", err.Error()),
-			"Model folder structure",
-			"",
+			resourceType,
+			resourceName,
 			"",
 			err.Error(),
 		)
-		if err != nil {
-			logger.Error(err.Error())
+		if e != nil {
+			logger.Error(e.Error())
 		}
-		util.RemoveModelRepository(config.Config.TritonServer.ModelStore, owner, artivcModel.ID, modelConfig.Tag)
+		return st
+	}
+	// fail removes what was written into the model repository and reports the error
+	fail := func(st *status.Status) (*modelPB.CreateModelResponse, error) {
+		util.RemoveModelRepository(modelStore, owner, model.ID, tag)
 		span.SetStatus(1, st.Err().Error())
 		return &modelPB.CreateModelResponse{}, st.Err()
 	}
+
+	stagingPath := fetcher.stagingPath
+	if stagingPath == "" {
+		rdid, _ := uuid.NewV4()
+		stagingPath = fmt.Sprintf("%s/%v", util.TMP_DIR, rdid.String())
+	}
+	if err := fetcher.fetch(ctx, stagingPath); err != nil {
+		// a staging path named by the fetcher is kept for a retried creation to resume from
+		if fetcher.stagingPath == "" {
+			_ = os.RemoveAll(stagingPath)
+		}
+		return fail(resourceError(codes.FailedPrecondition, fetcher.source, fetcher.action, err))
+	}
+
+	place := fetcher.place
+	if place == nil {
+		place = util.UpdateModelPath
+	}
+	readmeFilePath, ensembleFilePath, err := place(stagingPath, modelStore, owner, model)
+	_ = os.RemoveAll(stagingPath) // remove fetched temporary files
+	if err != nil {
+		return fail(resourceError(codes.FailedPrecondition, "Model folder structure", "", err))
+	}
+
+	model.Task = datamodel.ModelTask(modelPB.Model_TASK_UNSPECIFIED)
 	if _, err := os.Stat(readmeFilePath); err == nil {
 		modelMeta, err := util.GetModelMetaFromReadme(readmeFilePath)
-		if err != nil || modelMeta.Task == "" {
-			st, e := sterr.CreateErrorResourceInfo(
-				codes.FailedPrecondition,
-				fmt.Sprintf("[handler] create a model error: %s", err.Error()),
-				"README.md file",
-				"Could not get meta data from README.md file",
-				"",
-				err.Error(),
-			)
-			if e != nil {
-				logger.Error(e.Error())
-			}
-			util.RemoveModelRepository(config.Config.TritonServer.ModelStore, owner, artivcModel.ID, modelConfig.Tag)
-			span.SetStatus(1, st.Err().Error())
-			return &modelPB.CreateModelResponse{}, st.Err()
+		if err != nil {
+			return fail(resourceError(codes.FailedPrecondition, "README.md file", "Could not get meta data from README.md file", err))
 		}
-		if val, ok := util.Tasks[fmt.Sprintf("TASK_%v", strings.ToUpper(modelMeta.Task))]; ok {
-			artivcModel.Task = datamodel.ModelTask(val)
-		} else {
-			if modelMeta.Task != "" {
-				st, err := sterr.CreateErrorResourceInfo(
-					codes.FailedPrecondition,
-					"[handler] create a model error",
-					"README.md file",
-					"README.md contains unsupported task",
-					"",
-					err.Error(),
-				)
-				if err != nil {
-					logger.Error(err.Error())
-				}
-				util.RemoveModelRepository(config.Config.TritonServer.ModelStore, owner, artivcModel.ID, modelConfig.Tag)
-				span.SetStatus(1, st.Err().Error())
-				return &modelPB.CreateModelResponse{}, st.Err()
-			} else {
-				artivcModel.Task = datamodel.ModelTask(modelPB.Model_TASK_UNSPECIFIED)
+		if modelMeta.Task != "" {
+			val, ok := util.Tasks[fmt.Sprintf("TASK_%v", strings.ToUpper(modelMeta.Task))]
+			if !ok {
+				return fail(resourceError(codes.FailedPrecondition, "README.md file", "README.md contains unsupported task", fmt.Errorf("unsupported task %s", modelMeta.Task)))
 			}
+			model.Task = datamodel.ModelTask(val)
 		}
-	} else {
-		artivcModel.Task = datamodel.ModelTask(modelPB.Model_TASK_UNSPECIFIED)
 	}
 
-	maxBatchSize, err := util.GetMaxBatchSize(ensembleFilePath)
-	if err != nil {
-		st, e := sterr.CreateErrorResourceInfo(
-			codes.FailedPrecondition,
-			fmt.Sprintf("[handler] create a model error: %s", err.Error()),
-			"ArtiVC model",
-			"Missing ensemble model",
-			"",
-			err.Error(),
-		)
-		if e != nil {
-			logger.Error(e.Error())
+	if violations := util.ValidateModelPackage(modelStore, owner, model); len(violations) > 0 {
+		return fail(modelPackageError(violations))
+	}
+
+	maxBatchSize := 0
+	if ensembleFilePath != "" || !fetcher.allowNoEnsemble {
+		if maxBatchSize, err = util.GetMaxBatchSize(ensembleFilePath); err != nil {
+			return fail(resourceError(codes.FailedPrecondition, fmt.Sprintf("%s model", fetcher.source), "Missing ensemble model", err))
 		}
-		span.SetStatus(1, st.Err().Error())
-		return &modelPB.CreateModelResponse{}, st.Err()
 	}
 
-	allowedMaxBatchSize := util.GetSupportedBatchSize(artivcModel.Task)
+	allowedMaxBatchSize := util.GetSupportedBatchSize(model.Task)
 
 	if maxBatchSize > allowedMaxBatchSize {
 		st, e := sterr.CreateErrorPreconditionFailure(
@@ -1466,26 +1286,12 @@ func createArtiVCModel(h *PublicHandler, ctx context.Context, req *modelPB.Creat
 		if e != nil {
 			logger.Error(e.Error())
 		}
-		span.SetStatus(1, st.Err().Error())
-		return &modelPB.CreateModelResponse{}, st.Err()
+		return fail(st)
 	}
 
-	wfId, err := h.service.CreateModelAsync(ctx, owner, &artivcModel)
+	wfId, err := h.service.CreateModelAsync(ctx, owner, model)
 	if err != nil {
-		st, e := sterr.CreateErrorResourceInfo(
-			codes.Internal,
-			fmt.Sprintf("[handler] create a model error: %s", err.Error()),
-			"Model service",
-			"",
-			"",
-			err.Error(),
-		)
-		if e != nil {
-			logger.Error(e.Error())
-		}
-		util.RemoveModelRepository(config.Config.TritonServer.ModelStore, owner, artivcModel.ID, modelConfig.Tag)
-		span.SetStatus(1, st.Err().Error())
-		return &modelPB.CreateModelResponse{}, st.Err()
+		return fail(resourceError(codes.Internal, "Model service", "", err))
 	}
 
 	// Manually set the custom header to have a StatusCreated http response for REST endpoint
@@ -1505,7 +1311,7 @@ func createArtiVCModel(h *PublicHandler, ctx context.Context, req *modelPB.Creat
 		logUUID.String(),
 		user,
 		eventName,
-		custom_otel.SetEventResource(artivcModel),
+		custom_otel.SetEventResource(*model),
 		custom_otel.SetEventResult(&longrunningpb.Operation_Response{
 			Response: &anypb.Any{
 				Value: []byte(wfId),
@@ -1522,83 +1328,170 @@ func createArtiVCModel(h *PublicHandler, ctx context.Context, req *modelPB.Creat
 	}}, nil
 }
 
-func (h *PublicHandler) CreateModel(ctx context.Context, req *modelPB.CreateModelRequest) (*modelPB.CreateModelResponse, error) {
+func createArtiVCModel(h *PublicHandler, ctx context.Context, req *modelPB.CreateModelRequest, owner string, modelDefinition *datamodel.ModelDefinition) (*modelPB.CreateModelResponse, error) {
 
-	ctx, span := tracer.Start(ctx, "CreateModel",
+	eventName := "CreateArtiVCModel"
+
+	ctx, span := tracer.Start(ctx, eventName,
 		trace.WithSpanKind(trace.SpanKindServer))
 	defer span.End()
 
-	resp := &modelPB.CreateModelResponse{}
-	owner, err := resource.GetOwner(ctx, h.service.GetMgmtPrivateServiceClient(), h.service.GetRedisClient())
+	modelConfig, err := parseArtiVCModelConfiguration(req.Model)
 	if err != nil {
 		span.SetStatus(1, err.Error())
-		return resp, err
+		return &modelPB.CreateModelResponse{}, err
 	}
-	ownerPermalink := "users/" + owner.GetUid()
 
-	// Set all OUTPUT_ONLY fields to zero value on the requested payload model resource
-	if err := checkfield.CheckCreateOutputOnlyFields(req.Model, outputOnlyFields); err != nil {
+	artivcModel := newRemoteModel(req, owner, modelDefinition, modelConfig)
+
+	return createModelFromSource(h, ctx, span, eventName, owner, modelConfig.Tag, &artivcModel, modelFetcher{
+		source: "ArtiVC",
+		action: "Clone repository",
+		fetch: func(ctx context.Context, dir string) error {
+			if config.Config.Server.ItMode.Enabled { // use local model for testing to remove internet connection issue while testing
+				return copyDummyModel(dir)
+			}
+			if err := util.ArtiVCClone(ctx, dir, modelConfig, false, nil); err != nil {
+				return err
+			}
+			util.AddMissingTritonModelFolder(ctx, dir) // large files not pull then need to create triton model folder
+			return nil
+		},
+	})
+}
+
+func createS3Model(h *PublicHandler, ctx context.Context, req *modelPB.CreateModelRequest, owner string, modelDefinition *datamodel.ModelDefinition) (*modelPB.CreateModelResponse, error) {
+
+	eventName := "CreateS3Model"
+
+	ctx, span := tracer.Start(ctx, eventName,
+		trace.WithSpanKind(trace.SpanKindServer))
+	defer span.End()
+
+	modelConfig, err := parseS3ModelConfiguration(req.Model)
+	if err != nil {
 		span.SetStatus(1, err.Error())
-		return resp, status.Errorf(codes.InvalidArgument, err.Error())
+		return &modelPB.CreateModelResponse{}, err
 	}
 
-	// Return error if REQUIRED fields are not provided in the requested payload model resource
-	if err := checkfield.CheckRequiredFields(req.Model, requiredFields); err != nil {
+	s3Model := newRemoteModel(req, owner, modelDefinition, modelConfig)
+
+	return createModelFromSource(h, ctx, span, eventName, owner, modelConfig.Tag, &s3Model, modelFetcher{
+		source: "S3",
+		action: "Download model",
+		fetch: func(ctx context.Context, dir string) error {
+			if config.Config.Server.ItMode.Enabled { // use local model for testing to remove internet connection issue while testing
+				return copyDummyModel(dir)
+			}
+			if err := util.S3Download(ctx, dir, modelConfig, false); err != nil {
+				return err
+			}
+			util.AddMissingTritonModelFolder(ctx, dir) // large files not pull then need to create triton model folder
+			return nil
+		},
+	})
+}
+
+func createGitModel(h *PublicHandler, ctx context.Context, req *modelPB.CreateModelRequest, owner string, modelDefinition *datamodel.ModelDefinition) (*modelPB.CreateModelResponse, error) {
+
+	eventName := "CreateGitModel"
+
+	ctx, span := tracer.Start(ctx, eventName,
+		trace.WithSpanKind(trace.SpanKindServer))
+	defer span.End()
+
+	modelConfig, err := parseGitModelConfiguration(req.Model)
+	if err != nil {
 		span.SetStatus(1, err.Error())
-		return resp, status.Errorf(codes.InvalidArgument, err.Error())
+		return &modelPB.CreateModelResponse{}, err
 	}
 
-	// Return error if resource ID does not follow RFC-1034
-	if err := checkfield.CheckResourceID(req.Model.GetId()); err != nil {
+	gitModel := newRemoteModel(req, owner, modelDefinition, modelConfig)
+
+	return createModelFromSource(h, ctx, span, eventName, owner, modelConfig.Tag, &gitModel, modelFetcher{
+		source: "Git",
+		action: "Clone repository",
+		fetch: func(ctx context.Context, dir string) error {
+			if config.Config.Server.ItMode.Enabled { // use local model for testing to remove internet connection issue while testing
+				return copyDummyModel(dir)
+			}
+			if err := util.GitClone(ctx, dir, modelConfig.Url, modelConfig.Tag, modelConfig.CredentialRef, false, nil); err != nil {
+				return err
+			}
+			util.AddMissingTritonModelFolder(ctx, dir) // large files not pull then need to create triton model folder
+			return nil
+		},
+	})
+}
+
+func createURLModel(h *PublicHandler, ctx context.Context, req *modelPB.CreateModelRequest, owner string, modelDefinition *datamodel.ModelDefinition) (*modelPB.CreateModelResponse, error) {
+
+	eventName := "CreateURLModel"
+
+	ctx, span := tracer.Start(ctx, eventName,
+		trace.WithSpanKind(trace.SpanKindServer))
+	defer span.End()
+
+	modelConfig, err := parseURLModelConfiguration(req.Model)
+	if err != nil {
 		span.SetStatus(1, err.Error())
-		return resp, status.Errorf(codes.InvalidArgument, err.Error())
+		return &modelPB.CreateModelResponse{}, err
 	}
-	// Validate ModelDefinition JSON Schema
-	if err := datamodel.ValidateJSONSchema(datamodel.ModelJSONSchema, req.Model, false); err != nil {
+	headers, err := util.GetURLCredentialHeaders(modelConfig.CredentialRef)
+	if err != nil {
 		span.SetStatus(1, err.Error())
-		return resp, status.Errorf(codes.InvalidArgument, err.Error())
-	}
-	_, err = h.service.GetModelByID(ctx, ownerPermalink, req.Model.Id, modelPB.View_VIEW_FULL)
-	if err == nil {
-		span.SetStatus(1, "Model already existed")
-		return resp, status.Errorf(codes.AlreadyExists, "Model already existed")
+		return &modelPB.CreateModelResponse{}, status.Errorf(codes.InvalidArgument, err.Error())
 	}
 
-	if req.Model.Configuration == nil {
-		span.SetStatus(1, "Missing Configuration")
-		return resp, status.Errorf(codes.InvalidArgument, "Missing Configuration")
-	}
+	urlModel := newRemoteModel(req, owner, modelDefinition, modelConfig)
+
+	return createModelFromSource(h, ctx, span, eventName, owner, modelConfig.Tag, &urlModel, modelFetcher{
+		source: "URL",
+		action: "Download archive",
+		// The staged archive is named after the model and the URL so that a retried creation resumes the download
+		stagingPath: fmt.Sprintf("%s/%s", util.TMP_DIR, uuid.NewV5(uuid.NamespaceURL, fmt.Sprintf("%s/%s#%s", owner, urlModel.ID, modelConfig.Url))),
+		fetch: func(ctx context.Context, archivePath string) error {
+			return util.DownloadFile(ctx, modelConfig.Url, archivePath, headers, modelConfig.Checksum)
+		},
+		place:           util.ExtractModelArchive,
+		allowNoEnsemble: true,
+	})
+}
+
+func (h *PublicHandler) CreateModel(ctx context.Context, req *modelPB.CreateModelRequest) (*modelPB.CreateModelResponse, error) {
+
+	ctx, span := tracer.Start(ctx, "CreateModel",
+		trace.WithSpanKind(trace.SpanKindServer))
+	defer span.End()
 
-	modelDefinitionID, err := resource.GetDefinitionID(req.Model.ModelDefinition)
+	resp := &modelPB.CreateModelResponse{}
+	owner, err := resource.GetOwner(ctx, h.service.GetMgmtPrivateServiceClient(), h.service.GetRedisClient())
 	if err != nil {
 		span.SetStatus(1, err.Error())
 		return resp, err
 	}
+	ownerPermalink := "users/" + owner.GetUid()
 
-	modelDefinition, err := h.service.GetModelDefinition(ctx, modelDefinitionID)
+	modelDefinition, err := checkCreateModelRequest(ctx, h.service, ownerPermalink, req.Model)
 	if err != nil {
-		span.SetStatus(1, err.Error())
-		return &modelPB.CreateModelResponse{}, status.Errorf(codes.InvalidArgument, err.Error())
-	}
-
-	// validate model configuration
-	rs := &jsonschema.Schema{}
-	if err := json.Unmarshal([]byte(modelDefinition.ModelSpec.String()), rs); err != nil {
-		span.SetStatus(1, "Could not get model definition")
-		return &modelPB.CreateModelResponse{}, status.Errorf(codes.InvalidArgument, "Could not get model definition")
-	}
-	if err := datamodel.ValidateJSONSchema(rs, req.Model.GetConfiguration(), true); err != nil {
-		span.SetStatus(1, fmt.Sprintf("Model configuration is invalid %v", err.Error()))
-		return &modelPB.CreateModelResponse{}, status.Errorf(codes.InvalidArgument, fmt.Sprintf("Model configuration is invalid %v", err.Error()))
+		span.SetStatus(1, err.Error())
+		return resp, err
 	}
+	modelDefinitionID := modelDefinition.ID
 
 	switch modelDefinitionID {
 	case "github":
-		return createGitHubModel(h, ctx, req, ownerPermalink, &modelDefinition)
+		return createGitHubModel(h, ctx, req, ownerPermalink, modelDefinition)
 	case "artivc":
-		return createArtiVCModel(h, ctx, req, ownerPermalink, &modelDefinition)
+		return createArtiVCModel(h, ctx, req, ownerPermalink, modelDefinition)
 	case "huggingface":
-		return createHuggingFaceModel(h, ctx, req, ownerPermalink, &modelDefinition)
+		return createHuggingFaceModel(h, ctx, req, ownerPermalink, modelDefinition)
+	case "s3":
+		return createS3Model(h, ctx, req, ownerPermalink, modelDefinition)
+	case "git":
+		return createGitModel(h, ctx, req, ownerPermalink, modelDefinition)
+	case "url":
+		return createURLModel(h, ctx, req, ownerPermalink, modelDefinition)
 	default:
 		span.SetStatus(1, fmt.Sprintf("model definition %v is not supported", modelDefinitionID))
 		return resp, status.Errorf(codes.InvalidArgument, fmt.Sprintf("model definition %v is not supported", modelDefinitionID))
@@ -2068,7 +1961,9 @@ func (h *PublicHandler) DeployModel(ctx context.Context, req *modelPB.DeployMode
 		return &modelPB.DeployModelResponse{}, err
 	}
 
-	if *state != modelPB.Model_STATE_OFFLINE {
+	// a model in operation is deployed by the running operation when it is a deploy, which the request joins, and the
+	// request fails with an Aborted error otherwise
+	if *state != modelPB.Model_STATE_OFFLINE && *state != modelPB.Model_STATE_UNSPECIFIED {
 		return &modelPB.DeployModelResponse{},
 			status.Error(codes.FailedPrecondition, fmt.Sprintf("Deploy model only work with offline model state, current model state is %s", state))
 	}
@@ -2080,12 +1975,19 @@ func (h *PublicHandler) DeployModel(ctx context.Context, req *modelPB.DeployMode
 	}
 
 	// set user desired state to STATE_ONLINE
-	if _, err := h.service.UpdateModelState(ctx, dbModel.UID, &dbModel, datamodel.ModelState(modelPB.Model_STATE_ONLINE)); err != nil {
-		return &modelPB.DeployModelResponse{}, err
+	if *state == modelPB.Model_STATE_OFFLINE {
+		if _, err := h.service.UpdateModelState(ctx, dbModel.UID, &dbModel, datamodel.ModelState(modelPB.Model_STATE_ONLINE)); err != nil {
+			return &modelPB.DeployModelResponse{}, err
+		}
 	}
 
 	wfId, err := h.service.DeployModelAsync(ctx, ownerPermalink, dbModel.UID)
 	if err != nil {
+		// another operation is running on the model
+		if status.Code(err) == codes.Aborted {
+			span.SetStatus(1, err.Error())
+			return &modelPB.DeployModelResponse{}, err
+		}
 		st, e := sterr.CreateErrorResourceInfo(
 			codes.Internal,
 			fmt.Sprintf("[handler] deploy a model error: %s", err.Error()),
@@ -2112,12 +2014,14 @@ func (h *PublicHandler) DeployModel(ctx context.Context, req *modelPB.DeployMode
 		return &modelPB.DeployModelResponse{}, st.Err()
 	}
 
+	// the controller follows the workflow of the operation, the latest run of its workflow ID
+	workflowID, _ := worker.ParseOperationID(wfId)
 	if err := h.service.UpdateResourceState(
 		ctx,
 		dbModel.UID,
 		modelPB.Model_STATE_UNSPECIFIED,
 		nil,
-		&wfId,
+		&workflowID,
 	); err != nil {
 		span.SetStatus(1, err.Error())
 		return &modelPB.DeployModelResponse{}, err
@@ -2188,7 +2092,9 @@ func (h *PublicHandler) UndeployModel(ctx context.Context, req *modelPB.Undeploy
 		return &modelPB.UndeployModelResponse{}, err
 	}
 
-	if *state != modelPB.Model_STATE_ONLINE {
+	// a model in operation is undeployed by the running operation when it is an undeploy, which the request joins, and
+	// the request fails with an Aborted error otherwise
+	if *state != modelPB.Model_STATE_ONLINE && *state != modelPB.Model_STATE_UNSPECIFIED {
 		span.SetStatus(1, fmt.Sprintf("undeploy model only work with online model instance state, current model state is %s",
 			state))
 		return &modelPB.UndeployModelResponse{},
@@ -2197,13 +2103,20 @@ func (h *PublicHandler) UndeployModel(ctx context.Context, req *modelPB.Undeploy
 	}
 
 	// set user desired state to STATE_OFFLINE
-	if _, err := h.service.UpdateModelState(ctx, dbModel.UID, &dbModel, datamodel.ModelState(modelPB.Model_STATE_OFFLINE)); err != nil {
-		span.SetStatus(1, err.Error())
-		return &modelPB.UndeployModelResponse{}, err
+	if *state == modelPB.Model_STATE_ONLINE {
+		if _, err := h.service.UpdateModelState(ctx, dbModel.UID, &dbModel, datamodel.ModelState(modelPB.Model_STATE_OFFLINE)); err != nil {
+			span.SetStatus(1, err.Error())
+			return &modelPB.UndeployModelResponse{}, err
+		}
 	}
 
 	wfId, err := h.service.UndeployModelAsync(ctx, ownerPermalink, dbModel.UID)
 	if err != nil {
+		// another operation is running on the model
+		if status.Code(err) == codes.Aborted {
+			span.SetStatus(1, err.Error())
+			return &modelPB.UndeployModelResponse{}, err
+		}
 		// Manually set the custom header to have a StatusUnprocessableEntity http response for REST endpoint
 		if err := grpc.SetHeader(ctx, metadata.Pairs("x-http-code", strconv.Itoa(http.StatusUnprocessableEntity))); err != nil {
 			span.SetStatus(1, err.Error())
@@ -2213,12 +2126,14 @@ func (h *PublicHandler) UndeployModel(ctx context.Context, req *modelPB.Undeploy
 		return &modelPB.UndeployModelResponse{}, err
 	}
 
+	// the controller follows the workflow of the operation, the latest run of its workflow ID
+	workflowID, _ := worker.ParseOperationID(wfId)
 	if err := h.service.UpdateResourceState(
 		ctx,
 		dbModel.UID,
 		modelPB.Model_STATE_UNSPECIFIED,
 		nil,
-		&wfId,
+		&workflowID,
 	); err != nil {
 		span.SetStatus(1, err.Error())
 		return &modelPB.UndeployModelResponse{}, err
@@ -2376,6 +2291,13 @@ func (h *PublicHandler) TestModelBinaryFileUpload(stream modelPB.ModelPublicServ
 		}
 	}
 
+	md, err := ensureModelLoaded(stream.Context(), h.service, &modelInDB)
+	if err != nil {
+		span.SetStatus(1, err.Error())
+		return err
+	}
+	_ = stream.SetHeader(md)
+
 	task := modelPB.Model_Task(modelInDB.Task)
 	response, err := h.service.ModelInferTestMode(stream.Context(), ownerPermalink, modelInDB.UID, triggerInput, task)
 	if err != nil {
@@ -2482,6 +2404,13 @@ func (h *PublicHandler) TriggerModelBinaryFileUpload(stream modelPB.ModelPublicS
 		}
 	}
 
+	md, err := ensureModelLoaded(stream.Context(), h.service, &modelInDB)
+	if err != nil {
+		span.SetStatus(1, err.Error())
+		return err
+	}
+	_ = stream.SetHeader(md)
+
 	task := modelPB.Model_Task(modelInDB.Task)
 	response, err := h.service.ModelInfer(stream.Context(), modelInDB.UID, triggerInput, task)
 	if err != nil {
@@ -2611,6 +2540,13 @@ func (h *PublicHandler) TriggerModel(ctx context.Context, req *modelPB.TriggerMo
 			return &modelPB.TriggerModelResponse{}, status.Error(codes.InvalidArgument, "The model do not support batching, so could not make inference with multiple images")
 		}
 	}
+	md, err := ensureModelLoaded(ctx, h.service, &modelInDB)
+	if err != nil {
+		span.SetStatus(1, err.Error())
+		return &modelPB.TriggerModelResponse{}, err
+	}
+	_ = grpc.SetHeader(ctx, md)
+
 	task := modelPB.Model_Task(modelInDB.Task)
 	response, err := h.service.ModelInfer(ctx, modelInDB.UID, inputInfer, task)
 	if err != nil {
@@ -2750,6 +2686,13 @@ func (h *PublicHandler) TestModel(ctx context.Context, req *modelPB.TestModelReq
 		}
 	}
 
+	md, err := ensureModelLoaded(ctx, h.service, &modelInDB)
+	if err != nil {
+		span.SetStatus(1, err.Error())
+		return &modelPB.TestModelResponse{}, err
+	}
+	_ = grpc.SetHeader(ctx, md)
+
 	task := modelPB.Model_Task(modelInDB.Task)
 	response, err := h.service.ModelInferTestMode(ctx, ownerPermalink, modelInDB.UID, inputInfer, task)
 	if err != nil {
@@ -2927,6 +2870,20 @@ func inferModelByUpload(s service.Service, w http.ResponseWriter, req *http.Requ
 		}
 	}
 
+	md, err := ensureModelLoaded(req.Context(), s, &modelInDB)
+	if err != nil {
+		if status.Code(err) == codes.Unavailable {
+			makeJSONResponse(w, 503, "Model Unavailable", err.Error())
+		} else {
+			makeJSONResponse(w, 500, "Internal Error", err.Error())
+		}
+		span.SetStatus(1, err.Error())
+		return
+	}
+	for k, v := range md {
+		w.Header().Set(k, v[0])
+	}
+
 	task := modelPB.Model_Task(modelInDB.Task)
 	var response []*modelPB.TaskOutput
 	if mode == "test" {
@@ -2996,6 +2953,186 @@ func HandleTriggerModelByUpload(s service.Service, w http.ResponseWriter, r *htt
 	inferModelByUpload(s, w, r, pathParams, "trigger")
 }
 
+// HandleSetModelIdleTimeout is a custom handler that sets the minutes without inference after which
+// the deployed model is unloaded from Triton, 0 disables it
+func HandleSetModelIdleTimeout(s service.Service, w http.ResponseWriter, req *http.Request, pathParams map[string]string) {
+
+	eventName := "HandleSetModelIdleTimeout"
+
+	ctx, span := tracer.Start(req.Context(), eventName,
+		trace.WithSpanKind(trace.SpanKindServer))
+	defer span.End()
+
+	logUUID, _ := uuid.NewV4()
+
+	logger, _ := logger.GetZapLogger(ctx)
+
+	owner, err := resource.GetOwnerCustom(req, s.GetMgmtPrivateServiceClient(), s.GetRedisClient())
+	if err != nil {
+		sta := status.Convert(err)
+		switch sta.Code() {
+		case codes.NotFound:
+			makeJSONResponse(w, 404, "Not found", "User not found")
+			span.SetStatus(1, "User not found")
+			return
+		default:
+			makeJSONResponse(w, 401, "Unauthorized", "Required parameter 'jwt-sub' or 'owner-id' not found in your header")
+			span.SetStatus(1, "Required parameter 'jwt-sub' or 'owner-id' not found in your header")
+			return
+		}
+	}
+	ownerPermalink := GenOwnerPermalink(owner)
+
+	modelID, err := resource.GetModelID(pathParams["name"])
+	if err != nil {
+		makeJSONResponse(w, 400, "Parameter invalid", "Required parameter model name is invalid")
+		span.SetStatus(1, "Required parameter model name is invalid")
+		return
+	}
+
+	var body struct {
+		IdleTimeout int `json:"idle_timeout"`
+	}
+	if err := json.NewDecoder(req.Body).Decode(&body); err != nil {
+		makeJSONResponse(w, 400, "Parameter invalid", fmt.Sprintf("Unable to parse the request body: %s", err))
+		span.SetStatus(1, err.Error())
+		return
+	}
+
+	dbModel, err := s.SetModelIdleTimeout(ctx, ownerPermalink, modelID, body.IdleTimeout)
+	if err != nil {
+		sta := status.Convert(err)
+		switch sta.Code() {
+		case codes.NotFound:
+			makeJSONResponse(w, 404, "Model not found", sta.Message())
+		case codes.InvalidArgument:
+			makeJSONResponse(w, 400, "Parameter invalid", sta.Message())
+		default:
+			makeJSONResponse(w, 500, "Internal Error", sta.Message())
+		}
+		span.SetStatus(1, err.Error())
+		return
+	}
+
+	res, err := json.Marshal(map[string]interface{}{
+		"name":           fmt.Sprintf("models/%s", dbModel.ID),
+		"idle_timeout":   dbModel.IdleTimeout,
+		"scaled_to_zero": dbModel.ScaledToZero,
+	})
+	if err != nil {
+		makeJSONResponse(w, 500, "Internal Error", err.Error())
+		span.SetStatus(1, err.Error())
+		return
+	}
+
+	logger.Info(string(custom_otel.NewLogMessage(
+		span,
+		logUUID.String(),
+		owner,
+		eventName,
+		custom_otel.SetEventResource(dbModel),
+		custom_otel.SetEventMessage(fmt.Sprintf("%s done", eventName)),
+	)))
+
+	w.Header().Add("Content-Type", "application/json")
+	w.WriteHeader(200)
+	_, _ = w.Write(res)
+}
+
+// HandleSetModelDeployConfig is a custom handler that sets the instance group, batching and device kind
+// applied on top of the shipped Triton model configurations when the model is deployed
+func HandleSetModelDeployConfig(s service.Service, w http.ResponseWriter, req *http.Request, pathParams map[string]string) {
+
+	eventName := "HandleSetModelDeployConfig"
+
+	ctx, span := tracer.Start(req.Context(), eventName,
+		trace.WithSpanKind(trace.SpanKindServer))
+	defer span.End()
+
+	logUUID, _ := uuid.NewV4()
+
+	logger, _ := logger.GetZapLogger(ctx)
+
+	owner, err := resource.GetOwnerCustom(req, s.GetMgmtPrivateServiceClient(), s.GetRedisClient())
+	if err != nil {
+		sta := status.Convert(err)
+		switch sta.Code() {
+		case codes.NotFound:
+			makeJSONResponse(w, 404, "Not found", "User not found")
+			span.SetStatus(1, "User not found")
+			return
+		default:
+			makeJSONResponse(w, 401, "Unauthorized", "Required parameter 'jwt-sub' or 'owner-id' not found in your header")
+			span.SetStatus(1, "Required parameter 'jwt-sub' or 'owner-id' not found in your header")
+			return
+		}
+	}
+	ownerPermalink := GenOwnerPermalink(owner)
+
+	modelID, err := resource.GetModelID(pathParams["name"])
+	if err != nil {
+		makeJSONResponse(w, 400, "Parameter invalid", "Required parameter model name is invalid")
+		span.SetStatus(1, "Required parameter model name is invalid")
+		return
+	}
+
+	body, err := io.ReadAll(req.Body)
+	if err != nil {
+		makeJSONResponse(w, 400, "Parameter invalid", fmt.Sprintf("Unable to read the request body: %s", err))
+		span.SetStatus(1, err.Error())
+		return
+	}
+	if err := datamodel.ValidateJSONSchemaString(datamodel.ModelDeployConfigJSONSchema, string(body)); err != nil {
+		makeJSONResponse(w, 400, "Parameter invalid", fmt.Sprintf("Deploy configuration is invalid: %s", err))
+		span.SetStatus(1, err.Error())
+		return
+	}
+	var deployConfig datamodel.ModelDeployConfiguration
+	if err := json.Unmarshal(body, &deployConfig); err != nil {
+		makeJSONResponse(w, 400, "Parameter invalid", fmt.Sprintf("Unable to parse the request body: %s", err))
+		span.SetStatus(1, err.Error())
+		return
+	}
+
+	dbModel, err := s.SetModelDeployConfig(ctx, ownerPermalink, modelID, deployConfig)
+	if err != nil {
+		sta := status.Convert(err)
+		switch sta.Code() {
+		case codes.NotFound:
+			makeJSONResponse(w, 404, "Model not found", sta.Message())
+		case codes.InvalidArgument:
+			makeJSONResponse(w, 400, "Parameter invalid", sta.Message())
+		default:
+			makeJSONResponse(w, 500, "Internal Error", sta.Message())
+		}
+		span.SetStatus(1, err.Error())
+		return
+	}
+
+	res, err := json.Marshal(map[string]interface{}{
+		"name":          fmt.Sprintf("models/%s", dbModel.ID),
+		"deploy_config": dbModel.DeployConfig,
+	})
+	if err != nil {
+		makeJSONResponse(w, 500, "Internal Error", err.Error())
+		span.SetStatus(1, err.Error())
+		return
+	}
+
+	logger.Info(string(custom_otel.NewLogMessage(
+		span,
+		logUUID.String(),
+		owner,
+		eventName,
+		custom_otel.SetEventResource(dbModel),
+		custom_otel.SetEventMessage(fmt.Sprintf("%s done", eventName)),
+	)))
+
+	w.Header().Add("Content-Type", "application/json")
+	w.WriteHeader(200)
+	_, _ = w.Write(res)
+}
+
 func (h *PublicHandler) GetModelCard(ctx context.Context, req *modelPB.GetModelCardRequest) (*modelPB.GetModelCardResponse, error) {
 
 	eventName := "GetModelCard"
@@ -3113,16 +3250,139 @@ func (h *PublicHandler) GetModelOperation(ctx context.Context, req *modelPB.GetM
 		trace.WithSpanKind(trace.SpanKindServer))
 	defer span.End()
 
-	operationId, err := resource.GetOperationID(req.Name)
+	logger, _ := logger.GetZapLogger(ctx)
+
+	owner, err := resource.GetOwner(ctx, h.service.GetMgmtPrivateServiceClient(), h.service.GetRedisClient())
 	if err != nil {
+		span.SetStatus(1, err.Error())
 		return &modelPB.GetModelOperationResponse{}, err
 	}
-	operation, err := h.service.GetOperation(ctx, operationId)
+
+	operationId, err := resource.GetOperationID(req.Name)
 	if err != nil {
 		return &modelPB.GetModelOperationResponse{}, err
 	}
+	operation, dbModel, err := h.service.GetOperation(ctx, GenOwnerPermalink(owner), operationId)
+	if status.Code(err) == codes.NotFound {
+		st, err := sterr.CreateErrorResourceInfo(
+			codes.NotFound,
+			"[handler] operation not found",
+			"operation",
+			req.Name,
+			"",
+			"operation not found",
+		)
+		if err != nil {
+			logger.Error(err.Error())
+		}
+		span.SetStatus(1, st.Err().Error())
+		return &modelPB.GetModelOperationResponse{}, st.Err()
+	} else if err != nil {
+		span.SetStatus(1, err.Error())
+		return &modelPB.GetModelOperationResponse{}, err
+	}
+
+	if err := setOperationResponse(ctx, h.service, operation, dbModel); err != nil {
+		span.SetStatus(1, err.Error())
+		return &modelPB.GetModelOperationResponse{}, err
+	}
 
 	return &modelPB.GetModelOperationResponse{
 		Operation: operation,
 	}, nil
 }
+
+// HandleExportModel is a custom handler that streams the model package of a model as an archive in the format query
+// parameter, zip by default, so that the model can be created again on another instance
+func HandleExportModel(s service.Service, w http.ResponseWriter, req *http.Request, pathParams map[string]string) {
+
+	eventName := "HandleExportModel"
+
+	ctx, span := tracer.Start(req.Context(), eventName,
+		trace.WithSpanKind(trace.SpanKindServer))
+	defer span.End()
+
+	logUUID, _ := uuid.NewV4()
+
+	logger, _ := logger.GetZapLogger(ctx)
+
+	owner, err := resource.GetOwnerCustom(req, s.GetMgmtPrivateServiceClient(), s.GetRedisClient())
+	if err != nil {
+		sta := status.Convert(err)
+		switch sta.Code() {
+		case codes.NotFound:
+			makeJSONResponse(w, 404, "Not found", "User not found")
+			span.SetStatus(1, "User not found")
+			return
+		default:
+			makeJSONResponse(w, 401, "Unauthorized", "Required parameter 'jwt-sub' or 'owner-id' not found in your header")
+			span.SetStatus(1, "Required parameter 'jwt-sub' or 'owner-id' not found in your header")
+			return
+		}
+	}
+	ownerPermalink := GenOwnerPermalink(owner)
+
+	modelID, err := resource.GetModelID(pathParams["name"])
+	if err != nil {
+		makeJSONResponse(w, 400, "Parameter invalid", "Required parameter model name is invalid")
+		span.SetStatus(1, "Required parameter model name is invalid")
+		return
+	}
+	format := req.URL.Query().Get("format")
+	if format == "" {
+		format = "zip"
+	}
+	contentType, ok := util.ModelArchiveFormats[format]
+	if !ok {
+		makeJSONResponse(w, 400, "Parameter invalid", fmt.Sprintf("unsupported archive format %q, only zip and tar.gz are supported", format))
+		span.SetStatus(1, "unsupported archive format")
+		return
+	}
+
+	dbModel, err := s.GetModelByID(ctx, ownerPermalink, modelID, modelPB.View_VIEW_FULL)
+	if err != nil {
+		makeJSONResponse(w, 404, "Model not found", fmt.Sprintf("The model %s is not found", modelID))
+		span.SetStatus(1, err.Error())
+		return
+	}
+	modelDef, err := s.GetModelDefinitionByUID(ctx, dbModel.ModelDefinitionUid)
+	if err != nil {
+		makeJSONResponse(w, 500, "Internal Error", err.Error())
+		span.SetStatus(1, err.Error())
+		return
+	}
+	if entries, err := util.GetModelPackageEntries(config.Config.TritonServer.ModelStore, ownerPermalink, &dbModel); err != nil || len(entries) == 0 {
+		makeJSONResponse(w, 409, "Export Model Error", fmt.Sprintf("The files of the model %s are not in the model repository", modelID))
+		span.SetStatus(1, "model files not found")
+		return
+	}
+
+	manifest := util.ModelExportManifest{
+		ID:              dbModel.ID,
+		ModelDefinition: fmt.Sprintf("model-definitions/%s", modelDef.ID),
+		Description:     dbModel.Description.String,
+		Task:            modelPB.Model_Task(dbModel.Task).String(),
+		Visibility:      modelPB.Model_Visibility(dbModel.Visibility).String(),
+		Configuration:   util.GetExportedModelConfiguration(dbModel.Configuration),
+		ExportTime:      time.Now().UTC().Format(time.RFC3339),
+	}
+
+	w.Header().Add("Content-Type", contentType)
+	w.Header().Add("Content-Disposition", fmt.Sprintf("attachment; filename=\"%s.%s\"", dbModel.ID, format))
+	w.WriteHeader(200)
+	// the response is already streamed, an error can only abort it
+	if err := util.ExportModelArchive(w, format, config.Config.TritonServer.ModelStore, ownerPermalink, &dbModel, &manifest); err != nil {
+		logger.Error(fmt.Sprintf("unable to export the model %s: %s", modelID, err))
+		span.SetStatus(1, err.Error())
+		return
+	}
+
+	logger.Info(string(custom_otel.NewLogMessage(
+		span,
+		logUUID.String(),
+		owner,
+		eventName,
+		custom_otel.SetEventResource(dbModel),
+		custom_otel.SetEventMessage(fmt.Sprintf("%s done", eventName)),
+	)))
+}
@@ -0,0 +1,151 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/instill-ai/model-backend/internal/resource"
+	"github.com/instill-ai/model-backend/pkg/service"
+)
+
+// modelScheduleRequest is the body of a request creating a model schedule
+type modelScheduleRequest struct {
+	Kind     string `json:"kind"`
+	Cron     string `json:"cron"`
+	TimeZone string `json:"time_zone"`
+}
+
+func writeModelSchedules(w http.ResponseWriter, span trace.Span, statusCode int, v interface{}) {
+	obj, err := json.Marshal(v)
+	if err != nil {
+		makeJSONResponse(w, 500, "Internal Error", err.Error())
+		span.SetStatus(1, err.Error())
+		return
+	}
+	w.Header().Add("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	_, _ = w.Write(obj)
+}
+
+// HandleCreateModelSchedule is a custom handler that creates a schedule deploying or undeploying a model of the
+// requester at the times of a cron expression in a time zone, UTC when empty
+func HandleCreateModelSchedule(s service.Service, w http.ResponseWriter, req *http.Request, pathParams map[string]string) {
+
+	eventName := "HandleCreateModelSchedule"
+
+	ctx, span := tracer.Start(req.Context(), eventName,
+		trace.WithSpanKind(trace.SpanKindServer))
+	defer span.End()
+
+	owner, ok := getCustomOwner(s, w, req, span)
+	if !ok {
+		return
+	}
+
+	modelID, err := resource.GetModelID(pathParams["name"])
+	if err != nil {
+		makeJSONResponse(w, 400, "Parameter invalid", "Required parameter model name is invalid")
+		span.SetStatus(1, "Required parameter model name is invalid")
+		return
+	}
+
+	body, err := io.ReadAll(req.Body)
+	if err != nil {
+		makeJSONResponse(w, 400, "Parameter invalid", fmt.Sprintf("Unable to read the request body: %s", err))
+		span.SetStatus(1, err.Error())
+		return
+	}
+	var scheduleReq modelScheduleRequest
+	if err := json.Unmarshal(body, &scheduleReq); err != nil {
+		makeJSONResponse(w, 400, "Parameter invalid", fmt.Sprintf("Unable to parse the request body: %s", err))
+		span.SetStatus(1, err.Error())
+		return
+	}
+
+	schedule, err := s.CreateModelSchedule(ctx, GenOwnerPermalink(owner), modelID, scheduleReq.Kind, scheduleReq.Cron, scheduleReq.TimeZone)
+	if err != nil {
+		writeOperationError(w, span, err)
+		return
+	}
+	writeModelSchedules(w, span, http.StatusCreated, schedule)
+}
+
+// HandleListModelSchedules is a custom handler that lists the schedules of the models of the requester with the
+// outcome of their recent runs, filtered by the model query parameter
+func HandleListModelSchedules(s service.Service, w http.ResponseWriter, req *http.Request, pathParams map[string]string) {
+
+	eventName := "HandleListModelSchedules"
+
+	ctx, span := tracer.Start(req.Context(), eventName,
+		trace.WithSpanKind(trace.SpanKindServer))
+	defer span.End()
+
+	owner, ok := getCustomOwner(s, w, req, span)
+	if !ok {
+		return
+	}
+
+	schedules, err := s.ListModelSchedules(ctx, GenOwnerPermalink(owner), strings.TrimPrefix(req.URL.Query().Get("model"), "models/"))
+	if err != nil {
+		writeOperationError(w, span, err)
+		return
+	}
+	writeModelSchedules(w, span, http.StatusOK, map[string]interface{}{
+		"schedules": schedules,
+	})
+}
+
+// HandlePauseModelSchedule is a custom handler that pauses a schedule of the requester
+func HandlePauseModelSchedule(s service.Service, w http.ResponseWriter, req *http.Request, pathParams map[string]string) {
+	pauseModelSchedule(s, w, req, pathParams, "HandlePauseModelSchedule", true)
+}
+
+// HandleUnpauseModelSchedule is a custom handler that resumes a paused schedule of the requester
+func HandleUnpauseModelSchedule(s service.Service, w http.ResponseWriter, req *http.Request, pathParams map[string]string) {
+	pauseModelSchedule(s, w, req, pathParams, "HandleUnpauseModelSchedule", false)
+}
+
+func pauseModelSchedule(s service.Service, w http.ResponseWriter, req *http.Request, pathParams map[string]string, eventName string, paused bool) {
+
+	ctx, span := tracer.Start(req.Context(), eventName,
+		trace.WithSpanKind(trace.SpanKindServer))
+	defer span.End()
+
+	owner, ok := getCustomOwner(s, w, req, span)
+	if !ok {
+		return
+	}
+
+	schedule, err := s.PauseModelSchedule(ctx, GenOwnerPermalink(owner), strings.TrimPrefix(pathParams["name"], "schedules/"), paused)
+	if err != nil {
+		writeOperationError(w, span, err)
+		return
+	}
+	writeModelSchedules(w, span, http.StatusOK, schedule)
+}
+
+// HandleDeleteModelSchedule is a custom handler that deletes a schedule of the requester
+func HandleDeleteModelSchedule(s service.Service, w http.ResponseWriter, req *http.Request, pathParams map[string]string) {
+
+	eventName := "HandleDeleteModelSchedule"
+
+	ctx, span := tracer.Start(req.Context(), eventName,
+		trace.WithSpanKind(trace.SpanKindServer))
+	defer span.End()
+
+	owner, ok := getCustomOwner(s, w, req, span)
+	if !ok {
+		return
+	}
+
+	if err := s.DeleteModelSchedule(ctx, GenOwnerPermalink(owner), strings.TrimPrefix(pathParams["name"], "schedules/")); err != nil {
+		writeOperationError(w, span, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,546 @@
+package handler
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"cloud.google.com/go/longrunning/autogen/longrunningpb"
+	"github.com/gofrs/uuid"
+	"github.com/santhosh-tekuri/jsonschema/v5"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/types/known/anypb"
+
+	"github.com/instill-ai/model-backend/config"
+	"github.com/instill-ai/model-backend/internal/resource"
+	"github.com/instill-ai/model-backend/pkg/datamodel"
+	"github.com/instill-ai/model-backend/pkg/logger"
+	"github.com/instill-ai/model-backend/pkg/service"
+	"github.com/instill-ai/model-backend/pkg/util"
+	"github.com/instill-ai/x/sterr"
+
+	custom_otel "github.com/instill-ai/model-backend/pkg/logger/otel"
+	mgmtPB "github.com/instill-ai/protogen-go/base/mgmt/v1alpha"
+	modelPB "github.com/instill-ai/protogen-go/model/model/v1alpha"
+)
+
+// uploadError is the JSON error response of the model upload custom handlers
+type uploadError struct {
+	status int
+	title  string
+	detail string
+}
+
+// newUploadedModel validates the local model to create from an uploaded archive
+func newUploadedModel(ctx context.Context, s service.Service, ownerPermalink string, modelID string, modelDefinitionID string, visibility modelPB.Model_Visibility, description string, filename string) (*datamodel.Model, *uploadError) {
+	localModelDefinition, err := s.GetRepository().GetModelDefinition(modelDefinitionID)
+	if err != nil {
+		return nil, &uploadError{400, "Parameter invalid", "ModelDefinitionId not found"}
+	}
+	rs := &jsonschema.Schema{}
+	if err := json.Unmarshal([]byte(localModelDefinition.ModelSpec.String()), rs); err != nil {
+		return nil, &uploadError{500, "Add Model Error", "Could not get model definition"}
+	}
+	modelConfiguration := datamodel.LocalModelConfiguration{
+		Content: filename,
+	}
+
+	if err := datamodel.ValidateJSONSchema(rs, modelConfiguration, true); err != nil {
+		return nil, &uploadError{400, "Add Model Error", fmt.Sprintf("Model configuration is invalid %v", err.Error())}
+	}
+	modelConfiguration.Tag = "latest" // Set after validation. Because the model definition do not contain tag.
+
+	bModelConfig, _ := json.Marshal(modelConfiguration)
+	uploadedModel := datamodel.Model{
+		ID:                 modelID,
+		ModelDefinitionUid: localModelDefinition.UID,
+		Owner:              ownerPermalink,
+		Visibility:         datamodel.ModelVisibility(visibility),
+		State:              datamodel.ModelState(modelPB.Model_STATE_OFFLINE),
+		Description: sql.NullString{
+			String: description,
+			Valid:  true,
+		},
+		Configuration: bModelConfig,
+	}
+
+	// Validate ModelDefinition JSON Schema
+	if err := datamodel.ValidateJSONSchema(datamodel.ModelJSONSchema, DBModelToPBModel(ctx, &localModelDefinition, &uploadedModel, ownerPermalink), true); err != nil {
+		return nil, &uploadError{400, "Add Model Error", fmt.Sprintf("Model definition is invalid %v", err.Error())}
+	}
+
+	if _, err := s.GetModelByID(ctx, ownerPermalink, uploadedModel.ID, modelPB.View_VIEW_FULL); err == nil {
+		return nil, &uploadError{409, "Add Model Error", fmt.Sprintf("The model %v already existed", uploadedModel.ID)}
+	}
+
+	return &uploadedModel, nil
+}
+
+// extractModelArchive extracts an uploaded archive into a model store, takes the model task from its README.md and
+// lints its Triton models. The model files are removed on error, they are kept with the violations found.
+func extractModelArchive(ctx context.Context, modelStore string, ownerPermalink string, uploadedModel *datamodel.Model, archive string) ([]util.ModelPackageViolation, *uploadError) {
+	logger, _ := logger.GetZapLogger(ctx)
+
+	tag := "latest"
+	readmeFilePath, ensembleFilePath, err := util.ExtractModelArchive(archive, modelStore, ownerPermalink, uploadedModel)
+	if err != nil {
+		util.RemoveModelRepository(modelStore, ownerPermalink, uploadedModel.ID, tag)
+		return nil, &uploadError{400, "Add Model Error", err.Error()}
+	}
+	if _, err := os.Stat(readmeFilePath); err == nil {
+		modelMeta, err := util.GetModelMetaFromReadme(readmeFilePath)
+		if err != nil {
+			util.RemoveModelRepository(modelStore, ownerPermalink, uploadedModel.ID, tag)
+			return nil, &uploadError{400, "Add Model Error", err.Error()}
+		}
+		if modelMeta.Task == "" {
+			uploadedModel.Task = datamodel.ModelTask(modelPB.Model_TASK_UNSPECIFIED)
+		} else {
+			if val, ok := util.Tasks[fmt.Sprintf("TASK_%v", strings.ToUpper(modelMeta.Task))]; ok {
+				uploadedModel.Task = datamodel.ModelTask(val)
+			} else {
+				util.RemoveModelRepository(modelStore, ownerPermalink, uploadedModel.ID, tag)
+				return nil, &uploadError{400, "Add Model Error", "README.md contains unsupported task"}
+			}
+		}
+	} else {
+		uploadedModel.Task = datamodel.ModelTask(modelPB.Model_TASK_UNSPECIFIED)
+	}
+
+	maxBatchSize := 0
+	if ensembleFilePath != "" {
+		maxBatchSize, err = util.GetMaxBatchSize(ensembleFilePath)
+		if err != nil {
+			st, e := sterr.CreateErrorResourceInfo(
+				codes.FailedPrecondition,
+				"[handler] create a model error",
+				"Local model",
+				"Missing ensemble model",
+				"",
+				"err.Error()",
+			)
+			if e != nil {
+				logger.Error(e.Error())
+			}
+			util.RemoveModelRepository(modelStore, ownerPermalink, uploadedModel.ID, tag)
+			obj, _ := json.Marshal(st.Details())
+			return nil, &uploadError{400, st.Message(), string(obj)}
+		}
+	}
+
+	violations := util.ValidateModelPackage(modelStore, ownerPermalink, uploadedModel)
+	if allowedMaxBatchSize := util.GetSupportedBatchSize(uploadedModel.Task); maxBatchSize > allowedMaxBatchSize {
+		violations = append(violations, util.ModelPackageViolation{
+			Type:        "MAX BATCH SIZE LIMITATION",
+			Subject:     "Create a model error",
+			Description: fmt.Sprintf("The max_batch_size in config.pbtxt exceeded the limitation %v, please try with a smaller max_batch_size", allowedMaxBatchSize),
+		})
+	}
+
+	return violations, nil
+}
+
+// createModelFromArchive extracts an uploaded archive into the model store and starts the creation workflow of the
+// model, the model files are removed on failure
+func createModelFromArchive(ctx context.Context, s service.Service, ownerPermalink string, uploadedModel *datamodel.Model, archive string) (string, *uploadError) {
+	violations, e := extractModelArchive(ctx, config.Config.TritonServer.ModelStore, ownerPermalink, uploadedModel, archive)
+	if e != nil {
+		return "", e
+	}
+	if len(violations) > 0 {
+		util.RemoveModelRepository(config.Config.TritonServer.ModelStore, ownerPermalink, uploadedModel.ID, "latest")
+		st := modelPackageError(violations)
+		obj, _ := json.Marshal(st.Details())
+		return "", &uploadError{400, st.Message(), string(obj)}
+	}
+
+	wfId, err := s.CreateModelAsync(ctx, ownerPermalink, uploadedModel)
+	if err != nil {
+		util.RemoveModelRepository(config.Config.TritonServer.ModelStore, ownerPermalink, uploadedModel.ID, "latest")
+		return "", &uploadError{500, "Add Model Error", err.Error()}
+	}
+
+	return wfId, nil
+}
+
+// validateModelArchive runs the creation checks of a model on an uploaded archive extracted into a scratch model
+// store and writes the report, the model is not created
+func validateModelArchive(ctx context.Context, w http.ResponseWriter, ownerPermalink string, uploadedModel *datamodel.Model, archive string) *uploadError {
+	modelStore, err := os.MkdirTemp(util.TMP_DIR, "model-store-")
+	if err != nil {
+		return &uploadError{500, "Validate Model Error", err.Error()}
+	}
+	defer os.RemoveAll(modelStore)
+
+	violations, e := extractModelArchive(ctx, modelStore, ownerPermalink, uploadedModel, archive)
+	if e != nil {
+		return e
+	}
+	tritonModels := []string{}
+	for _, tm := range uploadedModel.TritonModels {
+		tritonModels = append(tritonModels, strings.Split(tm.Name, "#")[2])
+	}
+	obj, err := json.Marshal(map[string]interface{}{
+		"valid":         len(violations) == 0,
+		"task":          modelPB.Model_Task(uploadedModel.Task).String(),
+		"triton_models": tritonModels,
+		"violations":    violations,
+	})
+	if err != nil {
+		return &uploadError{500, "Validate Model Error", err.Error()}
+	}
+
+	w.Header().Add("Content-Type", "application/json")
+	w.WriteHeader(200)
+	_, _ = w.Write(obj)
+	return nil
+}
+
+// getUploadOwner returns the owner of an upload session request, an error response is written when there is none
+func getCustomOwner(s service.Service, w http.ResponseWriter, req *http.Request, span trace.Span) (*mgmtPB.User, bool) {
+	owner, err := resource.GetOwnerCustom(req, s.GetMgmtPrivateServiceClient(), s.GetRedisClient())
+	if err != nil {
+		sta := status.Convert(err)
+		switch sta.Code() {
+		case codes.NotFound:
+			makeJSONResponse(w, 404, "Not found", "User not found")
+			span.SetStatus(1, "User not found")
+		default:
+			makeJSONResponse(w, 401, "Unauthorized", "Required parameter 'jwt-sub' or 'owner-id' not found in your header")
+			span.SetStatus(1, "Required parameter 'jwt-sub' or 'owner-id' not found in your header")
+		}
+		return nil, false
+	}
+	return owner, true
+}
+
+// getUploadSessionUID returns the UID of an upload session resource name, uploads/{uid}
+func getUploadSessionUID(name string) string {
+	return strings.TrimPrefix(name, "uploads/")
+}
+
+func writeUploadSession(w http.ResponseWriter, statusCode int, session *util.UploadSession) {
+	obj, err := json.Marshal(map[string]interface{}{
+		"name":             fmt.Sprintf("uploads/%s", session.UID),
+		"model_id":         session.ModelID,
+		"model_definition": fmt.Sprintf("model-definitions/%s", session.ModelDefinitionID),
+		"filename":         session.Filename,
+		"size":             session.Size,
+		"sha256":           session.Sha256,
+		"received_size":    session.ReceivedSize(),
+		"received_ranges":  session.ReceivedRanges,
+		"complete":         session.IsComplete(),
+		"create_time":      session.CreateTime.Format(time.RFC3339),
+		"expire_time":      session.ExpireTime.Format(time.RFC3339),
+	})
+	if err != nil {
+		makeJSONResponse(w, 500, "Internal Error", err.Error())
+		return
+	}
+
+	w.Header().Add("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	_, _ = w.Write(obj)
+}
+
+func writeUploadSessionError(w http.ResponseWriter, span trace.Span, err error) {
+	if errors.Is(err, util.ErrUploadSessionNotFound) {
+		makeJSONResponse(w, 404, "Not found", err.Error())
+	} else {
+		makeJSONResponse(w, 500, "Internal Error", err.Error())
+	}
+	span.SetStatus(1, err.Error())
+}
+
+// HandleCreateModelUploadSession is a custom handler that starts a resumable upload of a local model archive
+func HandleCreateModelUploadSession(s service.Service, w http.ResponseWriter, req *http.Request, pathParams map[string]string) {
+
+	eventName := "HandleCreateModelUploadSession"
+
+	ctx, span := tracer.Start(req.Context(), eventName,
+		trace.WithSpanKind(trace.SpanKindServer))
+	defer span.End()
+
+	logUUID, _ := uuid.NewV4()
+
+	logger, _ := logger.GetZapLogger(ctx)
+
+	owner, ok := getCustomOwner(s, w, req, span)
+	if !ok {
+		return
+	}
+	ownerPermalink := GenOwnerPermalink(owner)
+
+	var body struct {
+		ID              string `json:"id"`
+		ModelDefinition string `json:"model_definition"`
+		Visibility      string `json:"visibility"`
+		Description     string `json:"description"`
+		Filename        string `json:"filename"`
+		Size            int64  `json:"size"`
+		Sha256          string `json:"sha256"`
+	}
+	if err := json.NewDecoder(req.Body).Decode(&body); err != nil {
+		makeJSONResponse(w, 400, "Parameter invalid", fmt.Sprintf("Unable to parse the request body: %s", err))
+		span.SetStatus(1, err.Error())
+		return
+	}
+	if body.ID == "" {
+		makeJSONResponse(w, 400, "Missing parameter", "Model Id need to be specified")
+		span.SetStatus(1, "Model Id need to be specified")
+		return
+	}
+	modelDefinitionID, err := resource.GetDefinitionID(body.ModelDefinition)
+	if err != nil {
+		makeJSONResponse(w, 400, "Invalid parameter", err.Error())
+		span.SetStatus(1, err.Error())
+		return
+	}
+	visibility := modelPB.Model_VISIBILITY_PRIVATE
+	if body.Visibility != "" {
+		if visibility = util.Visibility[body.Visibility]; visibility == modelPB.Model_VISIBILITY_UNSPECIFIED {
+			makeJSONResponse(w, 400, "Invalid parameter", "Visibility is invalid")
+			span.SetStatus(1, "Visibility is invalid")
+			return
+		}
+	}
+
+	// the model is validated before any chunk is uploaded
+	if _, e := newUploadedModel(ctx, s, ownerPermalink, body.ID, modelDefinitionID, visibility, body.Description, body.Filename); e != nil {
+		makeJSONResponse(w, e.status, e.title, e.detail)
+		span.SetStatus(1, e.detail)
+		return
+	}
+
+	session := util.UploadSession{
+		Owner:             ownerPermalink,
+		ModelID:           body.ID,
+		ModelDefinitionID: modelDefinitionID,
+		Visibility:        body.Visibility,
+		Description:       body.Description,
+		Filename:          body.Filename,
+		Size:              body.Size,
+		Sha256:            body.Sha256,
+	}
+	if err := util.CreateUploadSession(&session); err != nil {
+		makeJSONResponse(w, 400, "Parameter invalid", err.Error())
+		span.SetStatus(1, err.Error())
+		return
+	}
+
+	logger.Info(string(custom_otel.NewLogMessage(
+		span,
+		logUUID.String(),
+		owner,
+		eventName,
+		custom_otel.SetEventMessage(fmt.Sprintf("%s done, upload session %s created for model %s", eventName, session.UID, session.ModelID)),
+	)))
+
+	writeUploadSession(w, 201, &session)
+}
+
+// HandleGetModelUploadSession is a custom handler that returns an upload session with its received ranges
+func HandleGetModelUploadSession(s service.Service, w http.ResponseWriter, req *http.Request, pathParams map[string]string) {
+
+	eventName := "HandleGetModelUploadSession"
+
+	_, span := tracer.Start(req.Context(), eventName,
+		trace.WithSpanKind(trace.SpanKindServer))
+	defer span.End()
+
+	owner, ok := getCustomOwner(s, w, req, span)
+	if !ok {
+		return
+	}
+
+	session, err := util.GetUploadSession(getUploadSessionUID(pathParams["name"]), GenOwnerPermalink(owner))
+	if err != nil {
+		writeUploadSessionError(w, span, err)
+		return
+	}
+
+	writeUploadSession(w, 200, session)
+}
+
+// HandleUploadModelChunk is a custom handler that writes the request body at the offset query parameter of an
+// upload session, the X-Content-Sha256 header is the hex encoded sha256 checksum of the chunk
+func HandleUploadModelChunk(s service.Service, w http.ResponseWriter, req *http.Request, pathParams map[string]string) {
+
+	eventName := "HandleUploadModelChunk"
+
+	_, span := tracer.Start(req.Context(), eventName,
+		trace.WithSpanKind(trace.SpanKindServer))
+	defer span.End()
+
+	owner, ok := getCustomOwner(s, w, req, span)
+	if !ok {
+		return
+	}
+
+	offset, err := strconv.ParseInt(req.URL.Query().Get("offset"), 10, 64)
+	if err != nil {
+		makeJSONResponse(w, 400, "Parameter invalid", fmt.Sprintf("invalid offset value: %s", req.URL.Query().Get("offset")))
+		span.SetStatus(1, err.Error())
+		return
+	}
+	checksum := req.Header.Get("X-Content-Sha256")
+	if checksum == "" {
+		makeJSONResponse(w, 400, "Missing parameter", "The X-Content-Sha256 header need to be specified")
+		span.SetStatus(1, "The X-Content-Sha256 header need to be specified")
+		return
+	}
+
+	uid := getUploadSessionUID(pathParams["name"])
+	ownerPermalink := GenOwnerPermalink(owner)
+	if _, err := util.GetUploadSession(uid, ownerPermalink); err != nil {
+		writeUploadSessionError(w, span, err)
+		return
+	}
+	session, err := util.WriteUploadChunk(uid, ownerPermalink, offset, req.Body, checksum)
+	if err != nil {
+		if errors.Is(err, util.ErrUploadSessionNotFound) {
+			writeUploadSessionError(w, span, err)
+			return
+		}
+		makeJSONResponse(w, 400, "Upload Error", err.Error())
+		span.SetStatus(1, err.Error())
+		return
+	}
+
+	writeUploadSession(w, 200, session)
+}
+
+// HandleFinalizeModelUploadSession is a custom handler that creates the model of a complete upload session, or only
+// validates it with the validate_only query parameter
+func HandleFinalizeModelUploadSession(s service.Service, w http.ResponseWriter, req *http.Request, pathParams map[string]string) {
+
+	eventName := "HandleFinalizeModelUploadSession"
+
+	ctx, span := tracer.Start(req.Context(), eventName,
+		trace.WithSpanKind(trace.SpanKindServer))
+	defer span.End()
+
+	logUUID, _ := uuid.NewV4()
+
+	logger, _ := logger.GetZapLogger(ctx)
+
+	owner, ok := getCustomOwner(s, w, req, span)
+	if !ok {
+		return
+	}
+	ownerPermalink := GenOwnerPermalink(owner)
+
+	uid := getUploadSessionUID(pathParams["name"])
+	if _, err := util.GetUploadSession(uid, ownerPermalink); err != nil {
+		writeUploadSessionError(w, span, err)
+		return
+	}
+	// a session is finalized once, the concurrent requests find it removed
+	unlock, err := util.LockUploadSession(uid)
+	if err != nil {
+		writeUploadSessionError(w, span, err)
+		return
+	}
+	defer unlock()
+	session, err := util.GetUploadSession(uid, ownerPermalink)
+	if err != nil {
+		writeUploadSessionError(w, span, err)
+		return
+	}
+	if err := util.VerifyUploadSession(session); err != nil {
+		makeJSONResponse(w, 412, "Upload Error", err.Error())
+		span.SetStatus(1, err.Error())
+		return
+	}
+
+	visibility := modelPB.Model_VISIBILITY_PRIVATE
+	if session.Visibility != "" {
+		visibility = util.Visibility[session.Visibility]
+	}
+	uploadedModel, e := newUploadedModel(ctx, s, ownerPermalink, session.ModelID, session.ModelDefinitionID, visibility, session.Description, session.Filename)
+	if e != nil {
+		makeJSONResponse(w, e.status, e.title, e.detail)
+		span.SetStatus(1, e.detail)
+		return
+	}
+	// validate_only is a dry run of the creation checks, the session is kept to be finalized
+	if validateOnly, _ := strconv.ParseBool(req.URL.Query().Get("validate_only")); validateOnly {
+		if e := validateModelArchive(ctx, w, ownerPermalink, uploadedModel, util.GetUploadContentPath(session.UID)); e != nil {
+			makeJSONResponse(w, e.status, e.title, e.detail)
+			span.SetStatus(1, e.detail)
+		}
+		return
+	}
+	wfId, e := createModelFromArchive(ctx, s, ownerPermalink, uploadedModel, util.GetUploadContentPath(session.UID))
+	if e != nil {
+		makeJSONResponse(w, e.status, e.title, e.detail)
+		span.SetStatus(1, e.detail)
+		return
+	}
+	if err := util.DeleteUploadSession(session.UID); err != nil {
+		logger.Warn(fmt.Sprintf("unable to remove the upload session %s: %s", session.UID, err))
+	}
+
+	m := protojson.MarshalOptions{UseProtoNames: true, UseEnumNumbers: false, EmitUnpopulated: true}
+	b, err := m.Marshal(&modelPB.CreateModelResponse{Operation: &longrunningpb.Operation{
+		Name: fmt.Sprintf("operations/%s", wfId),
+		Done: false,
+		Result: &longrunningpb.Operation_Response{
+			Response: &anypb.Any{},
+		},
+	}})
+	if err != nil {
+		makeJSONResponse(w, 500, "Add Model Error", err.Error())
+		span.SetStatus(1, err.Error())
+		return
+	}
+
+	logger.Info(string(custom_otel.NewLogMessage(
+		span,
+		logUUID.String(),
+		owner,
+		eventName,
+		custom_otel.SetEventResource(*uploadedModel),
+	)))
+
+	w.Header().Add("Content-Type", "application/json")
+	w.WriteHeader(201)
+	_, _ = w.Write(b)
+}
+
+// HandleDeleteModelUploadSession is a custom handler that aborts an upload session and removes its chunks
+func HandleDeleteModelUploadSession(s service.Service, w http.ResponseWriter, req *http.Request, pathParams map[string]string) {
+
+	eventName := "HandleDeleteModelUploadSession"
+
+	_, span := tracer.Start(req.Context(), eventName,
+		trace.WithSpanKind(trace.SpanKindServer))
+	defer span.End()
+
+	owner, ok := getCustomOwner(s, w, req, span)
+	if !ok {
+		return
+	}
+
+	session, err := util.GetUploadSession(getUploadSessionUID(pathParams["name"]), GenOwnerPermalink(owner))
+	if err != nil {
+		writeUploadSessionError(w, span, err)
+		return
+	}
+	if err := util.DeleteUploadSession(session.UID); err != nil {
+		writeUploadSessionError(w, span, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
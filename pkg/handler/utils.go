@@ -1,7 +1,58 @@
 package handler
 
-import mgmtPB "github.com/instill-ai/protogen-go/base/mgmt/v1alpha"
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/instill-ai/model-backend/pkg/constant"
+	"github.com/instill-ai/model-backend/pkg/datamodel"
+	"github.com/instill-ai/model-backend/pkg/service"
+	"github.com/instill-ai/model-backend/pkg/util"
+	"github.com/instill-ai/x/sterr"
+
+	mgmtPB "github.com/instill-ai/protogen-go/base/mgmt/v1alpha"
+)
 
 func GenOwnerPermalink(owner *mgmtPB.User) string {
 	return "users/" + owner.GetUid()
 }
+
+// ensureModelLoaded loads back a scaled-to-zero model before an inference and returns
+// the response metadata reporting the cold start, if any
+func ensureModelLoaded(ctx context.Context, s service.Service, dbModel *datamodel.Model) (metadata.MD, error) {
+	start := time.Now()
+	coldStart, err := s.EnsureModelLoaded(ctx, dbModel)
+	if err != nil {
+		return nil, err
+	}
+	if !coldStart {
+		return metadata.MD{}, nil
+	}
+	return metadata.Pairs(
+		constant.HeaderModelColdStartKey, "true",
+		constant.HeaderModelColdStartDurationKey, fmt.Sprint(time.Since(start).Milliseconds()),
+	), nil
+}
+
+// modelPackageError returns the precondition failure listing the violations found in a model package
+func modelPackageError(violations []util.ModelPackageViolation) *status.Status {
+	pbViolations := make([]*errdetails.PreconditionFailure_Violation, 0, len(violations))
+	for _, v := range violations {
+		pbViolations = append(pbViolations, &errdetails.PreconditionFailure_Violation{
+			Type:        v.Type,
+			Subject:     v.Subject,
+			Description: v.Description,
+		})
+	}
+	st, err := sterr.CreateErrorPreconditionFailure("[handler] create a model error: invalid model package", pbViolations)
+	if err != nil {
+		return status.New(codes.Internal, err.Error())
+	}
+	return st
+}
@@ -0,0 +1,327 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/gofrs/uuid"
+	"github.com/santhosh-tekuri/jsonschema/v5"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+
+	"github.com/instill-ai/model-backend/config"
+	"github.com/instill-ai/model-backend/internal/resource"
+	"github.com/instill-ai/model-backend/pkg/datamodel"
+	"github.com/instill-ai/model-backend/pkg/logger"
+	"github.com/instill-ai/model-backend/pkg/service"
+	"github.com/instill-ai/model-backend/pkg/util"
+	"github.com/instill-ai/x/checkfield"
+
+	custom_otel "github.com/instill-ai/model-backend/pkg/logger/otel"
+	modelPB "github.com/instill-ai/protogen-go/model/model/v1alpha"
+)
+
+// checkCreateModelRequest checks the model of a creation request against the model resource and the specification
+// of its definition, which it returns
+func checkCreateModelRequest(ctx context.Context, s service.Service, ownerPermalink string, model *modelPB.Model) (*datamodel.ModelDefinition, error) {
+	// Set all OUTPUT_ONLY fields to zero value on the requested payload model resource
+	if err := checkfield.CheckCreateOutputOnlyFields(model, outputOnlyFields); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, err.Error())
+	}
+
+	// Return error if REQUIRED fields are not provided in the requested payload model resource
+	if err := checkfield.CheckRequiredFields(model, requiredFields); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, err.Error())
+	}
+
+	// Return error if resource ID does not follow RFC-1034
+	if err := checkfield.CheckResourceID(model.GetId()); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, err.Error())
+	}
+	// Validate ModelDefinition JSON Schema
+	if err := datamodel.ValidateJSONSchema(datamodel.ModelJSONSchema, model, false); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, err.Error())
+	}
+	if _, err := s.GetModelByID(ctx, ownerPermalink, model.Id, modelPB.View_VIEW_FULL); err == nil {
+		return nil, status.Errorf(codes.AlreadyExists, "Model already existed")
+	}
+
+	if model.Configuration == nil {
+		return nil, status.Errorf(codes.InvalidArgument, "Missing Configuration")
+	}
+
+	modelDefinitionID, err := resource.GetDefinitionID(model.ModelDefinition)
+	if err != nil {
+		return nil, err
+	}
+
+	modelDefinition, err := s.GetModelDefinition(ctx, modelDefinitionID)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, err.Error())
+	}
+
+	// validate model configuration
+	rs := &jsonschema.Schema{}
+	if err := json.Unmarshal([]byte(modelDefinition.ModelSpec.String()), rs); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "Could not get model definition")
+	}
+	if err := datamodel.ValidateJSONSchema(rs, model.GetConfiguration(), true); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, fmt.Sprintf("Model configuration is invalid %v", err.Error()))
+	}
+	return &modelDefinition, nil
+}
+
+// decodeModelConfiguration decodes the configuration of a model into the configuration of its source
+func decodeModelConfiguration(model *modelPB.Model, modelConfig interface{}) error {
+	b, err := model.GetConfiguration().MarshalJSON()
+	if err != nil {
+		return status.Errorf(codes.InvalidArgument, err.Error())
+	}
+	if err := json.Unmarshal(b, modelConfig); err != nil {
+		return status.Errorf(codes.InvalidArgument, err.Error())
+	}
+	return nil
+}
+
+func parseGitHubModelConfiguration(model *modelPB.Model) (datamodel.GitHubModelConfiguration, error) {
+	var modelConfig datamodel.GitHubModelConfiguration
+	if err := decodeModelConfiguration(model, &modelConfig); err != nil {
+		return modelConfig, err
+	}
+	if modelConfig.Repository == "" {
+		return modelConfig, status.Errorf(codes.InvalidArgument, "Invalid GitHub URL")
+	}
+	if _, err := util.GetGitCredential(modelConfig.CredentialRef); err != nil {
+		return modelConfig, status.Errorf(codes.InvalidArgument, err.Error())
+	}
+	return modelConfig, nil
+}
+
+func parseHuggingFaceModelConfiguration(model *modelPB.Model) (datamodel.HuggingFaceModelConfiguration, error) {
+	var modelConfig datamodel.HuggingFaceModelConfiguration
+	if err := decodeModelConfiguration(model, &modelConfig); err != nil {
+		return modelConfig, err
+	}
+	if modelConfig.RepoId == "" {
+		return modelConfig, status.Errorf(codes.InvalidArgument, "Invalid model ID")
+	}
+	modelConfig.HtmlUrl = "https://huggingface.co/" + modelConfig.RepoId
+	modelConfig.Tag = "latest"
+	if _, err := util.GetHuggingFaceCredentialHeaders(modelConfig.CredentialRef); err != nil {
+		return modelConfig, status.Errorf(codes.InvalidArgument, err.Error())
+	}
+	return modelConfig, nil
+}
+
+func parseArtiVCModelConfiguration(model *modelPB.Model) (datamodel.ArtiVCModelConfiguration, error) {
+	var modelConfig datamodel.ArtiVCModelConfiguration
+	if err := decodeModelConfiguration(model, &modelConfig); err != nil {
+		return modelConfig, err
+	}
+	if modelConfig.Url == "" {
+		return modelConfig, status.Errorf(codes.InvalidArgument, "Invalid GitHub URL")
+	}
+	return modelConfig, nil
+}
+
+func parseS3ModelConfiguration(model *modelPB.Model) (datamodel.S3ModelConfiguration, error) {
+	var modelConfig datamodel.S3ModelConfiguration
+	if err := decodeModelConfiguration(model, &modelConfig); err != nil {
+		return modelConfig, err
+	}
+	if modelConfig.Endpoint == "" || modelConfig.Bucket == "" {
+		return modelConfig, status.Errorf(codes.InvalidArgument, "Invalid S3 endpoint or bucket")
+	}
+	modelConfig.Tag = "latest"
+	return modelConfig, nil
+}
+
+func parseGitModelConfiguration(model *modelPB.Model) (datamodel.GitModelConfiguration, error) {
+	var modelConfig datamodel.GitModelConfiguration
+	if err := decodeModelConfiguration(model, &modelConfig); err != nil {
+		return modelConfig, err
+	}
+	if modelConfig.Url == "" || modelConfig.Tag == "" {
+		return modelConfig, status.Errorf(codes.InvalidArgument, "Invalid Git URL or tag")
+	}
+	if err := util.ValidateGitURL(modelConfig.Url); err != nil {
+		return modelConfig, status.Errorf(codes.InvalidArgument, err.Error())
+	}
+	if _, err := util.GetGitCredential(modelConfig.CredentialRef); err != nil {
+		return modelConfig, status.Errorf(codes.InvalidArgument, err.Error())
+	}
+	return modelConfig, nil
+}
+
+func parseURLModelConfiguration(model *modelPB.Model) (datamodel.URLModelConfiguration, error) {
+	var modelConfig datamodel.URLModelConfiguration
+	if err := decodeModelConfiguration(model, &modelConfig); err != nil {
+		return modelConfig, err
+	}
+	if !strings.HasPrefix(modelConfig.Url, "http://") && !strings.HasPrefix(modelConfig.Url, "https://") {
+		return modelConfig, status.Errorf(codes.InvalidArgument, "Invalid archive URL")
+	}
+	if modelConfig.Checksum != "" {
+		if _, _, err := util.ParseChecksum(modelConfig.Checksum); err != nil {
+			return modelConfig, status.Errorf(codes.InvalidArgument, err.Error())
+		}
+	}
+	modelConfig.Tag = "latest"
+	return modelConfig, nil
+}
+
+// validateModelSource checks the configuration of the source of a model and that the source holds what it refers
+// to, without downloading the model
+func validateModelSource(ctx context.Context, modelDefinitionID string, model *modelPB.Model) error {
+	// the integration tests use a local model in place of the remote sources
+	probe := !config.Config.Server.ItMode.Enabled
+	switch modelDefinitionID {
+	case "github":
+		modelConfig, err := parseGitHubModelConfiguration(model)
+		if err != nil || !probe {
+			return err
+		}
+		if _, err := util.ResolveGitRevision(ctx, util.GetGitHubURL(modelConfig.Repository), modelConfig.Tag, modelConfig.CredentialRef); err != nil {
+			return status.Errorf(codes.FailedPrecondition, err.Error())
+		}
+	case "huggingface":
+		modelConfig, err := parseHuggingFaceModelConfiguration(model)
+		if err != nil || !probe {
+			return err
+		}
+		if _, err := util.GetHuggingFaceRepoInfo(ctx, modelConfig.RepoId, modelConfig.Revision, modelConfig.CredentialRef); err != nil {
+			return status.Errorf(codes.FailedPrecondition, err.Error())
+		}
+	case "artivc":
+		modelConfig, err := parseArtiVCModelConfiguration(model)
+		if err != nil {
+			return err
+		}
+		// the ArtiVC repositories are only read from Google Cloud Storage
+		if !strings.HasPrefix(modelConfig.Url, "gs://") {
+			return status.Errorf(codes.InvalidArgument, fmt.Sprintf("not support url %v", modelConfig.Url))
+		}
+	case "s3":
+		modelConfig, err := parseS3ModelConfiguration(model)
+		if err != nil {
+			return err
+		}
+		if !probe {
+			return nil
+		}
+		if err := util.CheckS3Source(ctx, modelConfig); err != nil {
+			return status.Errorf(codes.FailedPrecondition, err.Error())
+		}
+	case "git":
+		modelConfig, err := parseGitModelConfiguration(model)
+		if err != nil || !probe {
+			return err
+		}
+		if _, err := util.ResolveGitRevision(ctx, modelConfig.Url, modelConfig.Tag, modelConfig.CredentialRef); err != nil {
+			return status.Errorf(codes.FailedPrecondition, err.Error())
+		}
+	case "url":
+		modelConfig, err := parseURLModelConfiguration(model)
+		if err != nil {
+			return err
+		}
+		headers, err := util.GetURLCredentialHeaders(modelConfig.CredentialRef)
+		if err != nil {
+			return status.Errorf(codes.InvalidArgument, err.Error())
+		}
+		if !probe {
+			return nil
+		}
+		if err := util.CheckURL(ctx, modelConfig.Url, headers); err != nil {
+			return status.Errorf(codes.FailedPrecondition, err.Error())
+		}
+	case "local":
+		return status.Errorf(codes.InvalidArgument, "the local models are validated by POST /v1alpha/models/multipart with validate_only")
+	default:
+		return status.Errorf(codes.InvalidArgument, fmt.Sprintf("model definition %v is not supported", modelDefinitionID))
+	}
+	return nil
+}
+
+// HandleValidateModel is a dry run of the creation of a model from the body of a CreateModel request: the request,
+// the model definition configuration and the source of the model are checked, and nothing is created
+func HandleValidateModel(s service.Service, w http.ResponseWriter, req *http.Request, pathParams map[string]string) {
+
+	eventName := "HandleValidateModel"
+
+	ctx, span := tracer.Start(req.Context(), eventName,
+		trace.WithSpanKind(trace.SpanKindServer))
+	defer span.End()
+
+	logUUID, _ := uuid.NewV4()
+
+	logger, _ := logger.GetZapLogger(ctx)
+
+	owner, ok := getCustomOwner(s, w, req, span)
+	if !ok {
+		return
+	}
+	ownerPermalink := GenOwnerPermalink(owner)
+
+	body, err := io.ReadAll(req.Body)
+	if err != nil {
+		makeJSONResponse(w, 400, "Parameter invalid", fmt.Sprintf("Unable to read the request body: %s", err))
+		span.SetStatus(1, err.Error())
+		return
+	}
+	model := &modelPB.Model{}
+	if err := protojson.Unmarshal(body, model); err != nil {
+		makeJSONResponse(w, 400, "Parameter invalid", fmt.Sprintf("Unable to parse the request body: %s", err))
+		span.SetStatus(1, err.Error())
+		return
+	}
+
+	modelDefinition, err := checkCreateModelRequest(ctx, s, ownerPermalink, model)
+	if err == nil {
+		err = validateModelSource(ctx, modelDefinition.ID, model)
+	}
+	if err != nil {
+		sta := status.Convert(err)
+		switch sta.Code() {
+		case codes.AlreadyExists:
+			makeJSONResponse(w, 409, "Model already exists", sta.Message())
+		case codes.FailedPrecondition:
+			makeJSONResponse(w, 422, "Model source invalid", sta.Message())
+		case codes.Internal, codes.Unknown:
+			makeJSONResponse(w, 500, "Internal Error", sta.Message())
+		default:
+			makeJSONResponse(w, 400, "Parameter invalid", sta.Message())
+		}
+		span.SetStatus(1, err.Error())
+		return
+	}
+
+	res, err := json.Marshal(map[string]interface{}{
+		"valid":            true,
+		"name":             fmt.Sprintf("models/%s", model.Id),
+		"model_definition": fmt.Sprintf("model-definitions/%s", modelDefinition.ID),
+	})
+	if err != nil {
+		makeJSONResponse(w, 500, "Internal Error", err.Error())
+		span.SetStatus(1, err.Error())
+		return
+	}
+
+	logger.Info(string(custom_otel.NewLogMessage(
+		span,
+		logUUID.String(),
+		owner,
+		eventName,
+		custom_otel.SetEventMessage(fmt.Sprintf("%s done", eventName)),
+	)))
+
+	w.Header().Add("Content-Type", "application/json")
+	w.WriteHeader(200)
+	_, _ = w.Write(res)
+}
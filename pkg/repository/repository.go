@@ -1,11 +1,13 @@
 package repository
 
 import (
+	"context"
 	"time"
 
 	"github.com/gofrs/uuid"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	"gorm.io/datatypes"
 	"gorm.io/gorm"
 
 	"github.com/instill-ai/model-backend/pkg/datamodel"
@@ -21,6 +23,9 @@ type Repository interface {
 	DeleteModel(modelUID uuid.UUID) error
 	UpdateModel(modelUID uuid.UUID, updatedModel datamodel.Model) error
 	UpdateModelState(modelUID uuid.UUID, state datamodel.ModelState) error
+	UpdateModelIdleTimeout(modelUID uuid.UUID, idleTimeout int) error
+	UpdateModelScaledToZero(modelUID uuid.UUID, scaledToZero bool) error
+	UpdateModelDeployConfig(modelUID uuid.UUID, deployConfig datatypes.JSON) error
 	ListModels(owner string, view modelPB.View, pageSize int, pageToken string) (models []datamodel.Model, nextPageToken string, totalSize int64, err error)
 
 	CreateTritonModel(model datamodel.TritonModel) error
@@ -33,6 +38,8 @@ type Repository interface {
 	GetModelByIDAdmin(modelID string, view modelPB.View) (datamodel.Model, error)
 	GetModelByUIDAdmin(modelUID uuid.UUID, view modelPB.View) (datamodel.Model, error)
 	ListModelsAdmin(view modelPB.View, pageSize int, pageToken string) (models []datamodel.Model, nextPageToken string, totalSize int64, err error)
+
+	Ping(ctx context.Context) error
 }
 
 // DefaultPageSize is the default pagination page size when page size is not assigned
@@ -62,6 +69,9 @@ var GetModelSelectedFields = []string{
 	`"model"."owner"`,
 	`"model"."state"`,
 	`"model"."task"`,
+	`"model"."idle_timeout"`,
+	`"model"."scaled_to_zero"`,
+	`"model"."deploy_config"`,
 	`"model"."create_time"`,
 	`"model"."update_time"`,
 }
@@ -76,6 +86,9 @@ var GetModelSelectedFieldsWOConfiguration = []string{
 	`"model"."owner"`,
 	`"model"."state"`,
 	`"model"."task"`,
+	`"model"."idle_timeout"`,
+	`"model"."scaled_to_zero"`,
+	`"model"."deploy_config"`,
 	`"model"."create_time"`,
 	`"model"."update_time"`,
 }
@@ -285,6 +298,30 @@ func (r *repository) UpdateModelState(modelUID uuid.UUID, state datamodel.ModelS
 	return nil
 }
 
+func (r *repository) UpdateModelIdleTimeout(modelUID uuid.UUID, idleTimeout int) error {
+	if result := r.db.Model(&datamodel.Model{}).Where(map[string]interface{}{"uid": modelUID}).Updates(map[string]interface{}{"idle_timeout": idleTimeout}); result.Error != nil {
+		return status.Errorf(codes.Internal, "Error %v", result.Error)
+	}
+
+	return nil
+}
+
+func (r *repository) UpdateModelScaledToZero(modelUID uuid.UUID, scaledToZero bool) error {
+	if result := r.db.Model(&datamodel.Model{}).Where(map[string]interface{}{"uid": modelUID}).Updates(map[string]interface{}{"scaled_to_zero": scaledToZero}); result.Error != nil {
+		return status.Errorf(codes.Internal, "Error %v", result.Error)
+	}
+
+	return nil
+}
+
+func (r *repository) UpdateModelDeployConfig(modelUID uuid.UUID, deployConfig datatypes.JSON) error {
+	if result := r.db.Model(&datamodel.Model{}).Where(map[string]interface{}{"uid": modelUID}).Updates(map[string]interface{}{"deploy_config": deployConfig}); result.Error != nil {
+		return status.Errorf(codes.Internal, "Error %v", result.Error)
+	}
+
+	return nil
+}
+
 func (r *repository) CreateTritonModel(model datamodel.TritonModel) error {
 	if result := r.db.Model(&datamodel.TritonModel{}).Create(&model); result.Error != nil {
 		return status.Errorf(codes.Internal, "Error %v", result.Error)
@@ -391,3 +428,12 @@ func (r *repository) ListModelDefinitions(view modelPB.View, pageSize int, pageT
 
 	return definitions, nextPageToken, totalSize, nil
 }
+
+// Ping verifies the database connection is still alive
+func (r *repository) Ping(ctx context.Context) error {
+	sqlDB, err := r.db.DB()
+	if err != nil {
+		return err
+	}
+	return sqlDB.PingContext(ctx)
+}
@@ -0,0 +1,191 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"cloud.google.com/go/longrunning/autogen/longrunningpb"
+	"github.com/gofrs/uuid"
+	"go.temporal.io/sdk/client"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/instill-ai/model-backend/pkg/datamodel"
+	"github.com/instill-ai/model-backend/pkg/logger"
+	"github.com/instill-ai/model-backend/pkg/worker"
+
+	modelPB "github.com/instill-ai/protogen-go/model/model/v1alpha"
+)
+
+// MaxBulkConcurrency is the most models a bulk operation works on at once
+const MaxBulkConcurrency = 20
+
+// BulkModelSelector selects the models of an owner a bulk operation works on, by ID, by state (online, offline or
+// error), by visibility (public or private) and by model definition ID, or by the intersection of them
+type BulkModelSelector struct {
+	ModelIDs        []string
+	State           string
+	Visibility      string
+	ModelDefinition string
+}
+
+// bulkActions are the actions a bulk operation runs on models
+var bulkActions = map[string]bool{
+	"deploy":    true,
+	"undeploy":  true,
+	"delete":    true,
+	"publish":   true,
+	"unpublish": true,
+}
+
+// selectBulkModels returns the models of an owner selected for a bulk operation, in the order of their IDs when
+// selected by ID
+func (s *service) selectBulkModels(ctx context.Context, owner string, selector BulkModelSelector) ([]datamodel.Model, error) {
+	var state *modelPB.Model_State
+	if selector.State != "" {
+		v, ok := modelPB.Model_State_value["STATE_"+strings.ToUpper(selector.State)]
+		if !ok || v == int32(modelPB.Model_STATE_UNSPECIFIED) {
+			return nil, status.Errorf(codes.InvalidArgument, "unknown model state %q", selector.State)
+		}
+		state = modelPB.Model_State(v).Enum()
+	}
+	var visibility *datamodel.ModelVisibility
+	if selector.Visibility != "" {
+		v, ok := modelPB.Model_Visibility_value["VISIBILITY_"+strings.ToUpper(selector.Visibility)]
+		if !ok || v == int32(modelPB.Model_VISIBILITY_UNSPECIFIED) {
+			return nil, status.Errorf(codes.InvalidArgument, "unknown model visibility %q", selector.Visibility)
+		}
+		mv := datamodel.ModelVisibility(v)
+		visibility = &mv
+	}
+	var modelDefinitionUID *uuid.UUID
+	if selector.ModelDefinition != "" {
+		modelDef, err := s.repository.GetModelDefinition(strings.TrimPrefix(selector.ModelDefinition, "model-definitions/"))
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "unknown model definition %q", selector.ModelDefinition)
+		}
+		modelDefinitionUID = &modelDef.UID
+	}
+	if len(selector.ModelIDs) == 0 && state == nil && visibility == nil && modelDefinitionUID == nil {
+		return nil, status.Errorf(codes.InvalidArgument, "select the models by name or by a filter")
+	}
+
+	candidates := []datamodel.Model{}
+	if len(selector.ModelIDs) > 0 {
+		seen := map[string]bool{}
+		for _, modelID := range selector.ModelIDs {
+			if seen[modelID] {
+				continue
+			}
+			seen[modelID] = true
+			dbModel, err := s.repository.GetModelByID(owner, modelID, modelPB.View_VIEW_BASIC)
+			if err != nil {
+				return nil, status.Errorf(codes.NotFound, "model %s not found", modelID)
+			}
+			candidates = append(candidates, dbModel)
+		}
+	} else {
+		pageToken := ""
+		for {
+			dbModels, nextPageToken, _, err := s.repository.ListModels(owner, modelPB.View_VIEW_BASIC, 100, pageToken)
+			if err != nil {
+				return nil, err
+			}
+			candidates = append(candidates, dbModels...)
+			if nextPageToken == "" {
+				break
+			}
+			pageToken = nextPageToken
+		}
+	}
+
+	models := []datamodel.Model{}
+	for _, dbModel := range candidates {
+		if visibility != nil && dbModel.Visibility != *visibility {
+			continue
+		}
+		if modelDefinitionUID != nil && dbModel.ModelDefinitionUid != *modelDefinitionUID {
+			continue
+		}
+		if state != nil {
+			// The controller holds the latest state, fall back to the database when it is unknown to the controller
+			modelState := modelPB.Model_State(dbModel.State)
+			if resourceState, err := s.GetResourceState(ctx, dbModel.UID); err == nil {
+				modelState = *resourceState
+			}
+			if modelState != *state {
+				continue
+			}
+		}
+		models = append(models, dbModel)
+	}
+	return models, nil
+}
+
+// BulkModelOperation starts an operation deploying, undeploying, deleting, publishing or unpublishing the selected
+// models of an owner, at most concurrency of them at once. Its response reports the outcome on every model. The
+// schedules of the models to delete are deleted first.
+func (s *service) BulkModelOperation(ctx context.Context, owner string, action string, selector BulkModelSelector, concurrency int) (*longrunningpb.Operation, error) {
+	logger, _ := logger.GetZapLogger(ctx)
+
+	if !bulkActions[action] {
+		return nil, status.Errorf(codes.InvalidArgument, "unknown bulk action %q, it must be deploy, undeploy, delete, publish or unpublish", action)
+	}
+	if concurrency == 0 {
+		concurrency = worker.DefaultBulkConcurrency
+	}
+	if concurrency < 1 || concurrency > MaxBulkConcurrency {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid concurrency %d, it must be between 1 and %d", concurrency, MaxBulkConcurrency)
+	}
+
+	models, err := s.selectBulkModels(ctx, owner, selector)
+	if err != nil {
+		return nil, err
+	}
+
+	bulkModels := make([]worker.BulkModel, 0, len(models))
+	for i := range models {
+		if action == "delete" {
+			if err := s.deleteModelSchedules(ctx, owner, models[i].UID); err != nil {
+				return nil, err
+			}
+		}
+		bulkModels = append(bulkModels, worker.BulkModel{
+			UID: models[i].UID,
+			ID:  models[i].ID,
+		})
+	}
+
+	id, _ := uuid.NewV4()
+	workflowOptions := client.StartWorkflowOptions{
+		ID:        fmt.Sprintf("bulk-%s", id.String()),
+		TaskQueue: worker.TaskQueue,
+		Memo: map[string]interface{}{
+			worker.OperationMemoOwner: owner,
+		},
+		SearchAttributes: map[string]interface{}{
+			worker.SearchAttributeOwner: owner,
+		},
+	}
+
+	we, err := s.temporalClient.ExecuteWorkflow(
+		ctx,
+		workflowOptions,
+		"BulkModelWorkflow",
+		&worker.BulkParams{
+			Action:      action,
+			Owner:       owner,
+			Models:      bulkModels,
+			Concurrency: concurrency,
+		})
+	if err != nil {
+		logger.Error(fmt.Sprintf("unable to execute workflow: %s", err.Error()))
+		return nil, err
+	}
+
+	logger.Info(fmt.Sprintf("started workflow with WorkflowID %s and RunID %s", we.GetID(), we.GetRunID()))
+
+	operation, _, err := s.GetOperation(ctx, owner, worker.OperationID(we.GetID(), we.GetRunID()))
+	return operation, err
+}
@@ -0,0 +1,59 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/instill-ai/model-backend/config"
+	"github.com/instill-ai/model-backend/pkg/logger"
+	"github.com/instill-ai/model-backend/pkg/util"
+)
+
+// ModelCacheReport lists the entries of the model artifact cache, the least recently used first, and the entries
+// evicted by an eviction request
+type ModelCacheReport struct {
+	Entries   []util.ModelCacheEntry `json:"entries"`
+	Evicted   []util.ModelCacheEntry `json:"evicted,omitempty"`
+	TotalSize int64                  `json:"total_size"`
+	SizeLimit int64                  `json:"size_limit"`
+}
+
+// ListModelCache lists the entries of the model artifact cache
+func (s *service) ListModelCache(ctx context.Context) (*ModelCacheReport, error) {
+	entries, totalSize, err := util.ListModelCache()
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, err.Error())
+	}
+	return &ModelCacheReport{
+		Entries:   entries,
+		TotalSize: totalSize,
+		SizeLimit: config.Config.Cache.ModelSizeLimit,
+	}, nil
+}
+
+// EvictModelCache evicts an entry of the model artifact cache, or every entry not in use when key is empty
+func (s *service) EvictModelCache(ctx context.Context, key string) (*ModelCacheReport, error) {
+	logger, _ := logger.GetZapLogger(ctx)
+
+	evicted, err := util.EvictModelCache(key)
+	switch {
+	case errors.Is(err, util.ErrModelCacheEntryNotFound):
+		return nil, status.Errorf(codes.NotFound, err.Error())
+	case errors.Is(err, util.ErrModelCacheEntryInUse):
+		return nil, status.Errorf(codes.FailedPrecondition, err.Error())
+	case err != nil:
+		return nil, status.Errorf(codes.Internal, err.Error())
+	}
+	logger.Info(fmt.Sprintf("evicted %d model cache entry(ies)", len(evicted)))
+
+	report, err := s.ListModelCache(ctx)
+	if err != nil {
+		return nil, err
+	}
+	report.Evicted = evicted
+	return report, nil
+}
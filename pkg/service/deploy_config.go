@@ -0,0 +1,46 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/instill-ai/model-backend/config"
+	"github.com/instill-ai/model-backend/pkg/datamodel"
+	"github.com/instill-ai/model-backend/pkg/util"
+
+	modelPB "github.com/instill-ai/protogen-go/model/model/v1alpha"
+)
+
+// SetModelDeployConfig validates a deploy configuration against the Triton models of the model and persists it,
+// it is applied by every following deployment, cold start and reload of the model
+func (s *service) SetModelDeployConfig(ctx context.Context, owner string, modelID string, deployConfig datamodel.ModelDeployConfiguration) (datamodel.Model, error) {
+	modelInDB, err := s.GetModelByID(ctx, owner, modelID, modelPB.View_VIEW_BASIC)
+	if err != nil {
+		return datamodel.Model{}, err
+	}
+
+	if owner != modelInDB.Owner {
+		return datamodel.Model{}, status.Errorf(codes.Unauthenticated, "Unauthorized")
+	}
+
+	tritonModels, err := s.repository.GetTritonModels(modelInDB.UID)
+	if err != nil {
+		return datamodel.Model{}, err
+	}
+	if err := util.ValidateModelDeployConfig(config.Config.TritonServer.ModelStore, tritonModels, modelInDB.Task, deployConfig); err != nil {
+		return datamodel.Model{}, status.Errorf(codes.InvalidArgument, "invalid deploy configuration: %s", err.Error())
+	}
+
+	b, err := json.Marshal(deployConfig)
+	if err != nil {
+		return datamodel.Model{}, status.Errorf(codes.Internal, err.Error())
+	}
+	if err := s.repository.UpdateModelDeployConfig(modelInDB.UID, b); err != nil {
+		return datamodel.Model{}, err
+	}
+
+	return s.GetModelByID(ctx, owner, modelID, modelPB.View_VIEW_BASIC)
+}
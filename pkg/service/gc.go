@@ -0,0 +1,110 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/instill-ai/model-backend/config"
+	"github.com/instill-ai/model-backend/pkg/logger"
+	"github.com/instill-ai/model-backend/pkg/repository"
+	"github.com/instill-ai/model-backend/pkg/util"
+	"github.com/instill-ai/model-backend/pkg/worker"
+
+	modelPB "github.com/instill-ai/protogen-go/model/model/v1alpha"
+)
+
+// GarbageReport lists the orphaned files found in one garbage collection pass, nothing is removed in dry-run mode
+type GarbageReport struct {
+	DryRun        bool           `json:"dry_run"`
+	Items         []util.Garbage `json:"items"`
+	TotalSize     int64          `json:"total_size"`
+	ReclaimedSize int64          `json:"reclaimed_size"`
+}
+
+// CollectGarbage finds the model store directories, README files and staged temporary files
+// that do not belong to any model and removes them unless dryRun is set
+func (s *service) CollectGarbage(ctx context.Context, dryRun bool) (*GarbageReport, error) {
+	logger, _ := logger.GetZapLogger(ctx)
+
+	tritonModelNames := map[string]bool{}
+	models := map[string]bool{}
+	pageToken := ""
+	for {
+		dbModels, nextPageToken, _, err := s.repository.ListModelsAdmin(modelPB.View_VIEW_BASIC, repository.MaxPageSize, pageToken)
+		if err != nil {
+			return nil, err
+		}
+		for _, dbModel := range dbModels {
+			models[fmt.Sprintf("%s#%s", dbModel.Owner, dbModel.ID)] = true
+			tritonModels, err := s.repository.GetTritonModels(dbModel.UID)
+			if err != nil {
+				return nil, err
+			}
+			for _, tm := range tritonModels {
+				tritonModelNames[tm.Name] = true
+			}
+		}
+		if nextPageToken == "" {
+			break
+		}
+		pageToken = nextPageToken
+	}
+
+	modelStore := config.Config.TritonServer.ModelStore
+	gracePeriod := config.Config.GarbageCollector.GracePeriod
+
+	modelDirs, err := util.FindOrphanedModelDirs(modelStore, tritonModelNames, gracePeriod)
+	if err != nil {
+		return nil, err
+	}
+	readmes, err := util.FindOrphanedReadmes(modelStore, models, gracePeriod)
+	if err != nil {
+		return nil, err
+	}
+	staleTmpEntries, err := util.FindStaleTmpEntries(util.TMP_DIR, gracePeriod)
+	if err != nil {
+		return nil, err
+	}
+	// the staging folders of the deployments are left to the worker hosts, which know whether they are still in use
+	tmpEntries := []string{}
+	for _, name := range staleTmpEntries {
+		if !worker.IsStagingDir(name) {
+			tmpEntries = append(tmpEntries, name)
+		}
+	}
+
+	report := &GarbageReport{
+		DryRun: dryRun,
+		Items:  []util.Garbage{},
+	}
+	collect := func(root string, names []string, kind string) {
+		for _, name := range names {
+			item := util.Garbage{
+				Path: fmt.Sprintf("%s/%s", root, name),
+				Kind: kind,
+			}
+			size, err := util.DiskUsage(item.Path)
+			if err != nil {
+				item.Error = err.Error()
+			}
+			item.Size = size
+			report.TotalSize += item.Size
+			if !dryRun && item.Error == "" {
+				if err := util.RemoveUnder(root, name); err != nil {
+					item.Error = err.Error()
+					logger.Error(fmt.Sprintf("unable to remove %s: %s", item.Path, err))
+				} else {
+					report.ReclaimedSize += item.Size
+				}
+			}
+			report.Items = append(report.Items, item)
+		}
+	}
+	collect(modelStore, modelDirs, util.GarbageKindModelDir)
+	collect(modelStore, readmes, util.GarbageKindReadme)
+	collect(util.TMP_DIR, tmpEntries, util.GarbageKindTmp)
+
+	logger.Info(fmt.Sprintf("garbage collection found %d item(s) of %d byte(s), reclaimed %d byte(s)", len(report.Items), report.TotalSize, report.ReclaimedSize))
+
+	return report, nil
+}
@@ -0,0 +1,167 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"go.temporal.io/sdk/client"
+
+	mgmtPB "github.com/instill-ai/protogen-go/base/mgmt/v1alpha"
+	usagePB "github.com/instill-ai/protogen-go/base/usage/v1alpha"
+	healthcheckPB "github.com/instill-ai/protogen-go/common/healthcheck/v1alpha"
+	controllerPB "github.com/instill-ai/protogen-go/model/controller/v1alpha"
+)
+
+// dependencyCheckTimeout bounds the time spent probing a single dependency
+const dependencyCheckTimeout = 3 * time.Second
+
+// DependencyStatus is the readiness result of a single backing dependency
+type DependencyStatus struct {
+	Name      string `json:"name"`
+	Required  bool   `json:"required"`
+	Healthy   bool   `json:"healthy"`
+	LatencyMs int64  `json:"latency_ms"`
+	Error     string `json:"error,omitempty"`
+}
+
+// ReadinessReport aggregates the readiness of all backing dependencies.
+// Ready is false as soon as one required dependency is unhealthy, whereas
+// an unhealthy optional dependency only flips Degraded.
+type ReadinessReport struct {
+	Ready        bool               `json:"ready"`
+	Degraded     bool               `json:"degraded"`
+	Dependencies []DependencyStatus `json:"dependencies"`
+}
+
+type dependencyCheck struct {
+	name     string
+	required bool
+	check    func(ctx context.Context) error
+}
+
+// errNotConfigured reports a dependency whose client the server was started without
+var errNotConfigured = fmt.Errorf("client is not configured")
+
+func (s *service) dependencyChecks() []dependencyCheck {
+	checks := []dependencyCheck{
+		{name: "postgres", required: true, check: func(ctx context.Context) error {
+			if s.repository == nil {
+				return errNotConfigured
+			}
+			return s.repository.Ping(ctx)
+		}},
+		{name: "redis", required: true, check: func(ctx context.Context) error {
+			if s.redisClient == nil {
+				return errNotConfigured
+			}
+			return s.redisClient.Ping(ctx).Err()
+		}},
+		{name: "triton", required: true, check: func(ctx context.Context) error {
+			if s.triton == nil {
+				return errNotConfigured
+			}
+			if !s.triton.IsTritonServerReady(ctx) {
+				return fmt.Errorf("triton server is not ready")
+			}
+			return nil
+		}},
+		{name: "temporal", required: true, check: func(ctx context.Context) error {
+			if s.temporalClient == nil {
+				return errNotConfigured
+			}
+			_, err := s.temporalClient.CheckHealth(ctx, &client.CheckHealthRequest{})
+			return err
+		}},
+		{name: "mgmt-backend", required: true, check: func(ctx context.Context) error {
+			if s.mgmtPrivateServiceClient == nil {
+				return errNotConfigured
+			}
+			pageSize := int64(1)
+			_, err := s.mgmtPrivateServiceClient.ListUsersAdmin(ctx, &mgmtPB.ListUsersAdminRequest{PageSize: &pageSize})
+			return err
+		}},
+		{name: "controller", required: true, check: func(ctx context.Context) error {
+			if s.controllerClient == nil {
+				return errNotConfigured
+			}
+			resp, err := s.controllerClient.Liveness(ctx, &controllerPB.LivenessRequest{})
+			if err != nil {
+				return err
+			}
+			if resp.GetHealthCheckResponse().GetStatus() != healthcheckPB.HealthCheckResponse_SERVING_STATUS_SERVING {
+				return fmt.Errorf("controller is %s", resp.GetHealthCheckResponse().GetStatus())
+			}
+			return nil
+		}},
+	}
+
+	// Usage reporting is best-effort, so a failure only degrades the report
+	if s.usageServiceClient != nil {
+		checks = append(checks, dependencyCheck{name: "usage", required: false, check: func(ctx context.Context) error {
+			resp, err := s.usageServiceClient.Readiness(ctx, &usagePB.ReadinessRequest{})
+			if err != nil {
+				return err
+			}
+			if resp.GetHealthCheckResponse().GetStatus() != healthcheckPB.HealthCheckResponse_SERVING_STATUS_SERVING {
+				return fmt.Errorf("usage server is %s", resp.GetHealthCheckResponse().GetStatus())
+			}
+			return nil
+		}})
+	}
+
+	return checks
+}
+
+// CheckReadiness probes every backing dependency concurrently and reports
+// the latency and error of each of them
+func (s *service) CheckReadiness(ctx context.Context) ReadinessReport {
+	checks := s.dependencyChecks()
+
+	report := ReadinessReport{
+		Ready:        true,
+		Dependencies: make([]DependencyStatus, len(checks)),
+	}
+
+	var wg sync.WaitGroup
+	for i, c := range checks {
+		wg.Add(1)
+		go func(i int, c dependencyCheck) {
+			defer wg.Done()
+			report.Dependencies[i] = runDependencyCheck(ctx, c)
+		}(i, c)
+	}
+	wg.Wait()
+
+	for _, dep := range report.Dependencies {
+		if dep.Healthy {
+			continue
+		}
+		if dep.Required {
+			report.Ready = false
+		} else {
+			report.Degraded = true
+		}
+	}
+
+	return report
+}
+
+func runDependencyCheck(ctx context.Context, c dependencyCheck) DependencyStatus {
+	dep := DependencyStatus{Name: c.name, Required: c.required}
+
+	ctx, cancel := context.WithTimeout(ctx, dependencyCheckTimeout)
+	defer cancel()
+
+	start := time.Now()
+	err := c.check(ctx)
+	dep.LatencyMs = time.Since(start).Milliseconds()
+	if err != nil {
+		dep.Error = err.Error()
+		return dep
+	}
+	dep.Healthy = true
+
+	return dep
+}
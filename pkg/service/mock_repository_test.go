@@ -5,12 +5,14 @@
 package service_test
 
 import (
+	context "context"
 	reflect "reflect"
 
 	uuid "github.com/gofrs/uuid"
 	gomock "github.com/golang/mock/gomock"
 	datamodel "github.com/instill-ai/model-backend/pkg/datamodel"
 	modelv1alpha "github.com/instill-ai/protogen-go/model/model/v1alpha"
+	datatypes "gorm.io/datatypes"
 )
 
 // MockRepository is a mock of Repository interface.
@@ -263,6 +265,20 @@ func (mr *MockRepositoryMockRecorder) ListModelsAdmin(arg0, arg1, arg2 interface
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListModelsAdmin", reflect.TypeOf((*MockRepository)(nil).ListModelsAdmin), arg0, arg1, arg2)
 }
 
+// Ping mocks base method.
+func (m *MockRepository) Ping(arg0 context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Ping", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Ping indicates an expected call of Ping.
+func (mr *MockRepositoryMockRecorder) Ping(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Ping", reflect.TypeOf((*MockRepository)(nil).Ping), arg0)
+}
+
 // UpdateModel mocks base method.
 func (m *MockRepository) UpdateModel(arg0 uuid.UUID, arg1 datamodel.Model) error {
 	m.ctrl.T.Helper()
@@ -277,6 +293,48 @@ func (mr *MockRepositoryMockRecorder) UpdateModel(arg0, arg1 interface{}) *gomoc
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateModel", reflect.TypeOf((*MockRepository)(nil).UpdateModel), arg0, arg1)
 }
 
+// UpdateModelDeployConfig mocks base method.
+func (m *MockRepository) UpdateModelDeployConfig(arg0 uuid.UUID, arg1 datatypes.JSON) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateModelDeployConfig", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateModelDeployConfig indicates an expected call of UpdateModelDeployConfig.
+func (mr *MockRepositoryMockRecorder) UpdateModelDeployConfig(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateModelDeployConfig", reflect.TypeOf((*MockRepository)(nil).UpdateModelDeployConfig), arg0, arg1)
+}
+
+// UpdateModelIdleTimeout mocks base method.
+func (m *MockRepository) UpdateModelIdleTimeout(arg0 uuid.UUID, arg1 int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateModelIdleTimeout", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateModelIdleTimeout indicates an expected call of UpdateModelIdleTimeout.
+func (mr *MockRepositoryMockRecorder) UpdateModelIdleTimeout(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateModelIdleTimeout", reflect.TypeOf((*MockRepository)(nil).UpdateModelIdleTimeout), arg0, arg1)
+}
+
+// UpdateModelScaledToZero mocks base method.
+func (m *MockRepository) UpdateModelScaledToZero(arg0 uuid.UUID, arg1 bool) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateModelScaledToZero", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateModelScaledToZero indicates an expected call of UpdateModelScaledToZero.
+func (mr *MockRepositoryMockRecorder) UpdateModelScaledToZero(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateModelScaledToZero", reflect.TypeOf((*MockRepository)(nil).UpdateModelScaledToZero), arg0, arg1)
+}
+
 // UpdateModelState mocks base method.
 func (m *MockRepository) UpdateModelState(arg0 uuid.UUID, arg1 datamodel.ModelState) error {
 	m.ctrl.T.Helper()
@@ -62,17 +62,17 @@ func (mr *MockTritonMockRecorder) Init() *gomock.Call {
 }
 
 // IsTritonServerReady mocks base method.
-func (m *MockTriton) IsTritonServerReady() bool {
+func (m *MockTriton) IsTritonServerReady(arg0 context.Context) bool {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "IsTritonServerReady")
+	ret := m.ctrl.Call(m, "IsTritonServerReady", arg0)
 	ret0, _ := ret[0].(bool)
 	return ret0
 }
 
 // IsTritonServerReady indicates an expected call of IsTritonServerReady.
-func (mr *MockTritonMockRecorder) IsTritonServerReady() *gomock.Call {
+func (mr *MockTritonMockRecorder) IsTritonServerReady(arg0 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsTritonServerReady", reflect.TypeOf((*MockTriton)(nil).IsTritonServerReady))
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsTritonServerReady", reflect.TypeOf((*MockTriton)(nil).IsTritonServerReady), arg0)
 }
 
 // ListModelsRequest mocks base method.
@@ -104,6 +104,21 @@ func (mr *MockTritonMockRecorder) LoadModelRequest(arg0 interface{}) *gomock.Cal
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoadModelRequest", reflect.TypeOf((*MockTriton)(nil).LoadModelRequest), arg0)
 }
 
+// LoadModelWithConfigRequest mocks base method.
+func (m *MockTriton) LoadModelWithConfigRequest(arg0 string, arg1 *inferenceserver.ModelConfig) (*inferenceserver.RepositoryModelLoadResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LoadModelWithConfigRequest", arg0, arg1)
+	ret0, _ := ret[0].(*inferenceserver.RepositoryModelLoadResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LoadModelWithConfigRequest indicates an expected call of LoadModelWithConfigRequest.
+func (mr *MockTritonMockRecorder) LoadModelWithConfigRequest(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoadModelWithConfigRequest", reflect.TypeOf((*MockTriton)(nil).LoadModelWithConfigRequest), arg0, arg1)
+}
+
 // ModelConfigRequest mocks base method.
 func (m *MockTriton) ModelConfigRequest(arg0, arg1 string) *inferenceserver.ModelConfigResponse {
 	m.ctrl.T.Helper()
@@ -190,6 +205,20 @@ func (mr *MockTritonMockRecorder) ServerLiveRequest() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ServerLiveRequest", reflect.TypeOf((*MockTriton)(nil).ServerLiveRequest))
 }
 
+// ServerMetadataRequest mocks base method.
+func (m *MockTriton) ServerMetadataRequest() *inferenceserver.ServerMetadataResponse {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ServerMetadataRequest")
+	ret0, _ := ret[0].(*inferenceserver.ServerMetadataResponse)
+	return ret0
+}
+
+// ServerMetadataRequest indicates an expected call of ServerMetadataRequest.
+func (mr *MockTritonMockRecorder) ServerMetadataRequest() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ServerMetadataRequest", reflect.TypeOf((*MockTriton)(nil).ServerMetadataRequest))
+}
+
 // ServerReadyRequest mocks base method.
 func (m *MockTriton) ServerReadyRequest() *inferenceserver.ServerReadyResponse {
 	m.ctrl.T.Helper()
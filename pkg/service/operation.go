@@ -0,0 +1,210 @@
+package service
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"cloud.google.com/go/longrunning/autogen/longrunningpb"
+	"go.temporal.io/api/enums/v1"
+	"go.temporal.io/api/serviceerror"
+	"go.temporal.io/api/workflowservice/v1"
+	"go.temporal.io/sdk/client"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	workflowpb "go.temporal.io/api/workflow/v1"
+
+	"github.com/instill-ai/model-backend/pkg/datamodel"
+	"github.com/instill-ai/model-backend/pkg/logger"
+	"github.com/instill-ai/model-backend/pkg/worker"
+
+	modelPB "github.com/instill-ai/protogen-go/model/model/v1alpha"
+)
+
+// OperationFilter selects the model operations listed, an empty field selects every operation
+type OperationFilter struct {
+	Owner   string
+	ModelID string
+	Kind    string
+	State   string
+}
+
+// ModelOperation is a model operation with the model it is about, nil when the model is gone
+type ModelOperation struct {
+	Operation *longrunningpb.Operation
+	Model     *datamodel.Model
+}
+
+// newOperationNotFoundError returns the error of an operation that does not exist or that is not of the caller
+func newOperationNotFoundError(operationID string) error {
+	return status.Errorf(codes.NotFound, "operation %s not found", operationID)
+}
+
+// getOperationQuery returns the visibility query of the workflows of the model operations of a kind and a state, the
+// state being the status of the workflow such as running or failed
+func getOperationQuery(kind string, state string) (string, error) {
+	workflowTypes := []string{}
+	for workflowType, k := range worker.OperationKinds {
+		if kind == "" || kind == k {
+			workflowTypes = append(workflowTypes, fmt.Sprintf("WorkflowType = '%s'", workflowType))
+		}
+	}
+	if len(workflowTypes) == 0 {
+		return "", status.Errorf(codes.InvalidArgument, "unknown operation kind %q", kind)
+	}
+	sort.Strings(workflowTypes)
+	query := fmt.Sprintf("(%s)", strings.Join(workflowTypes, " OR "))
+
+	if state != "" {
+		found := false
+		for _, name := range enums.WorkflowExecutionStatus_name {
+			if name != "Unspecified" && strings.EqualFold(strings.ReplaceAll(state, "_", ""), name) {
+				query += fmt.Sprintf(" AND ExecutionStatus = '%s'", name)
+				found = true
+			}
+		}
+		if !found {
+			return "", status.Errorf(codes.InvalidArgument, "unknown operation state %q", state)
+		}
+	}
+	return query, nil
+}
+
+// quoteQueryValue quotes a value of a visibility query
+func quoteQueryValue(value string) string {
+	return "'" + strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(value) + "'"
+}
+
+// ListOperations lists a page of the model operations, newest first. The owner and the model are filtered on by the
+// search attributes of the operations, the operations started before they were set are only listed unfiltered.
+func (s *service) ListOperations(ctx context.Context, filter OperationFilter, pageSize int, pageToken string) ([]ModelOperation, string, error) {
+	query, err := getOperationQuery(filter.Kind, filter.State)
+	if err != nil {
+		return nil, "", err
+	}
+	token, err := base64.URLEncoding.DecodeString(pageToken)
+	if err != nil {
+		return nil, "", status.Errorf(codes.InvalidArgument, "invalid page token %q", pageToken)
+	}
+
+	if filter.ModelID != "" {
+		if filter.Owner == "" {
+			return nil, "", status.Errorf(codes.InvalidArgument, "the model filter requires an owner")
+		}
+		dbModel, err := s.repository.GetModelByID(filter.Owner, filter.ModelID, modelPB.View_VIEW_BASIC)
+		if err != nil {
+			return nil, "", status.Errorf(codes.NotFound, "model %s not found", filter.ModelID)
+		}
+		// a model being created has no UID in the search attributes of its operation
+		query += fmt.Sprintf(" AND (%s = %s OR (%s = %s AND WorkflowType = 'CreateModelWorkflow'))",
+			worker.SearchAttributeModelUID, quoteQueryValue(dbModel.UID.String()),
+			worker.SearchAttributeModelID, quoteQueryValue(filter.ModelID))
+	}
+	if filter.Owner != "" {
+		query += fmt.Sprintf(" AND %s = %s", worker.SearchAttributeOwner, quoteQueryValue(filter.Owner))
+	}
+
+	resp, err := s.temporalClient.ListWorkflow(ctx, &workflowservice.ListWorkflowExecutionsRequest{
+		PageSize:      int32(pageSize),
+		NextPageToken: token,
+		Query:         query,
+	})
+	if err != nil {
+		return nil, "", err
+	}
+
+	operations := []ModelOperation{}
+	for _, info := range resp.Executions {
+		pendingActivities := []*workflowpb.PendingActivityInfo{}
+		if info.Status == enums.WORKFLOW_EXECUTION_STATUS_RUNNING {
+			if describeRes, err := s.temporalClient.DescribeWorkflowExecution(ctx, info.Execution.WorkflowId, info.Execution.RunId); err == nil {
+				pendingActivities = describeRes.PendingActivities
+			}
+		}
+		operation, dbModel, err := s.getOperation(ctx, info, pendingActivities)
+		if err != nil {
+			return nil, "", err
+		}
+		operations = append(operations, ModelOperation{Operation: operation, Model: dbModel})
+	}
+
+	return operations, base64.URLEncoding.EncodeToString(resp.NextPageToken), nil
+}
+
+// CancelOperation requests the cancellation of a running deploy, create or bulk operation of an owner, the workflow
+// cleans up what it fetched and leaves the model offline, a bulk operation cancels the operations on its models
+func (s *service) CancelOperation(ctx context.Context, owner string, operationID string) (*longrunningpb.Operation, *datamodel.Model, error) {
+	workflowID, runID := worker.ParseOperationID(operationID)
+	workflowExecutionRes, err := s.temporalClient.DescribeWorkflowExecution(ctx, workflowID, runID)
+	if err != nil {
+		var notFound *serviceerror.NotFound
+		if errors.As(err, &notFound) {
+			return nil, nil, newOperationNotFoundError(operationID)
+		}
+		return nil, nil, err
+	}
+	info := workflowExecutionRes.WorkflowExecutionInfo
+	if readOperationMemo(info.GetMemo())[worker.OperationMemoOwner] != owner {
+		return nil, nil, newOperationNotFoundError(operationID)
+	}
+	if kind := worker.OperationKinds[info.GetType().GetName()]; kind != "deploy" && kind != "create" && kind != "bulk" {
+		return nil, nil, status.Errorf(codes.FailedPrecondition, "%s operations cannot be canceled", kind)
+	}
+	if info.Status != enums.WORKFLOW_EXECUTION_STATUS_RUNNING {
+		return nil, nil, status.Errorf(codes.FailedPrecondition, "operation %s is done", operationID)
+	}
+
+	if err := s.temporalClient.CancelWorkflow(ctx, workflowID, info.Execution.RunId); err != nil {
+		return nil, nil, err
+	}
+
+	return s.GetOperation(ctx, owner, worker.OperationID(workflowID, info.Execution.RunId))
+}
+
+// newOperationConflictError returns the Aborted error of an operation started while another one is running on the
+// model
+func newOperationConflictError(info *workflowpb.WorkflowExecutionInfo) error {
+	return status.Errorf(codes.Aborted, "a %s operation is running on the model, wait for it to finish or cancel it", worker.OperationKinds[info.GetType().GetName()])
+}
+
+// claimOperation holds the operation workflow ID of a model with the workflow of a synchronous operation, such as a
+// delete, so that no other operation starts on the model until the returned release is called. The workflow times
+// out when it is never released.
+func (s *service) claimOperation(ctx context.Context, workflowType string, owner string, model *datamodel.Model) (func(), error) {
+	logger, _ := logger.GetZapLogger(ctx)
+
+	workflowOptions := client.StartWorkflowOptions{
+		ID:                                       worker.OperationWorkflowID(model.UID),
+		TaskQueue:                                worker.TaskQueue,
+		Memo:                                     worker.OperationMemo(owner, model),
+		SearchAttributes:                         worker.OperationSearchAttributes(owner, model),
+		WorkflowIDReusePolicy:                    enums.WORKFLOW_ID_REUSE_POLICY_ALLOW_DUPLICATE,
+		WorkflowExecutionErrorWhenAlreadyStarted: true,
+		WorkflowExecutionTimeout:                 worker.DeleteModelLease,
+	}
+	we, err := s.temporalClient.ExecuteWorkflow(ctx, workflowOptions, workflowType, &worker.ModelParams{
+		Model: *model,
+		Owner: owner,
+	})
+	if err != nil {
+		var alreadyStarted *serviceerror.WorkflowExecutionAlreadyStarted
+		if !errors.As(err, &alreadyStarted) {
+			return nil, err
+		}
+		workflowExecutionRes, err := s.temporalClient.DescribeWorkflowExecution(ctx, workflowOptions.ID, "")
+		if err != nil {
+			return nil, err
+		}
+		return nil, newOperationConflictError(workflowExecutionRes.GetWorkflowExecutionInfo())
+	}
+
+	return func() {
+		if err := s.temporalClient.SignalWorkflow(context.Background(), we.GetID(), we.GetRunID(), worker.DeleteModelReleaseSignal, nil); err != nil {
+			logger.Error(fmt.Sprintf("unable to release the operation workflow %s: %s", we.GetID(), err.Error()))
+		}
+	}, nil
+}
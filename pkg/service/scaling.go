@@ -0,0 +1,279 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/go-redis/redis/v9"
+	"github.com/gofrs/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/instill-ai/model-backend/config"
+	"github.com/instill-ai/model-backend/pkg/constant"
+	"github.com/instill-ai/model-backend/pkg/datamodel"
+	"github.com/instill-ai/model-backend/pkg/logger"
+	"github.com/instill-ai/model-backend/pkg/repository"
+	"github.com/instill-ai/model-backend/pkg/util"
+
+	modelPB "github.com/instill-ai/protogen-go/model/model/v1alpha"
+)
+
+// errModelScalingLocked is returned when another replica is scaling the model
+var errModelScalingLocked = errors.New("the model is being scaled by another replica")
+
+// releaseModelScalingLockScript deletes the lock only if it is still held with the token
+var releaseModelScalingLockScript = redis.NewScript(`if redis.call("get", KEYS[1]) == ARGV[1] then return redis.call("del", KEYS[1]) end return 0`)
+
+// lockModelScaling takes the Redis lock serialising the cold starts and the idle unloads of a model across the
+// replicas, waiting for it until ctx is done if wait is set. The lock expires on its own if its holder dies.
+func (s *service) lockModelScaling(ctx context.Context, modelUID uuid.UUID, wait bool) (func(), error) {
+	key := fmt.Sprintf(constant.ModelScalingLockKeyFormat, modelUID)
+	token, err := uuid.NewV4()
+	if err != nil {
+		return nil, err
+	}
+	ttl := config.Config.IdleUndeploy.ColdStartTimeout + time.Minute
+
+	ticker := time.NewTicker(200 * time.Millisecond)
+	defer ticker.Stop()
+	for {
+		ok, err := s.redisClient.SetNX(ctx, key, token.String(), ttl).Result()
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			return func() {
+				_ = releaseModelScalingLockScript.Run(context.Background(), s.redisClient, []string{key}, token.String()).Err()
+			}, nil
+		}
+		if !wait {
+			return nil, errModelScalingLocked
+		}
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// SetModelIdleTimeout sets the minutes without inference after which the deployed model is unloaded, 0 disables it
+func (s *service) SetModelIdleTimeout(ctx context.Context, owner string, modelID string, idleTimeout int) (datamodel.Model, error) {
+	if idleTimeout < 0 {
+		return datamodel.Model{}, status.Errorf(codes.InvalidArgument, "idle timeout must not be negative")
+	}
+
+	modelInDB, err := s.GetModelByID(ctx, owner, modelID, modelPB.View_VIEW_BASIC)
+	if err != nil {
+		return datamodel.Model{}, err
+	}
+
+	if owner != modelInDB.Owner {
+		return datamodel.Model{}, status.Errorf(codes.Unauthenticated, "Unauthorized")
+	}
+
+	if err := s.repository.UpdateModelIdleTimeout(modelInDB.UID, idleTimeout); err != nil {
+		return datamodel.Model{}, err
+	}
+
+	return s.GetModelByID(ctx, owner, modelID, modelPB.View_VIEW_BASIC)
+}
+
+// EnsureModelLoaded records an inference of the model and, if the model has been scaled to zero,
+// loads it back into Triton and waits for it to be ready. It reports whether a cold start happened.
+func (s *service) EnsureModelLoaded(ctx context.Context, dbModel *datamodel.Model) (bool, error) {
+	modelUID := dbModel.UID
+	s.lastTriggerTimes.Store(modelUID, time.Now().Unix())
+
+	if !dbModel.ScaledToZero {
+		return false, nil
+	}
+
+	lockCtx, cancel := context.WithTimeout(ctx, config.Config.IdleUndeploy.ColdStartTimeout)
+	defer cancel()
+	unlock, err := s.lockModelScaling(lockCtx, modelUID, true)
+	if err != nil {
+		return false, status.Errorf(codes.Unavailable, "cold start of model %s failed: %s", dbModel.ID, err.Error())
+	}
+	defer unlock()
+
+	// another request may have loaded the model while waiting for the lock
+	modelInDB, err := s.repository.GetModelByUIDAdmin(modelUID, modelPB.View_VIEW_BASIC)
+	if err != nil {
+		return false, err
+	}
+	if !modelInDB.ScaledToZero {
+		return false, nil
+	}
+
+	tritonModels, err := s.repository.GetTritonModels(modelUID)
+	if err != nil {
+		return false, err
+	}
+	if len(tritonModels) == 0 {
+		return false, status.Errorf(codes.NotFound, "triton model of model %s not found", modelInDB.ID)
+	}
+	tEnsembleModel, _ := s.repository.GetTritonEnsembleModel(modelUID)
+	for _, tModel := range tritonModels {
+		if tEnsembleModel.Name != "" && tEnsembleModel.Name == tModel.Name { // load ensemble model last.
+			continue
+		}
+		if err := s.loadTritonModel(modelInDB, tModel.Name); err != nil {
+			return false, status.Errorf(codes.Unavailable, "cold start of model %s failed: %s", modelInDB.ID, err.Error())
+		}
+	}
+	readyModel := tritonModels[len(tritonModels)-1]
+	if tEnsembleModel.Name != "" {
+		if err := s.loadTritonModel(modelInDB, tEnsembleModel.Name); err != nil {
+			return false, status.Errorf(codes.Unavailable, "cold start of model %s failed: %s", modelInDB.ID, err.Error())
+		}
+		readyModel = tEnsembleModel
+	}
+
+	if err := s.waitModelReady(ctx, readyModel); err != nil {
+		return false, status.Errorf(codes.Unavailable, "cold start of model %s failed: %s", modelInDB.ID, err.Error())
+	}
+
+	if err := s.repository.UpdateModelScaledToZero(modelUID, false); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// FlushModelTriggerTimes writes the times of the latest inferences recorded since the previous flush to Redis in
+// one round trip, so that an inference does not wait on Redis
+func (s *service) FlushModelTriggerTimes(ctx context.Context) error {
+	pipe := s.redisClient.Pipeline()
+	flushed := map[uuid.UUID]int64{}
+	s.lastTriggerTimes.Range(func(key, value any) bool {
+		if value, ok := s.lastTriggerTimes.LoadAndDelete(key); ok {
+			flushed[key.(uuid.UUID)] = value.(int64)
+			pipe.Set(ctx, fmt.Sprintf(constant.ModelLastTriggerTimeKeyFormat, key), value, 0)
+		}
+		return true
+	})
+	if len(flushed) == 0 {
+		return nil
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		// keep them for the next flush unless newer ones were recorded meanwhile
+		for modelUID, triggerTime := range flushed {
+			s.lastTriggerTimes.LoadOrStore(modelUID, triggerTime)
+		}
+		return err
+	}
+	return nil
+}
+
+// loadTritonModel loads a Triton model with the deploy configuration of its model applied
+func (s *service) loadTritonModel(dbModel datamodel.Model, tritonModelName string) error {
+	modelConfig, err := util.GetModelConfigOverride(config.Config.TritonServer.ModelStore, tritonModelName, dbModel.DeployConfig)
+	if err != nil {
+		return err
+	}
+	_, err = s.triton.LoadModelWithConfigRequest(tritonModelName, modelConfig)
+	return err
+}
+
+func (s *service) waitModelReady(ctx context.Context, tritonModel datamodel.TritonModel) error {
+	ctx, cancel := context.WithTimeout(ctx, config.Config.IdleUndeploy.ColdStartTimeout)
+	defer cancel()
+
+	ticker := time.NewTicker(500 * time.Millisecond)
+	defer ticker.Stop()
+	for {
+		if resp := s.triton.ModelReadyRequest(ctx, tritonModel.Name, fmt.Sprint(tritonModel.Version)); resp != nil && resp.Ready {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("triton model %s is not ready: %w", tritonModel.Name, ctx.Err())
+		case <-ticker.C:
+		}
+	}
+}
+
+// UnloadIdleModels unloads from Triton the online models that have not been triggered within their idle timeout
+// and marks them as scaled to zero, they are loaded back by the next inference
+func (s *service) UnloadIdleModels(ctx context.Context) error {
+	logger, _ := logger.GetZapLogger(ctx)
+
+	if err := s.FlushModelTriggerTimes(ctx); err != nil {
+		logger.Error(fmt.Sprintf("unable to flush the last trigger times: %s", err))
+	}
+
+	pageToken := ""
+	for {
+		dbModels, nextPageToken, _, err := s.repository.ListModelsAdmin(modelPB.View_VIEW_BASIC, repository.MaxPageSize, pageToken)
+		if err != nil {
+			return err
+		}
+		for _, dbModel := range dbModels {
+			if dbModel.IdleTimeout <= 0 || dbModel.ScaledToZero {
+				continue
+			}
+			state, err := s.GetResourceState(ctx, dbModel.UID)
+			if err != nil || *state != modelPB.Model_STATE_ONLINE {
+				continue
+			}
+
+			key := fmt.Sprintf(constant.ModelLastTriggerTimeKeyFormat, dbModel.UID)
+			lastTriggerTime, err := s.redisClient.Get(ctx, key).Int64()
+			if err == redis.Nil {
+				// never triggered since the idle timeout was set, start counting from now
+				s.redisClient.Set(ctx, key, time.Now().Unix(), 0)
+				continue
+			} else if err != nil {
+				logger.Error(fmt.Sprintf("unable to get the last trigger time of model %s: %s", dbModel.UID, err))
+				continue
+			}
+			if time.Since(time.Unix(lastTriggerTime, 0)) < time.Duration(dbModel.IdleTimeout)*time.Minute {
+				continue
+			}
+
+			if err := s.scaleModelToZero(ctx, dbModel.UID); err != nil {
+				if errors.Is(err, errModelScalingLocked) {
+					continue
+				}
+				logger.Error(fmt.Sprintf("unable to unload idle model %s: %s", dbModel.UID, err))
+				continue
+			}
+			logger.Info(fmt.Sprintf("model %s/%s scaled to zero after %d idle minute(s)", dbModel.Owner, dbModel.ID, dbModel.IdleTimeout))
+		}
+		if nextPageToken == "" {
+			break
+		}
+		pageToken = nextPageToken
+	}
+
+	return nil
+}
+
+func (s *service) scaleModelToZero(ctx context.Context, modelUID uuid.UUID) error {
+	unlock, err := s.lockModelScaling(ctx, modelUID, false)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	tritonModels, err := s.repository.GetTritonModels(modelUID)
+	if err != nil {
+		return err
+	}
+
+	// flag the model first so that a partially unloaded model is fully loaded back by the next inference
+	if err := s.repository.UpdateModelScaledToZero(modelUID, true); err != nil {
+		return err
+	}
+	for _, tm := range tritonModels {
+		if _, err := s.triton.UnloadModelRequest(tm.Name); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
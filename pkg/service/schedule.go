@@ -0,0 +1,363 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/gofrs/uuid"
+	"go.temporal.io/api/enums/v1"
+	"go.temporal.io/api/serviceerror"
+	"go.temporal.io/api/workflowservice/v1"
+	"go.temporal.io/sdk/client"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	workflowpb "go.temporal.io/api/workflow/v1"
+
+	"github.com/instill-ai/model-backend/pkg/worker"
+
+	modelPB "github.com/instill-ai/protogen-go/model/model/v1alpha"
+)
+
+// ModelSchedule is a schedule deploying or undeploying a model at the times of a cron expression in a time zone, with
+// the outcome of its recent runs
+type ModelSchedule struct {
+	Name         string        `json:"name"`
+	Model        string        `json:"model"`
+	Kind         string        `json:"kind"`
+	Cron         string        `json:"cron"`
+	TimeZone     string        `json:"time_zone"`
+	Paused       bool          `json:"paused"`
+	NextRunTimes []time.Time   `json:"next_run_times"`
+	RecentRuns   []ScheduleRun `json:"recent_runs"`
+}
+
+// ScheduleRun is the outcome of a run of a model schedule, the operation it ran or the reason it was skipped, and
+// the error of a failed run
+type ScheduleRun struct {
+	ScheduleTime time.Time `json:"schedule_time"`
+	State        string    `json:"state"`
+	Operation    string    `json:"operation,omitempty"`
+	Skipped      string    `json:"skipped,omitempty"`
+	Error        string    `json:"error,omitempty"`
+}
+
+// The memo of a model schedule tells the model and the operation it runs besides the keys of the operation memo
+const (
+	scheduleMemoKind     = "kind"
+	scheduleMemoCron     = "cron"
+	scheduleMemoTimeZone = "time_zone"
+)
+
+// schedulerWorkflowIDPrefix is the prefix of the ID of the workflow Temporal runs a schedule with, the schedules of a
+// model are searched by the search attributes of these workflows since schedules cannot be listed by a query
+const schedulerWorkflowIDPrefix = "temporal-sys-scheduler:"
+
+// getModelScheduleIDPrefix returns the prefix of the IDs of the schedules of a model
+func getModelScheduleIDPrefix(modelUID uuid.UUID) string {
+	return fmt.Sprintf("model-%s-", modelUID.String())
+}
+
+// getOperationState returns the snake case state of a workflow, such as running or timed_out
+func getOperationState(workflowStatus enums.WorkflowExecutionStatus) string {
+	var b strings.Builder
+	for i, r := range workflowStatus.String() {
+		if unicode.IsUpper(r) && i > 0 {
+			b.WriteRune('_')
+		}
+		b.WriteRune(unicode.ToLower(r))
+	}
+	return b.String()
+}
+
+// CreateModelSchedule creates a Temporal schedule deploying or undeploying a model of an owner at the times of a cron
+// expression in a time zone, UTC when empty. A run is skipped while the previous one is running.
+func (s *service) CreateModelSchedule(ctx context.Context, owner string, modelID string, kind string, cron string, timeZone string) (*ModelSchedule, error) {
+	if kind != "deploy" && kind != "undeploy" {
+		return nil, status.Errorf(codes.InvalidArgument, "unknown schedule kind %q, it must be deploy or undeploy", kind)
+	}
+	if strings.TrimSpace(cron) == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "the cron expression is required")
+	}
+	if timeZone == "" {
+		timeZone = "UTC"
+	}
+	if _, err := time.LoadLocation(timeZone); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "unknown time zone %q", timeZone)
+	}
+
+	dbModel, err := s.repository.GetModelByID(owner, modelID, modelPB.View_VIEW_BASIC)
+	if err != nil {
+		return nil, status.Errorf(codes.NotFound, "model %s not found", modelID)
+	}
+
+	id, _ := uuid.NewV4()
+	scheduleID := getModelScheduleIDPrefix(dbModel.UID) + id.String()
+	memo := worker.OperationMemo(owner, &dbModel)
+	scheduleMemo := map[string]interface{}{
+		scheduleMemoKind:     kind,
+		scheduleMemoCron:     cron,
+		scheduleMemoTimeZone: timeZone,
+	}
+	for key, value := range memo {
+		scheduleMemo[key] = value
+	}
+
+	handle, err := s.temporalClient.ScheduleClient().Create(ctx, client.ScheduleOptions{
+		ID: scheduleID,
+		Spec: client.ScheduleSpec{
+			CronExpressions: []string{cron},
+			TimeZoneName:    timeZone,
+		},
+		Action: &client.ScheduleWorkflowAction{
+			ID:        fmt.Sprintf("schedule-%s", scheduleID),
+			Workflow:  "ModelOperationWorkflow",
+			TaskQueue: worker.TaskQueue,
+			Args: []interface{}{&worker.OperationParams{
+				Model:            dbModel,
+				Owner:            owner,
+				Kind:             kind,
+				Memo:             memo,
+				SearchAttributes: worker.OperationSearchAttributes(owner, &dbModel),
+			}},
+		},
+		Overlap:          enums.SCHEDULE_OVERLAP_POLICY_SKIP,
+		Memo:             scheduleMemo,
+		SearchAttributes: worker.OperationSearchAttributes(owner, &dbModel),
+	})
+	if err != nil {
+		var invalidArgument *serviceerror.InvalidArgument
+		if errors.As(err, &invalidArgument) {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid schedule: %s", invalidArgument.Message)
+		}
+		return nil, err
+	}
+
+	description, err := handle.Describe(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return s.getModelSchedule(ctx, handle.GetID(), readOperationMemo(description.Memo), isSchedulePaused(description), description.Info.NextActionTimes, description.Info.RecentActions), nil
+}
+
+// listModelScheduleIDs lists the IDs of the schedules of the models of an owner, or of one of its models, by a
+// visibility query on the search attributes of the schedules
+func (s *service) listModelScheduleIDs(ctx context.Context, owner string, modelUID uuid.UUID) ([]string, error) {
+	query := fmt.Sprintf("TemporalNamespaceDivision = 'TemporalScheduler' AND ExecutionStatus = 'Running' AND %s = %s",
+		worker.SearchAttributeOwner, quoteQueryValue(owner))
+	prefix := "model-"
+	if modelUID != uuid.Nil {
+		query += fmt.Sprintf(" AND %s = %s", worker.SearchAttributeModelUID, quoteQueryValue(modelUID.String()))
+		prefix = getModelScheduleIDPrefix(modelUID)
+	}
+
+	scheduleIDs := []string{}
+	var token []byte
+	for {
+		resp, err := s.temporalClient.ListWorkflow(ctx, &workflowservice.ListWorkflowExecutionsRequest{
+			PageSize:      100,
+			NextPageToken: token,
+			Query:         query,
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, info := range resp.Executions {
+			scheduleID := strings.TrimPrefix(info.GetExecution().GetWorkflowId(), schedulerWorkflowIDPrefix)
+			if strings.HasPrefix(scheduleID, prefix) {
+				scheduleIDs = append(scheduleIDs, scheduleID)
+			}
+		}
+		if len(resp.NextPageToken) == 0 {
+			return scheduleIDs, nil
+		}
+		token = resp.NextPageToken
+	}
+}
+
+// ListModelSchedules lists the schedules of the models of an owner, or of one of its models
+func (s *service) ListModelSchedules(ctx context.Context, owner string, modelID string) ([]ModelSchedule, error) {
+	modelUID := uuid.Nil
+	if modelID != "" {
+		dbModel, err := s.repository.GetModelByID(owner, modelID, modelPB.View_VIEW_BASIC)
+		if err != nil {
+			return nil, status.Errorf(codes.NotFound, "model %s not found", modelID)
+		}
+		modelUID = dbModel.UID
+	}
+
+	scheduleIDs, err := s.listModelScheduleIDs(ctx, owner, modelUID)
+	if err != nil {
+		return nil, err
+	}
+	schedules := []ModelSchedule{}
+	for _, scheduleID := range scheduleIDs {
+		_, description, err := s.getOwnedSchedule(ctx, owner, scheduleID)
+		if status.Code(err) == codes.NotFound { // deleted since it was listed
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		schedules = append(schedules, *s.getModelSchedule(ctx, scheduleID, readOperationMemo(description.Memo), isSchedulePaused(description), description.Info.NextActionTimes, description.Info.RecentActions))
+	}
+	return schedules, nil
+}
+
+// PauseModelSchedule pauses or resumes a schedule of an owner
+func (s *service) PauseModelSchedule(ctx context.Context, owner string, scheduleID string, paused bool) (*ModelSchedule, error) {
+	handle, _, err := s.getOwnedSchedule(ctx, owner, scheduleID)
+	if err != nil {
+		return nil, err
+	}
+	if paused {
+		err = handle.Pause(ctx, client.SchedulePauseOptions{Note: fmt.Sprintf("paused by %s", owner)})
+	} else {
+		err = handle.Unpause(ctx, client.ScheduleUnpauseOptions{Note: fmt.Sprintf("resumed by %s", owner)})
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	description, err := handle.Describe(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return s.getModelSchedule(ctx, scheduleID, readOperationMemo(description.Memo), isSchedulePaused(description), description.Info.NextActionTimes, description.Info.RecentActions), nil
+}
+
+// DeleteModelSchedule deletes a schedule of an owner, a running operation it started is left running
+func (s *service) DeleteModelSchedule(ctx context.Context, owner string, scheduleID string) error {
+	handle, _, err := s.getOwnedSchedule(ctx, owner, scheduleID)
+	if err != nil {
+		return err
+	}
+	return handle.Delete(ctx)
+}
+
+// deleteModelSchedules deletes the schedules of a model being deleted
+func (s *service) deleteModelSchedules(ctx context.Context, owner string, modelUID uuid.UUID) error {
+	scheduleIDs, err := s.listModelScheduleIDs(ctx, owner, modelUID)
+	if err != nil {
+		return err
+	}
+	for _, scheduleID := range scheduleIDs {
+		if err := s.temporalClient.ScheduleClient().GetHandle(ctx, scheduleID).Delete(ctx); err != nil {
+			var notFound *serviceerror.NotFound
+			if !errors.As(err, &notFound) {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func isSchedulePaused(description *client.ScheduleDescription) bool {
+	return description.Schedule.State != nil && description.Schedule.State.Paused
+}
+
+// getOwnedSchedule returns the handle and the description of a schedule of an owner, the schedules of other owners
+// are not found
+func (s *service) getOwnedSchedule(ctx context.Context, owner string, scheduleID string) (client.ScheduleHandle, *client.ScheduleDescription, error) {
+	handle := s.temporalClient.ScheduleClient().GetHandle(ctx, scheduleID)
+	description, err := handle.Describe(ctx)
+	if err != nil {
+		var notFound *serviceerror.NotFound
+		if errors.As(err, &notFound) {
+			return nil, nil, status.Errorf(codes.NotFound, "schedule %s not found", scheduleID)
+		}
+		return nil, nil, err
+	}
+	memo := readOperationMemo(description.Memo)
+	if memo[worker.OperationMemoOwner] != owner || memo[scheduleMemoKind] == "" {
+		return nil, nil, status.Errorf(codes.NotFound, "schedule %s not found", scheduleID)
+	}
+	return handle, description, nil
+}
+
+// getModelSchedule returns a model schedule with the outcome of its recent runs, read from their workflows
+func (s *service) getModelSchedule(ctx context.Context, scheduleID string, memo map[string]string, paused bool, nextActionTimes []time.Time, recentActions []client.ScheduleActionResult) *ModelSchedule {
+	schedule := &ModelSchedule{
+		Name:         fmt.Sprintf("schedules/%s", scheduleID),
+		Model:        fmt.Sprintf("models/%s", memo[worker.OperationMemoModelID]),
+		Kind:         memo[scheduleMemoKind],
+		Cron:         memo[scheduleMemoCron],
+		TimeZone:     memo[scheduleMemoTimeZone],
+		Paused:       paused,
+		NextRunTimes: nextActionTimes,
+		RecentRuns:   []ScheduleRun{},
+	}
+	if schedule.NextRunTimes == nil {
+		schedule.NextRunTimes = []time.Time{}
+	}
+	schedule.RecentRuns = s.getScheduleRuns(ctx, recentActions)
+	return schedule
+}
+
+// getScheduleRuns returns the outcome of the recent runs of a schedule. Their workflows are listed by one visibility
+// query, and the result of a completed run is read from its memo, only the error of a failed run is read from its
+// history. A run whose workflow is not listed is unknown.
+func (s *service) getScheduleRuns(ctx context.Context, recentActions []client.ScheduleActionResult) []ScheduleRun {
+	runs := []ScheduleRun{}
+	clauses := []string{}
+	for _, action := range recentActions {
+		if action.StartWorkflowResult != nil {
+			clauses = append(clauses, fmt.Sprintf("WorkflowId = %s", quoteQueryValue(action.StartWorkflowResult.WorkflowID)))
+		}
+	}
+	infos := map[string]*workflowpb.WorkflowExecutionInfo{}
+	if len(clauses) > 0 {
+		resp, err := s.temporalClient.ListWorkflow(ctx, &workflowservice.ListWorkflowExecutionsRequest{
+			PageSize: int32(len(clauses)),
+			Query:    strings.Join(clauses, " OR "),
+		})
+		if err == nil {
+			for _, info := range resp.Executions {
+				infos[info.GetExecution().GetRunId()] = info
+			}
+		}
+	}
+
+	for _, action := range recentActions {
+		run := ScheduleRun{ScheduleTime: action.ScheduleTime, State: "unknown"}
+		if action.StartWorkflowResult != nil {
+			if info, ok := infos[action.StartWorkflowResult.FirstExecutionRunID]; ok {
+				s.readScheduleRun(ctx, &run, info)
+			}
+		}
+		runs = append(runs, run)
+	}
+	return runs
+}
+
+// readScheduleRun reads the state and the outcome of a run of a schedule from its workflow
+func (s *service) readScheduleRun(ctx context.Context, run *ScheduleRun, info *workflowpb.WorkflowExecutionInfo) {
+	workflowID := info.GetExecution().GetWorkflowId()
+	runID := info.GetExecution().GetRunId()
+	workflowStatus := info.GetStatus()
+	run.State = getOperationState(workflowStatus)
+	switch workflowStatus {
+	case enums.WORKFLOW_EXECUTION_STATUS_RUNNING:
+	case enums.WORKFLOW_EXECUTION_STATUS_COMPLETED:
+		memo := readOperationMemo(info.GetMemo())
+		if _, ok := info.GetMemo().GetFields()[worker.OperationResultMemoOperation]; ok {
+			run.Operation = memo[worker.OperationResultMemoOperation]
+			run.Skipped = memo[worker.OperationResultMemoSkipped]
+			return
+		}
+		// the runs completed before their result was kept in their memo
+		var result worker.OperationResult
+		if err := s.temporalClient.GetWorkflow(ctx, workflowID, runID).Get(ctx, &result); err == nil {
+			run.Operation = result.Operation
+			run.Skipped = result.Skipped
+		}
+	case enums.WORKFLOW_EXECUTION_STATUS_FAILED:
+		run.Error = getOperationError(workflowStatus, s.temporalClient.GetWorkflow(ctx, workflowID, runID).Get(ctx, nil)).Message
+	default:
+		run.Error = getOperationError(workflowStatus, nil).Message
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"path/filepath"
 	"strconv"
 	"strings"
+	"sync"
 
 	"cloud.google.com/go/longrunning/autogen/longrunningpb"
 	"github.com/go-redis/redis/v9"
@@ -26,9 +27,11 @@ import (
 	"github.com/instill-ai/model-backend/pkg/repository"
 	"github.com/instill-ai/model-backend/pkg/triton"
 	"github.com/instill-ai/model-backend/pkg/util"
+	"github.com/instill-ai/model-backend/pkg/worker"
 	"github.com/instill-ai/x/sterr"
 
 	mgmtPB "github.com/instill-ai/protogen-go/base/mgmt/v1alpha"
+	usagePB "github.com/instill-ai/protogen-go/base/usage/v1alpha"
 	controllerPB "github.com/instill-ai/protogen-go/model/controller/v1alpha"
 	modelPB "github.com/instill-ai/protogen-go/model/model/v1alpha"
 )
@@ -67,7 +70,9 @@ type Service interface {
 	GetTritonEnsembleModel(ctx context.Context, modelUID uuid.UUID) (datamodel.TritonModel, error)
 	GetTritonModels(ctx context.Context, modelUID uuid.UUID) ([]datamodel.TritonModel, error)
 
-	GetOperation(ctx context.Context, workflowID string) (*longrunningpb.Operation, error)
+	GetOperation(ctx context.Context, owner string, operationID string) (*longrunningpb.Operation, *datamodel.Model, error)
+	ListOperations(ctx context.Context, filter OperationFilter, pageSize int, pageToken string) ([]ModelOperation, string, error)
+	CancelOperation(ctx context.Context, owner string, operationID string) (*longrunningpb.Operation, *datamodel.Model, error)
 
 	GetModelByIDAdmin(ctx context.Context, modelID string, view modelPB.View) (datamodel.Model, error)
 	GetModelByUIDAdmin(ctx context.Context, modelUID uuid.UUID, view modelPB.View) (datamodel.Model, error)
@@ -76,6 +81,26 @@ type Service interface {
 	GetResourceState(ctx context.Context, modelUID uuid.UUID) (*modelPB.Model_State, error)
 	UpdateResourceState(ctx context.Context, modelUID uuid.UUID, state modelPB.Model_State, progress *int32, workflowID *string) error
 	DeleteResourceState(ctx context.Context, modelUID uuid.UUID) error
+
+	CheckReadiness(ctx context.Context) ReadinessReport
+	ReconcileModels(ctx context.Context, dryRun bool) (*worker.ReconcileReport, error)
+	CollectGarbage(ctx context.Context, dryRun bool) (*GarbageReport, error)
+	ListModelCache(ctx context.Context) (*ModelCacheReport, error)
+	EvictModelCache(ctx context.Context, key string) (*ModelCacheReport, error)
+
+	SetModelIdleTimeout(ctx context.Context, owner string, modelID string, idleTimeout int) (datamodel.Model, error)
+	EnsureModelLoaded(ctx context.Context, dbModel *datamodel.Model) (bool, error)
+	FlushModelTriggerTimes(ctx context.Context) error
+	UnloadIdleModels(ctx context.Context) error
+
+	SetModelDeployConfig(ctx context.Context, owner string, modelID string, deployConfig datamodel.ModelDeployConfiguration) (datamodel.Model, error)
+
+	CreateModelSchedule(ctx context.Context, owner string, modelID string, kind string, cron string, timeZone string) (*ModelSchedule, error)
+	ListModelSchedules(ctx context.Context, owner string, modelID string) ([]ModelSchedule, error)
+	PauseModelSchedule(ctx context.Context, owner string, scheduleID string, paused bool) (*ModelSchedule, error)
+	DeleteModelSchedule(ctx context.Context, owner string, scheduleID string) error
+
+	BulkModelOperation(ctx context.Context, owner string, action string, selector BulkModelSelector, concurrency int) (*longrunningpb.Operation, error)
 }
 
 type service struct {
@@ -85,10 +110,12 @@ type service struct {
 	mgmtPrivateServiceClient    mgmtPB.MgmtPrivateServiceClient
 	temporalClient              client.Client
 	controllerClient            controllerPB.ControllerPrivateServiceClient
+	usageServiceClient          usagePB.UsageServiceClient
+	lastTriggerTimes            sync.Map
 }
 
-// NewService returns a new service instance
-func NewService(r repository.Repository, t triton.Triton, m mgmtPB.MgmtPrivateServiceClient, rc *redis.Client, tc client.Client, cs controllerPB.ControllerPrivateServiceClient) Service {
+// NewService returns a new service instance, the usage service client is optional and can be nil
+func NewService(r repository.Repository, t triton.Triton, m mgmtPB.MgmtPrivateServiceClient, rc *redis.Client, tc client.Client, cs controllerPB.ControllerPrivateServiceClient, uc usagePB.UsageServiceClient) Service {
 	return &service{
 		repository:                  r,
 		triton:                      t,
@@ -96,6 +123,7 @@ func NewService(r repository.Repository, t triton.Triton, m mgmtPB.MgmtPrivateSe
 		redisClient:                 rc,
 		temporalClient:              tc,
 		controllerClient:            cs,
+		usageServiceClient:          uc,
 	}
 }
 
@@ -699,6 +727,12 @@ func (s *service) DeleteModel(ctx context.Context, owner string, modelID string)
 		return st.Err()
 	}
 
+	release, err := s.claimOperation(ctx, "DeleteModelWorkflow", owner, &modelInDB)
+	if err != nil {
+		return err
+	}
+	defer release()
+
 	if err := s.UndeployModel(ctx, modelInDB.UID); err != nil {
 		if err := s.UpdateResourceState(
 			ctx,
@@ -712,17 +746,31 @@ func (s *service) DeleteModel(ctx context.Context, owner string, modelID string)
 		return err
 	}
 
-	// remove README.md
-	_ = os.RemoveAll(fmt.Sprintf("%v/%v#%v#README.md", config.Config.TritonServer.ModelStore, owner, modelInDB.ID))
+	// remove README.md, which is suffixed with the model tag
+	readmeFiles, err := filepath.Glob(fmt.Sprintf("%v/%v#%v#README.md*", config.Config.TritonServer.ModelStore, owner, modelInDB.ID))
+	if err != nil {
+		return status.Errorf(codes.Internal, err.Error())
+	}
+	for _, readmeFile := range readmeFiles {
+		if err := os.RemoveAll(readmeFile); err != nil {
+			return status.Errorf(codes.Internal, "unable to remove the model README file: %s", err.Error())
+		}
+	}
 	tritonModels, err := s.repository.GetTritonModels(modelInDB.UID)
 	if err == nil {
 		// remove model folders
 		for i := 0; i < len(tritonModels); i++ {
 			modelDir := filepath.Join(config.Config.TritonServer.ModelStore, tritonModels[i].Name)
-			_ = os.RemoveAll(modelDir)
+			if err := os.RemoveAll(modelDir); err != nil {
+				return status.Errorf(codes.Internal, "unable to remove the model directory: %s", err.Error())
+			}
 		}
 	}
 
+	if err := s.deleteModelSchedules(ctx, owner, modelInDB.UID); err != nil {
+		return err
+	}
+
 	if err := s.DeleteResourceState(ctx, modelInDB.UID); err != nil {
 		return err
 	}
@@ -7,14 +7,30 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.temporal.io/api/enums/v1"
+	"go.temporal.io/api/serviceerror"
+	"go.temporal.io/api/workflowservice/v1"
+	"go.temporal.io/sdk/client"
+	"go.temporal.io/sdk/converter"
+	"go.temporal.io/sdk/temporal"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/protobuf/types/known/structpb"
 
 	uuid "github.com/gofrs/uuid"
 	gomock "github.com/golang/mock/gomock"
+	commonpb "go.temporal.io/api/common/v1"
+	workflowpb "go.temporal.io/api/workflow/v1"
+	temporalmocks "go.temporal.io/sdk/mocks"
+	grpcstatus "google.golang.org/grpc/status"
 
 	"github.com/instill-ai/model-backend/pkg/service"
+	"github.com/instill-ai/model-backend/pkg/worker"
 
 	datamodel "github.com/instill-ai/model-backend/pkg/datamodel"
 	inferenceserver "github.com/instill-ai/model-backend/pkg/triton/inferenceserver"
@@ -79,7 +95,7 @@ func TestGetModelByID(t *testing.T) {
 			GetModelByID(gomock.Eq(OWNER), gomock.Eq(newModel.ID), modelPB.View_VIEW_FULL).
 			Return(datamodel.Model{}, nil).
 			Times(1)
-		s := service.NewService(mockRepository, nil, nil, nil, nil, nil)
+		s := service.NewService(mockRepository, nil, nil, nil, nil, nil, nil)
 
 		_, err := s.GetModelByID(context.Background(), OWNER, newModel.ID, modelPB.View_VIEW_FULL)
 		assert.NoError(t, err)
@@ -107,7 +123,7 @@ func TestGetModelByUID(t *testing.T) {
 			GetModelByUID(gomock.Eq(OWNER), gomock.Eq(newModel.UID), modelPB.View_VIEW_FULL).
 			Return(datamodel.Model{}, nil).
 			Times(1)
-		s := service.NewService(mockRepository, nil, nil, nil, nil, nil)
+		s := service.NewService(mockRepository, nil, nil, nil, nil, nil, nil)
 
 		_, err := s.GetModelByUID(context.Background(), OWNER, uid, modelPB.View_VIEW_FULL)
 		assert.NoError(t, err)
@@ -192,7 +208,7 @@ func TestRenameModel(t *testing.T) {
 				Owner:              OWNER,
 			}, nil).
 			Times(1)
-		s := service.NewService(mockRepository, nil, nil, nil, nil, nil)
+		s := service.NewService(mockRepository, nil, nil, nil, nil, nil, nil)
 
 		_, err := s.RenameModel(context.Background(), OWNER, ID, "new ID")
 		assert.NoError(t, err)
@@ -233,7 +249,7 @@ func TestPublishModel(t *testing.T) {
 			}).
 			Return(nil).
 			Times(1)
-		s := service.NewService(mockRepository, nil, nil, nil, nil, nil)
+		s := service.NewService(mockRepository, nil, nil, nil, nil, nil, nil)
 
 		_, err := s.PublishModel(context.Background(), OWNER, ID)
 		assert.NoError(t, err)
@@ -274,7 +290,7 @@ func TestUnpublishModel(t *testing.T) {
 			}).
 			Return(nil).
 			Times(1)
-		s := service.NewService(mockRepository, nil, nil, nil, nil, nil)
+		s := service.NewService(mockRepository, nil, nil, nil, nil, nil, nil)
 
 		_, err := s.UnpublishModel(context.Background(), OWNER, ID)
 		assert.NoError(t, err)
@@ -307,7 +323,7 @@ func TestUpdateModel(t *testing.T) {
 			GetModelByID(gomock.Eq(OWNER), gomock.Eq(newModel.ID), modelPB.View_VIEW_FULL).
 			Return(newModel, nil).
 			Times(1)
-		s := service.NewService(mockRepository, nil, nil, nil, nil, nil)
+		s := service.NewService(mockRepository, nil, nil, nil, nil, nil, nil)
 
 		_, err := s.UpdateModel(context.Background(), newModel.UID, &newModel)
 		assert.NoError(t, err)
@@ -324,7 +340,7 @@ func TestListModell(t *testing.T) {
 			ListModels(OWNER, modelPB.View_VIEW_FULL, int(100), "").
 			Return([]datamodel.Model{}, "", int64(100), nil).
 			Times(1)
-		s := service.NewService(mockRepository, nil, nil, nil, nil, nil)
+		s := service.NewService(mockRepository, nil, nil, nil, nil, nil, nil)
 
 		_, _, _, err := s.ListModels(context.Background(), OWNER, modelPB.View_VIEW_FULL, 100, "")
 		assert.NoError(t, err)
@@ -336,7 +352,7 @@ func TestModelInfer(t *testing.T) {
 		ctrl := gomock.NewController(t)
 		mockRepository := NewMockRepository(ctrl)
 		triton := NewMockTriton(ctrl)
-		s := service.NewService(mockRepository, triton, nil, nil, nil, nil)
+		s := service.NewService(mockRepository, triton, nil, nil, nil, nil, nil)
 
 		uid := uuid.UUID{}
 
@@ -455,7 +471,7 @@ func TestGetModelDefinition(t *testing.T) {
 			GetModelDefinition("github").
 			Return(datamodel.ModelDefinition{}, nil).
 			Times(1)
-		s := service.NewService(mockRepository, nil, nil, nil, nil, nil)
+		s := service.NewService(mockRepository, nil, nil, nil, nil, nil, nil)
 
 		_, err := s.GetModelDefinition(context.Background(), "github")
 		assert.NoError(t, err)
@@ -472,9 +488,629 @@ func TestListModelDefinitions(t *testing.T) {
 			ListModelDefinitions(modelPB.View_VIEW_FULL, int(100), "").
 			Return([]datamodel.ModelDefinition{}, "", int64(100), nil).
 			Times(1)
-		s := service.NewService(mockRepository, nil, nil, nil, nil, nil)
+		s := service.NewService(mockRepository, nil, nil, nil, nil, nil, nil)
 
 		_, _, _, err := s.ListModelDefinitions(context.Background(), modelPB.View_VIEW_FULL, 100, "")
 		assert.NoError(t, err)
 	})
 }
+
+func TestCheckReadiness(t *testing.T) {
+	t.Run("CheckReadiness", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+
+		mockRepository := NewMockRepository(ctrl)
+		mockRepository.
+			EXPECT().
+			Ping(gomock.Any()).
+			Return(nil)
+		triton := NewMockTriton(ctrl)
+		triton.
+			EXPECT().
+			IsTritonServerReady(gomock.Any()).
+			Return(false)
+
+		// Clients left nil are reported as not configured
+		s := service.NewService(mockRepository, triton, nil, nil, nil, nil, nil)
+
+		report := s.CheckReadiness(context.Background())
+		assert.False(t, report.Ready)
+		assert.False(t, report.Degraded)
+		assert.Len(t, report.Dependencies, 6)
+		for _, dep := range report.Dependencies {
+			assert.True(t, dep.Required)
+			if dep.Name == "postgres" {
+				assert.True(t, dep.Healthy)
+				assert.Empty(t, dep.Error)
+			} else {
+				assert.False(t, dep.Healthy)
+				assert.NotEmpty(t, dep.Error)
+			}
+			if dep.Name == "redis" || dep.Name == "temporal" {
+				assert.Equal(t, "client is not configured", dep.Error)
+			}
+		}
+	})
+}
+
+func TestSetModelIdleTimeout(t *testing.T) {
+	t.Run("SetModelIdleTimeout", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+
+		uid, _ := uuid.NewV4()
+		dbModel := datamodel.Model{
+			BaseDynamic: datamodel.BaseDynamic{UID: uid},
+			ID:          ID,
+			Owner:       OWNER,
+		}
+		mockRepository := NewMockRepository(ctrl)
+		mockRepository.
+			EXPECT().
+			GetModelByID(gomock.Eq(OWNER), gomock.Eq(ID), modelPB.View_VIEW_BASIC).
+			Return(dbModel, nil).
+			Times(2)
+		mockRepository.
+			EXPECT().
+			UpdateModelIdleTimeout(uid, 30).
+			Return(nil)
+		s := service.NewService(mockRepository, nil, nil, nil, nil, nil, nil)
+
+		_, err := s.SetModelIdleTimeout(context.Background(), OWNER, ID, 30)
+		assert.NoError(t, err)
+
+		_, err = s.SetModelIdleTimeout(context.Background(), OWNER, ID, -1)
+		assert.Error(t, err)
+	})
+}
+
+func TestEnsureModelLoaded(t *testing.T) {
+	t.Run("EnsureModelLoaded", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+
+		uid, _ := uuid.NewV4()
+		dbModel := datamodel.Model{
+			BaseDynamic: datamodel.BaseDynamic{UID: uid},
+			ID:          ID,
+			Owner:       OWNER,
+		}
+		// a loaded model neither reads the database nor writes to redis
+		mockRepository := NewMockRepository(ctrl)
+		s := service.NewService(mockRepository, nil, nil, nil, nil, nil, nil)
+
+		coldStart, err := s.EnsureModelLoaded(context.Background(), &dbModel)
+		assert.NoError(t, err)
+		assert.False(t, coldStart)
+	})
+}
+
+func TestGetOperation(t *testing.T) {
+	t.Run("GetOperation", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+
+		uid, _ := uuid.NewV4()
+		dbModel := datamodel.Model{
+			BaseDynamic: datamodel.BaseDynamic{UID: uid},
+			ID:          ID,
+			Owner:       OWNER,
+		}
+		mockRepository := NewMockRepository(ctrl)
+		mockRepository.
+			EXPECT().
+			GetModelByUID(gomock.Eq(OWNER), gomock.Eq(uid), modelPB.View_VIEW_FULL).
+			Return(dbModel, nil).
+			Times(4)
+
+		dataConverter := converter.GetDefaultDataConverter()
+		memo := &commonpb.Memo{Fields: map[string]*commonpb.Payload{}}
+		for key, value := range map[string]string{"owner": OWNER, "model_id": ID, "model_uid": uid.String()} {
+			payload, err := dataConverter.ToPayload(value)
+			assert.NoError(t, err)
+			memo.Fields[key] = payload
+		}
+		details, err := dataConverter.ToPayloads(worker.OperationProgress{
+			Stage:           "fetch",
+			Progress:        40,
+			DownloadedBytes: 400,
+			TotalBytes:      1000,
+		})
+		assert.NoError(t, err)
+		startTime := time.Now()
+		runID := uuid.Must(uuid.NewV4()).String()
+		describeRun := func(workflowID string, runID string, workflowType string, workflowStatus enums.WorkflowExecutionStatus, pendingActivities ...*workflowpb.PendingActivityInfo) *workflowservice.DescribeWorkflowExecutionResponse {
+			return &workflowservice.DescribeWorkflowExecutionResponse{
+				WorkflowExecutionInfo: &workflowpb.WorkflowExecutionInfo{
+					Execution: &commonpb.WorkflowExecution{WorkflowId: workflowID, RunId: runID},
+					Type:      &commonpb.WorkflowType{Name: workflowType},
+					Status:    workflowStatus,
+					StartTime: &startTime,
+					Memo:      memo,
+				},
+				PendingActivities: pendingActivities,
+			}
+		}
+		describe := func(workflowID string, workflowType string, workflowStatus enums.WorkflowExecutionStatus, pendingActivities ...*workflowpb.PendingActivityInfo) *workflowservice.DescribeWorkflowExecutionResponse {
+			return describeRun(workflowID, runID, workflowType, workflowStatus, pendingActivities...)
+		}
+
+		mockRun := &temporalmocks.WorkflowRun{}
+		mockRun.
+			On("Get", mock.Anything, nil).
+			Return(temporal.NewNonRetryableApplicationError("invalid model package: infer: missing config.pbtxt", "InvalidModelPackage", nil))
+		mockClient := &temporalmocks.Client{}
+		mockClient.
+			On("DescribeWorkflowExecution", mock.Anything, "running", "").
+			Return(describe("running", "DeployModelWorkflow", enums.WORKFLOW_EXECUTION_STATUS_RUNNING, &workflowpb.PendingActivityInfo{
+				ActivityType:     &commonpb.ActivityType{Name: "FetchModelActivity"},
+				HeartbeatDetails: details,
+			}), nil)
+		mockClient.
+			On("DescribeWorkflowExecution", mock.Anything, "completed", "").
+			Return(describe("completed", "DeployModelWorkflow", enums.WORKFLOW_EXECUTION_STATUS_COMPLETED), nil)
+		mockClient.
+			On("DescribeWorkflowExecution", mock.Anything, "failed", "").
+			Return(describe("failed", "DeployModelWorkflow", enums.WORKFLOW_EXECUTION_STATUS_FAILED), nil)
+		mockClient.
+			On("GetWorkflow", mock.Anything, "failed", runID).
+			Return(mockRun)
+		deployRunID := uuid.Must(uuid.NewV4()).String()
+		mockClient.
+			On("DescribeWorkflowExecution", mock.Anything, "model", deployRunID).
+			Return(describeRun("model", deployRunID, "DeployModelWorkflow", enums.WORKFLOW_EXECUTION_STATUS_COMPLETED), nil)
+		mockClient.
+			On("DescribeWorkflowExecution", mock.Anything, "model", "").
+			Return(describe("model", "UnDeployModelWorkflow", enums.WORKFLOW_EXECUTION_STATUS_RUNNING), nil)
+		s := service.NewService(mockRepository, nil, nil, nil, mockClient, nil, nil)
+
+		operation, model, err := s.GetOperation(context.Background(), OWNER, "running")
+		assert.NoError(t, err)
+		assert.False(t, operation.Done)
+		assert.Equal(t, ID, model.ID)
+		metadata := &structpb.Struct{}
+		assert.NoError(t, operation.Metadata.UnmarshalTo(metadata))
+		assert.Equal(t, "deploy", metadata.Fields["kind"].GetStringValue())
+		assert.Equal(t, "models/"+ID, metadata.Fields["model"].GetStringValue())
+		assert.NotEmpty(t, metadata.Fields["start_time"].GetStringValue())
+		assert.Equal(t, "fetch", metadata.Fields["stage"].GetStringValue())
+		assert.Equal(t, float64(40), metadata.Fields["progress"].GetNumberValue())
+		assert.Equal(t, float64(400), metadata.Fields["downloaded_bytes"].GetNumberValue())
+
+		operation, _, err = s.GetOperation(context.Background(), OWNER, "completed")
+		assert.NoError(t, err)
+		assert.True(t, operation.Done)
+		assert.Nil(t, operation.GetError())
+		assert.NoError(t, operation.Metadata.UnmarshalTo(metadata))
+		assert.Equal(t, float64(100), metadata.Fields["progress"].GetNumberValue())
+
+		operation, _, err = s.GetOperation(context.Background(), OWNER, "failed")
+		assert.NoError(t, err)
+		assert.True(t, operation.Done)
+		assert.Equal(t, int32(codes.FailedPrecondition), operation.GetError().Code)
+		assert.Equal(t, "invalid model package: infer: missing config.pbtxt", operation.GetError().Message)
+
+		// the operations on a model share its workflow ID, a deploy is still described once an undeploy followed it
+		operation, _, err = s.GetOperation(context.Background(), OWNER, "model."+deployRunID)
+		assert.NoError(t, err)
+		assert.Equal(t, "operations/model."+deployRunID, operation.Name)
+		assert.True(t, operation.Done)
+		assert.NoError(t, operation.Metadata.UnmarshalTo(metadata))
+		assert.Equal(t, "deploy", metadata.Fields["kind"].GetStringValue())
+
+		// the operations of another owner are not found, whether their model exists or not
+		_, _, err = s.GetOperation(context.Background(), "users/other", "running")
+		assert.Equal(t, codes.NotFound, grpcstatus.Code(err))
+		_, _, err = s.GetOperation(context.Background(), "users/other", "failed")
+		assert.Equal(t, codes.NotFound, grpcstatus.Code(err))
+	})
+}
+
+func TestListAndCancelOperations(t *testing.T) {
+	t.Run("ListAndCancelOperations", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+
+		uid, _ := uuid.NewV4()
+		dbModel := datamodel.Model{
+			BaseDynamic: datamodel.BaseDynamic{UID: uid},
+			ID:          ID,
+			Owner:       OWNER,
+		}
+		mockRepository := NewMockRepository(ctrl)
+		mockRepository.
+			EXPECT().
+			GetModelByID(gomock.Eq(OWNER), gomock.Eq(ID), modelPB.View_VIEW_BASIC).
+			Return(dbModel, nil)
+		mockRepository.
+			EXPECT().
+			GetModelByUID(gomock.Eq(OWNER), gomock.Eq(uid), modelPB.View_VIEW_FULL).
+			Return(dbModel, nil).
+			AnyTimes()
+
+		dataConverter := converter.GetDefaultDataConverter()
+		newMemo := func(values map[string]string) *commonpb.Memo {
+			memo := &commonpb.Memo{Fields: map[string]*commonpb.Payload{}}
+			for key, value := range values {
+				payload, err := dataConverter.ToPayload(value)
+				assert.NoError(t, err)
+				memo.Fields[key] = payload
+			}
+			return memo
+		}
+		runID := uuid.Must(uuid.NewV4()).String()
+		newInfo := func(workflowID string, workflowType string, workflowStatus enums.WorkflowExecutionStatus, memo *commonpb.Memo) *workflowpb.WorkflowExecutionInfo {
+			return &workflowpb.WorkflowExecutionInfo{
+				Execution: &commonpb.WorkflowExecution{WorkflowId: workflowID, RunId: runID},
+				Type:      &commonpb.WorkflowType{Name: workflowType},
+				Status:    workflowStatus,
+				Memo:      memo,
+			}
+		}
+		ownMemo := newMemo(map[string]string{"owner": OWNER, "model_id": ID, "model_uid": uid.String()})
+		deploying := newInfo("deploying", "DeployModelWorkflow", enums.WORKFLOW_EXECUTION_STATUS_RUNNING, ownMemo)
+		undeployed := newInfo("undeployed", "UnDeployModelWorkflow", enums.WORKFLOW_EXECUTION_STATUS_COMPLETED, ownMemo)
+		other := newInfo("other", "DeployModelWorkflow", enums.WORKFLOW_EXECUTION_STATUS_RUNNING, newMemo(map[string]string{"owner": "users/other", "model_id": ID}))
+
+		mockClient := &temporalmocks.Client{}
+		mockClient.
+			On("ListWorkflow", mock.Anything, mock.MatchedBy(func(req *workflowservice.ListWorkflowExecutionsRequest) bool {
+				return req.Query == fmt.Sprintf("(WorkflowType = 'DeployModelWorkflow') AND ExecutionStatus = 'Running' AND (ModelUID = '%s' OR (ModelID = '%s' AND WorkflowType = 'CreateModelWorkflow')) AND ModelOwner = '%s'", uid, ID, OWNER)
+			})).
+			Return(&workflowservice.ListWorkflowExecutionsResponse{
+				Executions:    []*workflowpb.WorkflowExecutionInfo{deploying},
+				NextPageToken: []byte("next"),
+			}, nil)
+		mockClient.
+			On("DescribeWorkflowExecution", mock.Anything, "deploying", mock.Anything).
+			Return(&workflowservice.DescribeWorkflowExecutionResponse{WorkflowExecutionInfo: deploying}, nil)
+		mockClient.
+			On("DescribeWorkflowExecution", mock.Anything, "undeployed", "").
+			Return(&workflowservice.DescribeWorkflowExecutionResponse{WorkflowExecutionInfo: undeployed}, nil)
+		mockClient.
+			On("DescribeWorkflowExecution", mock.Anything, "other", "").
+			Return(&workflowservice.DescribeWorkflowExecutionResponse{WorkflowExecutionInfo: other}, nil)
+		mockClient.
+			On("CancelWorkflow", mock.Anything, "deploying", runID).
+			Return(nil)
+		s := service.NewService(mockRepository, nil, nil, nil, mockClient, nil, nil)
+
+		_, _, err := s.ListOperations(context.Background(), service.OperationFilter{Kind: "publish"}, 10, "")
+		assert.Equal(t, codes.InvalidArgument, grpcstatus.Code(err))
+		_, _, err = s.ListOperations(context.Background(), service.OperationFilter{State: "paused"}, 10, "")
+		assert.Equal(t, codes.InvalidArgument, grpcstatus.Code(err))
+
+		operations, nextPageToken, err := s.ListOperations(context.Background(), service.OperationFilter{Owner: OWNER, ModelID: ID, Kind: "deploy", State: "running"}, 10, "")
+		assert.NoError(t, err)
+		assert.Len(t, operations, 1)
+		assert.Equal(t, "operations/deploying."+runID, operations[0].Operation.Name)
+		assert.Equal(t, ID, operations[0].Model.ID)
+		assert.NotEmpty(t, nextPageToken)
+
+		_, _, err = s.CancelOperation(context.Background(), OWNER, "other")
+		assert.Equal(t, codes.NotFound, grpcstatus.Code(err))
+		_, _, err = s.CancelOperation(context.Background(), OWNER, "undeployed")
+		assert.Equal(t, codes.FailedPrecondition, grpcstatus.Code(err))
+		operation, _, err := s.CancelOperation(context.Background(), OWNER, strings.TrimPrefix(operations[0].Operation.Name, "operations/"))
+		assert.NoError(t, err)
+		assert.Equal(t, "operations/deploying."+runID, operation.Name)
+		mockClient.AssertCalled(t, "DescribeWorkflowExecution", mock.Anything, "deploying", runID)
+		mockClient.AssertCalled(t, "CancelWorkflow", mock.Anything, "deploying", runID)
+	})
+}
+
+func TestDeployModelAsync(t *testing.T) {
+	t.Run("DeployModelAsync", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+
+		uid, _ := uuid.NewV4()
+		dbModel := datamodel.Model{
+			BaseDynamic: datamodel.BaseDynamic{UID: uid},
+			ID:          ID,
+			Owner:       OWNER,
+		}
+		mockRepository := NewMockRepository(ctrl)
+		mockRepository.
+			EXPECT().
+			GetModelByUID(gomock.Eq(OWNER), gomock.Eq(uid), modelPB.View_VIEW_BASIC).
+			Return(dbModel, nil).
+			AnyTimes()
+
+		workflowID := fmt.Sprintf("model-%s", uid.String())
+		mockRun := &temporalmocks.WorkflowRun{}
+		mockRun.On("GetID").Return(workflowID)
+		runID := uuid.Must(uuid.NewV4()).String()
+		mockRun.On("GetRunID").Return(runID)
+
+		alreadyStarted := serviceerror.NewWorkflowExecutionAlreadyStarted("workflow already started", "", runID)
+		mockClient := &temporalmocks.Client{}
+		isOperationOptions := mock.MatchedBy(func(options client.StartWorkflowOptions) bool {
+			return options.ID == workflowID &&
+				options.WorkflowIDReusePolicy == enums.WORKFLOW_ID_REUSE_POLICY_ALLOW_DUPLICATE &&
+				options.WorkflowExecutionErrorWhenAlreadyStarted
+		})
+		mockClient.
+			On("ExecuteWorkflow", mock.Anything, isOperationOptions, "DeployModelWorkflow", mock.Anything).
+			Return(mockRun, nil).
+			Once()
+		mockClient.
+			On("ExecuteWorkflow", mock.Anything, isOperationOptions, mock.Anything, mock.Anything).
+			Return(nil, alreadyStarted)
+		mockClient.
+			On("DescribeWorkflowExecution", mock.Anything, workflowID, "").
+			Return(&workflowservice.DescribeWorkflowExecutionResponse{
+				WorkflowExecutionInfo: &workflowpb.WorkflowExecutionInfo{
+					Execution: &commonpb.WorkflowExecution{WorkflowId: workflowID, RunId: runID},
+					Type:      &commonpb.WorkflowType{Name: "DeployModelWorkflow"},
+					Status:    enums.WORKFLOW_EXECUTION_STATUS_RUNNING,
+				},
+			}, nil)
+		s := service.NewService(mockRepository, nil, nil, nil, mockClient, nil, nil)
+
+		// the operations on a model share its workflow ID, a second deploy joins the running one
+		for i := 0; i < 2; i++ {
+			id, err := s.DeployModelAsync(context.Background(), OWNER, uid)
+			assert.NoError(t, err)
+			assert.Equal(t, workflowID+"."+runID, id)
+		}
+
+		// Temporal rejects an undeploy while the deploy is running
+		_, err := s.UndeployModelAsync(context.Background(), OWNER, uid)
+		assert.Equal(t, codes.Aborted, grpcstatus.Code(err))
+		mockClient.AssertNumberOfCalls(t, "ExecuteWorkflow", 3)
+	})
+}
+
+type fakeScheduleHandle struct {
+	client.ScheduleHandle
+	id          string
+	description *client.ScheduleDescription
+	deleted     bool
+}
+
+func (h *fakeScheduleHandle) GetID() string {
+	return h.id
+}
+
+func (h *fakeScheduleHandle) Describe(ctx context.Context) (*client.ScheduleDescription, error) {
+	if h.description == nil {
+		return nil, serviceerror.NewNotFound("schedule not found")
+	}
+	return h.description, nil
+}
+
+func (h *fakeScheduleHandle) Pause(ctx context.Context, options client.SchedulePauseOptions) error {
+	h.description.Schedule.State.Paused = true
+	return nil
+}
+
+func (h *fakeScheduleHandle) Unpause(ctx context.Context, options client.ScheduleUnpauseOptions) error {
+	h.description.Schedule.State.Paused = false
+	return nil
+}
+
+func (h *fakeScheduleHandle) Delete(ctx context.Context) error {
+	h.deleted = true
+	return nil
+}
+
+func newOf[T any](_ *T) *T {
+	return new(T)
+}
+
+// fakeScheduleClient keeps the schedules it creates in memory
+type fakeScheduleClient struct {
+	client.ScheduleClient
+	handles map[string]*fakeScheduleHandle
+	options []client.ScheduleOptions
+}
+
+func (c *fakeScheduleClient) Create(ctx context.Context, options client.ScheduleOptions) (client.ScheduleHandle, error) {
+	memo := &commonpb.Memo{Fields: map[string]*commonpb.Payload{}}
+	for key, value := range options.Memo {
+		payload, err := converter.GetDefaultDataConverter().ToPayload(value)
+		if err != nil {
+			return nil, err
+		}
+		memo.Fields[key] = payload
+	}
+	c.options = append(c.options, options)
+	c.handles[options.ID] = &fakeScheduleHandle{
+		id: options.ID,
+		description: &client.ScheduleDescription{
+			Memo: memo,
+		},
+	}
+	// the type of the state is not exported by the client package
+	c.handles[options.ID].description.Schedule.State = newOf(c.handles[options.ID].description.Schedule.State)
+	c.handles[options.ID].description.Schedule.State.Paused = options.Paused
+	return c.handles[options.ID], nil
+}
+
+func (c *fakeScheduleClient) GetHandle(ctx context.Context, scheduleID string) client.ScheduleHandle {
+	if handle, ok := c.handles[scheduleID]; ok {
+		return handle
+	}
+	return &fakeScheduleHandle{id: scheduleID}
+}
+
+func TestModelSchedules(t *testing.T) {
+	t.Run("ModelSchedules", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+
+		uid, _ := uuid.NewV4()
+		dbModel := datamodel.Model{
+			BaseDynamic: datamodel.BaseDynamic{UID: uid},
+			ID:          ID,
+			Owner:       OWNER,
+		}
+		mockRepository := NewMockRepository(ctrl)
+		mockRepository.
+			EXPECT().
+			GetModelByID(gomock.Eq(OWNER), gomock.Eq(ID), modelPB.View_VIEW_BASIC).
+			Return(dbModel, nil).
+			AnyTimes()
+
+		scheduleClient := &fakeScheduleClient{handles: map[string]*fakeScheduleHandle{}}
+		mockClient := &temporalmocks.Client{}
+		mockClient.On("ScheduleClient").Return(scheduleClient)
+		s := service.NewService(mockRepository, nil, nil, nil, mockClient, nil, nil)
+
+		_, err := s.CreateModelSchedule(context.Background(), OWNER, ID, "create", "0 8 * * 1-5", "")
+		assert.Equal(t, codes.InvalidArgument, grpcstatus.Code(err))
+		_, err = s.CreateModelSchedule(context.Background(), OWNER, ID, "deploy", "0 8 * * 1-5", "Mars/Olympus_Mons")
+		assert.Equal(t, codes.InvalidArgument, grpcstatus.Code(err))
+
+		schedule, err := s.CreateModelSchedule(context.Background(), OWNER, ID, "deploy", "0 8 * * 1-5", "Europe/Paris")
+		assert.NoError(t, err)
+		assert.Equal(t, "models/"+ID, schedule.Model)
+		assert.Equal(t, "deploy", schedule.Kind)
+		assert.Equal(t, "Europe/Paris", schedule.TimeZone)
+		assert.Len(t, scheduleClient.options, 1)
+		assert.Equal(t, []string{"0 8 * * 1-5"}, scheduleClient.options[0].Spec.CronExpressions)
+		assert.Equal(t, "Europe/Paris", scheduleClient.options[0].Spec.TimeZoneName)
+		assert.Equal(t, uid.String(), scheduleClient.options[0].SearchAttributes[worker.SearchAttributeModelUID])
+		action := scheduleClient.options[0].Action.(*client.ScheduleWorkflowAction)
+		assert.Equal(t, "ModelOperationWorkflow", action.Workflow)
+		assert.Equal(t, "deploy", action.Args[0].(*worker.OperationParams).Kind)
+		scheduleID := strings.TrimPrefix(schedule.Name, "schedules/")
+		assert.True(t, strings.HasPrefix(scheduleID, fmt.Sprintf("model-%s-", uid.String())))
+
+		// the schedules are searched by the search attributes of the workflows running them
+		mockClient.
+			On("ListWorkflow", mock.Anything, mock.MatchedBy(func(req *workflowservice.ListWorkflowExecutionsRequest) bool {
+				return req.Query == fmt.Sprintf("TemporalNamespaceDivision = 'TemporalScheduler' AND ExecutionStatus = 'Running' AND ModelOwner = '%s' AND ModelUID = '%s'", OWNER, uid)
+			})).
+			Return(&workflowservice.ListWorkflowExecutionsResponse{
+				Executions: []*workflowpb.WorkflowExecutionInfo{{
+					Execution: &commonpb.WorkflowExecution{WorkflowId: "temporal-sys-scheduler:" + scheduleID, RunId: "scheduler"},
+				}},
+			}, nil)
+		mockClient.
+			On("ListWorkflow", mock.Anything, mock.MatchedBy(func(req *workflowservice.ListWorkflowExecutionsRequest) bool {
+				return req.Query == "TemporalNamespaceDivision = 'TemporalScheduler' AND ExecutionStatus = 'Running' AND ModelOwner = 'users/other'"
+			})).
+			Return(&workflowservice.ListWorkflowExecutionsResponse{}, nil)
+
+		// a failed run is listed with the failure of its operation
+		scheduleTime := time.Date(2023, 6, 5, 8, 0, 0, 0, time.UTC)
+		scheduleClient.handles[scheduleID].description.Info.RecentActions = []client.ScheduleActionResult{{
+			ScheduleTime:        scheduleTime,
+			StartWorkflowResult: &client.ScheduleWorkflowExecution{WorkflowID: "schedule-run", FirstExecutionRunID: "run"},
+		}}
+		mockClient.
+			On("ListWorkflow", mock.Anything, mock.MatchedBy(func(req *workflowservice.ListWorkflowExecutionsRequest) bool {
+				return req.Query == "WorkflowId = 'schedule-run'"
+			})).
+			Return(&workflowservice.ListWorkflowExecutionsResponse{
+				Executions: []*workflowpb.WorkflowExecutionInfo{{
+					Execution: &commonpb.WorkflowExecution{WorkflowId: "schedule-run", RunId: "run"},
+					Status:    enums.WORKFLOW_EXECUTION_STATUS_FAILED,
+				}},
+			}, nil)
+		mockRun := &temporalmocks.WorkflowRun{}
+		mockRun.
+			On("Get", mock.Anything, mock.Anything).
+			Return(temporal.NewNonRetryableApplicationError("another operation is running on the model", "Aborted", nil))
+		mockClient.On("GetWorkflow", mock.Anything, "schedule-run", "run").Return(mockRun)
+
+		schedules, err := s.ListModelSchedules(context.Background(), OWNER, ID)
+		assert.NoError(t, err)
+		assert.Len(t, schedules, 1)
+		assert.Len(t, schedules[0].RecentRuns, 1)
+		assert.Equal(t, scheduleTime, schedules[0].RecentRuns[0].ScheduleTime)
+		assert.Equal(t, "failed", schedules[0].RecentRuns[0].State)
+		assert.Equal(t, "another operation is running on the model", schedules[0].RecentRuns[0].Error)
+
+		schedules, err = s.ListModelSchedules(context.Background(), "users/other", "")
+		assert.NoError(t, err)
+		assert.Empty(t, schedules)
+
+		_, err = s.PauseModelSchedule(context.Background(), "users/other", scheduleID, true)
+		assert.Equal(t, codes.NotFound, grpcstatus.Code(err))
+		schedule, err = s.PauseModelSchedule(context.Background(), OWNER, scheduleID, true)
+		assert.NoError(t, err)
+		assert.True(t, schedule.Paused)
+
+		assert.Equal(t, codes.NotFound, grpcstatus.Code(s.DeleteModelSchedule(context.Background(), OWNER, "missing")))
+		assert.NoError(t, s.DeleteModelSchedule(context.Background(), OWNER, scheduleID))
+		assert.True(t, scheduleClient.handles[scheduleID].deleted)
+	})
+}
+
+func TestBulkModelOperation(t *testing.T) {
+	t.Run("BulkModelOperation", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+
+		publicUID, _ := uuid.NewV4()
+		privateUID, _ := uuid.NewV4()
+		publicModel := datamodel.Model{
+			BaseDynamic: datamodel.BaseDynamic{UID: publicUID},
+			ID:          "public-model",
+			Owner:       OWNER,
+			Visibility:  datamodel.ModelVisibility(modelPB.Model_VISIBILITY_PUBLIC),
+		}
+		privateModel := datamodel.Model{
+			BaseDynamic: datamodel.BaseDynamic{UID: privateUID},
+			ID:          "private-model",
+			Owner:       OWNER,
+			Visibility:  datamodel.ModelVisibility(modelPB.Model_VISIBILITY_PRIVATE),
+		}
+		mockRepository := NewMockRepository(ctrl)
+		mockRepository.
+			EXPECT().
+			ListModels(gomock.Eq(OWNER), modelPB.View_VIEW_BASIC, 100, "").
+			Return([]datamodel.Model{publicModel, privateModel}, "", int64(2), nil)
+
+		mockRun := &temporalmocks.WorkflowRun{}
+		runID := uuid.Must(uuid.NewV4()).String()
+		mockRun.On("GetID").Return("bulk")
+		mockRun.On("GetRunID").Return(runID)
+		mockRun.
+			On("Get", mock.Anything, mock.AnythingOfType("*worker.BulkReport")).
+			Run(func(args mock.Arguments) {
+				*args.Get(1).(*worker.BulkReport) = worker.BulkReport{
+					Action:    "unpublish",
+					Succeeded: 1,
+					Results:   []worker.BulkModelResult{{Model: "models/public-model", State: "succeeded"}},
+				}
+			}).
+			Return(nil)
+
+		mockClient := &temporalmocks.Client{}
+		mockClient.
+			On("ExecuteWorkflow", mock.Anything, mock.Anything, "BulkModelWorkflow", mock.MatchedBy(func(param *worker.BulkParams) bool {
+				return param.Action == "unpublish" && param.Concurrency == worker.DefaultBulkConcurrency &&
+					param.Owner == OWNER && len(param.Models) == 1 && param.Models[0].UID == publicUID
+			})).
+			Return(mockRun, nil)
+		ownerPayload, err := converter.GetDefaultDataConverter().ToPayload(OWNER)
+		assert.NoError(t, err)
+		mockClient.
+			On("DescribeWorkflowExecution", mock.Anything, "bulk", runID).
+			Return(&workflowservice.DescribeWorkflowExecutionResponse{
+				WorkflowExecutionInfo: &workflowpb.WorkflowExecutionInfo{
+					Execution: &commonpb.WorkflowExecution{WorkflowId: "bulk", RunId: runID},
+					Type:      &commonpb.WorkflowType{Name: "BulkModelWorkflow"},
+					Status:    enums.WORKFLOW_EXECUTION_STATUS_COMPLETED,
+					Memo:      &commonpb.Memo{Fields: map[string]*commonpb.Payload{"owner": ownerPayload}},
+				},
+			}, nil)
+		mockClient.On("GetWorkflow", mock.Anything, "bulk", runID).Return(mockRun)
+		s := service.NewService(mockRepository, nil, nil, nil, mockClient, nil, nil)
+
+		_, err = s.BulkModelOperation(context.Background(), OWNER, "rename", service.BulkModelSelector{ModelIDs: []string{ID}}, 0)
+		assert.Equal(t, codes.InvalidArgument, grpcstatus.Code(err))
+		_, err = s.BulkModelOperation(context.Background(), OWNER, "deploy", service.BulkModelSelector{}, 0)
+		assert.Equal(t, codes.InvalidArgument, grpcstatus.Code(err))
+		_, err = s.BulkModelOperation(context.Background(), OWNER, "deploy", service.BulkModelSelector{ModelIDs: []string{ID}}, service.MaxBulkConcurrency+1)
+		assert.Equal(t, codes.InvalidArgument, grpcstatus.Code(err))
+		_, err = s.BulkModelOperation(context.Background(), OWNER, "deploy", service.BulkModelSelector{State: "sleeping"}, 0)
+		assert.Equal(t, codes.InvalidArgument, grpcstatus.Code(err))
+
+		operation, err := s.BulkModelOperation(context.Background(), OWNER, "unpublish", service.BulkModelSelector{Visibility: "public"}, 0)
+		assert.NoError(t, err)
+		assert.Equal(t, "operations/bulk."+runID, operation.Name)
+		assert.True(t, operation.Done)
+		response := &structpb.Struct{}
+		assert.NoError(t, operation.GetResponse().UnmarshalTo(response))
+		assert.Equal(t, float64(1), response.Fields["succeeded"].GetNumberValue())
+		assert.Equal(t, "models/public-model", response.Fields["results"].GetListValue().Values[0].GetStructValue().Fields["model"].GetStringValue())
+	})
+}
@@ -2,15 +2,25 @@ package service
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"time"
 
 	"cloud.google.com/go/longrunning/autogen/longrunningpb"
 	"github.com/gofrs/uuid"
 	"go.temporal.io/api/enums/v1"
+	"go.temporal.io/api/serviceerror"
 	"go.temporal.io/sdk/client"
+	"go.temporal.io/sdk/converter"
+	"go.temporal.io/sdk/temporal"
 	"google.golang.org/genproto/googleapis/rpc/status"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/protobuf/encoding/protojson"
 	"google.golang.org/protobuf/types/known/anypb"
+	"google.golang.org/protobuf/types/known/structpb"
 
+	commonpb "go.temporal.io/api/common/v1"
 	workflowpb "go.temporal.io/api/workflow/v1"
 
 	"github.com/instill-ai/model-backend/pkg/datamodel"
@@ -20,115 +30,282 @@ import (
 	modelv1alpha "github.com/instill-ai/protogen-go/model/model/v1alpha"
 )
 
-func (s *service) DeployModelAsync(ctx context.Context, owner string, modelUID uuid.UUID) (string, error) {
+// operationNamespace is the namespace of the UUID a model being created is known by in the ID of its operation
+var operationNamespace = uuid.NewV5(uuid.NamespaceURL, "model-backend/operations")
+
+// getCreateModelUID returns the UID the operation creating a model is known by, the model is given its UID once it
+// is created
+func getCreateModelUID(owner string, modelID string) uuid.UUID {
+	return uuid.NewV5(operationNamespace, fmt.Sprintf("%s/models/%s", owner, modelID))
+}
+
+// startOperation starts the workflow of an operation on a model under the operation workflow ID of the model, and
+// returns the ID of its operation, or of the same operation already running. Temporal runs a single workflow per ID, so
+// the start fails with an Aborted error while an operation of another kind is running on the model.
+func (s *service) startOperation(ctx context.Context, workflowType string, owner string, modelUID uuid.UUID, model *datamodel.Model) (string, error) {
 	logger, _ := logger.GetZapLogger(ctx)
-	id, _ := uuid.NewV4()
+
 	workflowOptions := client.StartWorkflowOptions{
-		ID:        id.String(),
-		TaskQueue: worker.TaskQueue,
+		ID:                                       worker.OperationWorkflowID(modelUID),
+		TaskQueue:                                worker.TaskQueue,
+		Memo:                                     worker.OperationMemo(owner, model),
+		SearchAttributes:                         worker.OperationSearchAttributes(owner, model),
+		WorkflowIDReusePolicy:                    enums.WORKFLOW_ID_REUSE_POLICY_ALLOW_DUPLICATE,
+		WorkflowExecutionErrorWhenAlreadyStarted: true,
+	}
+
+	// the running operation may finish between the failed start and its description, the start is then retried once
+	for attempt := 0; ; attempt++ {
+		we, err := s.temporalClient.ExecuteWorkflow(
+			ctx,
+			workflowOptions,
+			workflowType,
+			&worker.ModelParams{
+				Model: *model,
+				Owner: owner,
+			})
+		if err == nil {
+			logger.Info(fmt.Sprintf("started workflow with WorkflowID %s and RunID %s", we.GetID(), we.GetRunID()))
+			return worker.OperationID(we.GetID(), we.GetRunID()), nil
+		}
+		var alreadyStarted *serviceerror.WorkflowExecutionAlreadyStarted
+		if !errors.As(err, &alreadyStarted) {
+			logger.Error(fmt.Sprintf("unable to execute workflow: %s", err.Error()))
+			return "", err
+		}
+
+		workflowExecutionRes, err := s.temporalClient.DescribeWorkflowExecution(ctx, workflowOptions.ID, "")
+		if err != nil {
+			return "", err
+		}
+		info := workflowExecutionRes.GetWorkflowExecutionInfo()
+		if info.GetStatus() != enums.WORKFLOW_EXECUTION_STATUS_RUNNING && attempt == 0 {
+			continue
+		}
+		// a second request for the same operation joins the running one
+		if info.GetType().GetName() == workflowType {
+			return worker.OperationID(workflowOptions.ID, info.GetExecution().GetRunId()), nil
+		}
+		return "", newOperationConflictError(info)
 	}
+}
 
+func (s *service) DeployModelAsync(ctx context.Context, owner string, modelUID uuid.UUID) (string, error) {
 	model, err := s.repository.GetModelByUID(owner, modelUID, modelv1alpha.View_VIEW_BASIC)
 	if err != nil {
 		return "", err
 	}
 
-	we, err := s.temporalClient.ExecuteWorkflow(
-		ctx,
-		workflowOptions,
-		"DeployModelWorkflow",
-		&worker.ModelParams{
-			Model: model,
-			Owner: owner,
-		})
+	return s.startOperation(ctx, "DeployModelWorkflow", owner, modelUID, &model)
+}
+
+func (s *service) UndeployModelAsync(ctx context.Context, owner string, modelUID uuid.UUID) (string, error) {
+	model, err := s.repository.GetModelByUID(owner, modelUID, modelv1alpha.View_VIEW_BASIC)
 	if err != nil {
-		logger.Error(fmt.Sprintf("unable to execute workflow: %s", err.Error()))
 		return "", err
 	}
 
-	logger.Info(fmt.Sprintf("started workflow with WorkflowID %s and RunID %s", we.GetID(), we.GetRunID()))
+	return s.startOperation(ctx, "UnDeployModelWorkflow", owner, modelUID, &model)
+}
 
-	return id.String(), nil
+// OperationMetadata is the metadata of a model operation, its kind, the model it is about, when it started and ended
+// and the progress of the stage it is at
+type OperationMetadata struct {
+	Kind      string     `json:"kind"`
+	Model     string     `json:"model,omitempty"`
+	StartTime *time.Time `json:"start_time,omitempty"`
+	EndTime   *time.Time `json:"end_time,omitempty"`
+	worker.OperationProgress
 }
 
-func (s *service) UndeployModelAsync(ctx context.Context, owner string, modelUID uuid.UUID) (string, error) {
-	logger, _ := logger.GetZapLogger(ctx)
-	id, _ := uuid.NewV4()
-	workflowOptions := client.StartWorkflowOptions{
-		ID:        id.String(),
-		TaskQueue: worker.TaskQueue,
+// getOperationProgress returns the progress of the stage a model operation is at, read from the heartbeat details of
+// its pending activity, a completed operation is at 100%
+func getOperationProgress(workflowExecutionInfo *workflowpb.WorkflowExecutionInfo, pendingActivities []*workflowpb.PendingActivityInfo) (worker.OperationProgress, error) {
+	progress := worker.OperationProgress{}
+	switch workflowExecutionInfo.Status {
+	case enums.WORKFLOW_EXECUTION_STATUS_COMPLETED:
+		progress.Progress = 100
+	case enums.WORKFLOW_EXECUTION_STATUS_RUNNING:
+		for _, pendingActivity := range pendingActivities {
+			stage, ok := worker.OperationStages[pendingActivity.GetActivityType().GetName()]
+			if !ok {
+				continue
+			}
+			if pendingActivity.HeartbeatDetails != nil {
+				if err := converter.GetDefaultDataConverter().FromPayloads(pendingActivity.HeartbeatDetails, &progress); err != nil {
+					return progress, err
+				}
+			}
+			progress.Stage = stage
+		}
 	}
+	return progress, nil
+}
 
-	model, err := s.repository.GetModelByUID(owner, modelUID, modelv1alpha.View_VIEW_BASIC)
-	if err != nil {
-		return "", err
+// getOperationError returns the status of a failed workflow with the message of the failure of its activity, if any,
+// and the gRPC code matching the failure
+func getOperationError(workflowStatus enums.WorkflowExecutionStatus, err error) *status.Status {
+	switch workflowStatus {
+	case enums.WORKFLOW_EXECUTION_STATUS_CANCELED:
+		return &status.Status{Code: int32(codes.Canceled), Message: "operation was canceled"}
+	case enums.WORKFLOW_EXECUTION_STATUS_TERMINATED:
+		return &status.Status{Code: int32(codes.Aborted), Message: "operation was terminated"}
+	case enums.WORKFLOW_EXECUTION_STATUS_TIMED_OUT:
+		return &status.Status{Code: int32(codes.DeadlineExceeded), Message: "operation timed out"}
+	}
+	if err == nil {
+		return &status.Status{Code: int32(codes.Unknown), Message: "operation failed"}
 	}
 
-	we, err := s.temporalClient.ExecuteWorkflow(
-		ctx,
-		workflowOptions,
-		"UnDeployModelWorkflow",
-		&worker.ModelParams{
-			Model: model,
-			Owner: owner,
-		})
+	prefix := ""
+	var activityErr *temporal.ActivityError
+	if errors.As(err, &activityErr) {
+		if stage, ok := worker.OperationStages[activityErr.ActivityType().GetName()]; ok {
+			prefix = fmt.Sprintf("%s stage failed: ", stage)
+		}
+	}
+	var applicationErr *temporal.ApplicationError
+	var timeoutErr *temporal.TimeoutError
+	var canceledErr *temporal.CanceledError
+	switch {
+	case errors.As(err, &applicationErr):
+		code := codes.Internal
+		if applicationErr.NonRetryable() {
+			code = codes.FailedPrecondition
+		}
+		return &status.Status{Code: int32(code), Message: prefix + applicationErr.Message()}
+	case errors.As(err, &timeoutErr):
+		return &status.Status{Code: int32(codes.DeadlineExceeded), Message: prefix + timeoutErr.Error()}
+	case errors.As(err, &canceledErr):
+		return &status.Status{Code: int32(codes.Canceled), Message: prefix + "operation was canceled"}
+	default:
+		return &status.Status{Code: int32(codes.Unknown), Message: prefix + err.Error()}
+	}
+}
+
+// readOperationMemo returns the memo of the workflow of a model operation, or of a model schedule
+func readOperationMemo(operationMemo *commonpb.Memo) map[string]string {
+	memo := map[string]string{}
+	for key, payload := range operationMemo.GetFields() {
+		var value string
+		if err := converter.GetDefaultDataConverter().FromPayload(payload, &value); err == nil {
+			memo[key] = value
+		}
+	}
+	return memo
+}
 
+// newAnyStruct returns the JSON of a value as an Any holding a Struct
+func newAnyStruct(v interface{}) (*anypb.Any, error) {
+	b, err := json.Marshal(v)
 	if err != nil {
-		logger.Error(fmt.Sprintf("unable to execute workflow: %s", err.Error()))
-		return "", err
+		return nil, err
+	}
+	st := &structpb.Struct{}
+	if err := protojson.Unmarshal(b, st); err != nil {
+		return nil, err
 	}
+	return anypb.New(st)
+}
 
-	logger.Info(fmt.Sprintf("started workflow with WorkflowID %s and RunID %s", we.GetID(), we.GetRunID()))
+// GetOperation returns the model operation of an owner run by a workflow run with its metadata, its error if it failed,
+// and the model it is about, nil when the model is gone. The response of a successful operation on a model is left for
+// the caller to fill. The owner is checked against the memo of the workflow, so that it holds for the operations on
+// the deleted models too.
+func (s *service) GetOperation(ctx context.Context, owner string, operationID string) (*longrunningpb.Operation, *datamodel.Model, error) {
+	workflowID, runID := worker.ParseOperationID(operationID)
+	workflowExecutionRes, err := s.temporalClient.DescribeWorkflowExecution(ctx, workflowID, runID)
 
-	return id.String(), nil
+	if err != nil {
+		var notFound *serviceerror.NotFound
+		if errors.As(err, &notFound) {
+			return nil, nil, newOperationNotFoundError(operationID)
+		}
+		return nil, nil, err
+	}
+	if readOperationMemo(workflowExecutionRes.WorkflowExecutionInfo.GetMemo())[worker.OperationMemoOwner] != owner {
+		return nil, nil, newOperationNotFoundError(operationID)
+	}
+	return s.getOperation(ctx, workflowExecutionRes.WorkflowExecutionInfo, workflowExecutionRes.PendingActivities)
 }
 
-func getOperationFromWorkflowInfo(workflowExecutionInfo *workflowpb.WorkflowExecutionInfo) (*longrunningpb.Operation, error) {
-	operation := longrunningpb.Operation{}
+func (s *service) getOperation(ctx context.Context, info *workflowpb.WorkflowExecutionInfo, pendingActivities []*workflowpb.PendingActivityInfo) (*longrunningpb.Operation, *datamodel.Model, error) {
+	memo := readOperationMemo(info.GetMemo())
+	var dbModel *datamodel.Model
+	if owner := memo[worker.OperationMemoOwner]; owner != "" {
+		var model datamodel.Model
+		if modelUID, err := uuid.FromString(memo[worker.OperationMemoModelUID]); err == nil {
+			model, err = s.repository.GetModelByUID(owner, modelUID, modelv1alpha.View_VIEW_FULL)
+			if err == nil {
+				dbModel = &model
+			}
+		} else if memo[worker.OperationMemoModelID] != "" {
+			model, err = s.repository.GetModelByID(owner, memo[worker.OperationMemoModelID], modelv1alpha.View_VIEW_FULL)
+			if err == nil {
+				dbModel = &model
+			}
+		}
+	}
+
+	progress, err := getOperationProgress(info, pendingActivities)
+	if err != nil {
+		return nil, nil, err
+	}
+	metadata := OperationMetadata{
+		Kind:              worker.OperationKinds[info.GetType().GetName()],
+		StartTime:         info.StartTime,
+		EndTime:           info.CloseTime,
+		OperationProgress: progress,
+	}
+	switch {
+	case dbModel != nil:
+		metadata.Model = fmt.Sprintf("models/%s", dbModel.ID)
+	case memo[worker.OperationMemoModelID] != "":
+		metadata.Model = fmt.Sprintf("models/%s", memo[worker.OperationMemoModelID])
+	}
 
-	switch workflowExecutionInfo.Status {
+	operation := &longrunningpb.Operation{
+		Name: fmt.Sprintf("operations/%s", worker.OperationID(info.Execution.WorkflowId, info.Execution.RunId)),
+	}
+	if operation.Metadata, err = newAnyStruct(metadata); err != nil {
+		return nil, nil, err
+	}
+	switch info.Status {
+	case enums.WORKFLOW_EXECUTION_STATUS_RUNNING, enums.WORKFLOW_EXECUTION_STATUS_CONTINUED_AS_NEW:
 	case enums.WORKFLOW_EXECUTION_STATUS_COMPLETED:
-		operation = longrunningpb.Operation{
-			Done: true,
-			Result: &longrunningpb.Operation_Response{
-				Response: &anypb.Any{},
-			},
-		}
-	case enums.WORKFLOW_EXECUTION_STATUS_RUNNING:
-	case enums.WORKFLOW_EXECUTION_STATUS_CONTINUED_AS_NEW:
-		operation = longrunningpb.Operation{
-			Done: false,
-			Result: &longrunningpb.Operation_Response{
-				Response: &anypb.Any{},
-			},
+		operation.Done = true
+		// the response of a bulk operation is its report on every model
+		if info.GetType().GetName() == "BulkModelWorkflow" {
+			var report worker.BulkReport
+			if err := s.temporalClient.GetWorkflow(ctx, info.Execution.WorkflowId, info.Execution.RunId).Get(ctx, &report); err != nil {
+				return nil, nil, err
+			}
+			response, err := newAnyStruct(report)
+			if err != nil {
+				return nil, nil, err
+			}
+			operation.Result = &longrunningpb.Operation_Response{Response: response}
 		}
 	default:
-		operation = longrunningpb.Operation{
-			Done: true,
-			Result: &longrunningpb.Operation_Error{
-				Error: &status.Status{
-					Code:    int32(workflowExecutionInfo.Status),
-					Details: []*anypb.Any{},
-					Message: "",
-				},
-			},
+		operation.Done = true
+		var workflowErr error
+		if info.Status == enums.WORKFLOW_EXECUTION_STATUS_FAILED {
+			workflowErr = s.temporalClient.GetWorkflow(ctx, info.Execution.WorkflowId, info.Execution.RunId).Get(ctx, nil)
+		}
+		operation.Result = &longrunningpb.Operation_Error{
+			Error: getOperationError(info.Status, workflowErr),
 		}
 	}
 
-	operation.Name = fmt.Sprintf("operations/%s", workflowExecutionInfo.Execution.WorkflowId)
-	return &operation, nil
+	return operation, dbModel, nil
 }
 
-func (s *service) GetOperation(ctx context.Context, workflowId string) (*longrunningpb.Operation, error) {
-	workflowExecutionRes, err := s.temporalClient.DescribeWorkflowExecution(ctx, workflowId, "")
-
-	if err != nil {
-		return nil, err
-	}
-	return getOperationFromWorkflowInfo(workflowExecutionRes.WorkflowExecutionInfo)
+func (s *service) CreateModelAsync(ctx context.Context, owner string, model *datamodel.Model) (string, error) {
+	return s.startOperation(ctx, "CreateModelWorkflow", owner, getCreateModelUID(owner, model.ID), model)
 }
 
-func (s *service) CreateModelAsync(ctx context.Context, owner string, model *datamodel.Model) (string, error) {
+// ReconcileModels runs one reconciliation pass and waits for its report
+func (s *service) ReconcileModels(ctx context.Context, dryRun bool) (*worker.ReconcileReport, error) {
 	logger, _ := logger.GetZapLogger(ctx)
 	id, _ := uuid.NewV4()
 	workflowOptions := client.StartWorkflowOptions{
@@ -139,17 +316,21 @@ func (s *service) CreateModelAsync(ctx context.Context, owner string, model *dat
 	we, err := s.temporalClient.ExecuteWorkflow(
 		ctx,
 		workflowOptions,
-		"CreateModelWorkflow",
-		&worker.ModelParams{
-			Model: *model,
-			Owner: owner,
+		"ReconcileModelsWorkflow",
+		&worker.ReconcileParams{
+			DryRun: dryRun,
 		})
 	if err != nil {
 		logger.Error(fmt.Sprintf("unable to execute workflow: %s", err.Error()))
-		return "", err
+		return nil, err
 	}
 
 	logger.Info(fmt.Sprintf("started workflow with WorkflowID %s and RunID %s", we.GetID(), we.GetRunID()))
 
-	return id.String(), nil
+	var report worker.ReconcileReport
+	if err := we.Get(ctx, &report); err != nil {
+		return nil, err
+	}
+
+	return &report, nil
 }
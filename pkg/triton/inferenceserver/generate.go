@@ -0,0 +1,5 @@
+package inferenceserver
+
+// The client is generated from the Triton protos in the proto folder, buf compiles them and runs the plugins pinned
+// in buf.gen.yaml
+//go:generate go run github.com/bufbuild/buf/cmd/buf@v1.17.0 generate proto
@@ -17,6 +17,7 @@ import (
 
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/protobuf/encoding/protojson"
 
 	"github.com/instill-ai/model-backend/config"
 	"github.com/instill-ai/model-backend/pkg/logger"
@@ -52,15 +53,17 @@ type ImageInput struct {
 type Triton interface {
 	ServerLiveRequest() *inferenceserver.ServerLiveResponse
 	ServerReadyRequest() *inferenceserver.ServerReadyResponse
+	ServerMetadataRequest() *inferenceserver.ServerMetadataResponse
 	ModelReadyRequest(ctx context.Context, modelName string, modelInstance string) *inferenceserver.ModelReadyResponse
 	ModelMetadataRequest(modelName string, modelInstance string) *inferenceserver.ModelMetadataResponse
 	ModelConfigRequest(modelName string, modelInstance string) *inferenceserver.ModelConfigResponse
 	ModelInferRequest(task modelPB.Model_Task, inferInput InferInput, modelName string, modelInstance string, modelMetadata *inferenceserver.ModelMetadataResponse, modelConfig *inferenceserver.ModelConfigResponse) (*inferenceserver.ModelInferResponse, error)
 	PostProcess(inferResponse *inferenceserver.ModelInferResponse, modelMetadata *inferenceserver.ModelMetadataResponse, task modelPB.Model_Task) (interface{}, error)
 	LoadModelRequest(modelName string) (*inferenceserver.RepositoryModelLoadResponse, error)
+	LoadModelWithConfigRequest(modelName string, modelConfig *inferenceserver.ModelConfig) (*inferenceserver.RepositoryModelLoadResponse, error)
 	UnloadModelRequest(modelName string) (*inferenceserver.RepositoryModelUnloadResponse, error)
 	ListModelsRequest() *inferenceserver.RepositoryIndexResponse
-	IsTritonServerReady() bool
+	IsTritonServerReady(ctx context.Context) bool
 	Init()
 	Close()
 }
@@ -123,6 +126,20 @@ func (ts *triton) ServerReadyRequest() *inferenceserver.ServerReadyResponse {
 	return serverReadyResponse
 }
 
+func (ts *triton) ServerMetadataRequest() *inferenceserver.ServerMetadataResponse {
+	// Create context for our request with 10 second timeout
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	serverMetadataRequest := inferenceserver.ServerMetadataRequest{}
+	// Submit ServerMetadata request to server
+	serverMetadataResponse, err := ts.tritonClient.ServerMetadata(ctx, &serverMetadataRequest)
+	if err != nil {
+		log.Printf("Couldn't get server metadata: %v", err)
+	}
+	return serverMetadataResponse
+}
+
 func (ts *triton) ModelReadyRequest(ctx context.Context, modelName string, modelInstance string) *inferenceserver.ModelReadyResponse {
 	logger, _ := logger.GetZapLogger(ctx)
 
@@ -915,6 +932,35 @@ func (ts *triton) LoadModelRequest(modelName string) (*inferenceserver.Repositor
 	return ts.tritonClient.RepositoryModelLoad(ctx, &loadModelRequest)
 }
 
+// LoadModelWithConfigRequest loads a model with the given configuration in place of its config.pbtxt,
+// a nil configuration loads the model as shipped
+func (ts *triton) LoadModelWithConfigRequest(modelName string, modelConfig *inferenceserver.ModelConfig) (*inferenceserver.RepositoryModelLoadResponse, error) {
+	if modelConfig == nil {
+		return ts.LoadModelRequest(modelName)
+	}
+
+	// Create context for our request with 600 second timeout. The time for warmup model inference
+	ctx, cancel := context.WithTimeout(context.Background(), 600*time.Second)
+	defer cancel()
+
+	// Triton uses the "config" parameter, the model configuration in JSON, in place of the config.pbtxt
+	configJSON, err := protojson.MarshalOptions{UseProtoNames: true}.Marshal(modelConfig)
+	if err != nil {
+		return nil, err
+	}
+	loadModelRequest := inferenceserver.RepositoryModelLoadRequest{
+		RepositoryName: "",
+		ModelName:      modelName,
+		Parameters: map[string]*inferenceserver.ModelRepositoryParameter{
+			"config": {
+				ParameterChoice: &inferenceserver.ModelRepositoryParameter_StringParam{StringParam: string(configJSON)},
+			},
+		},
+	}
+
+	return ts.tritonClient.RepositoryModelLoad(ctx, &loadModelRequest)
+}
+
 func (ts *triton) UnloadModelRequest(modelName string) (*inferenceserver.RepositoryModelUnloadResponse, error) {
 	// Create context for our request with 10 second timeout
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
@@ -946,11 +992,12 @@ func (ts *triton) ListModelsRequest() *inferenceserver.RepositoryIndexResponse {
 	return listModelsResponse
 }
 
-func (ts *triton) IsTritonServerReady() bool {
-	serverLiveResponse := ts.ServerLiveRequest()
-	if serverLiveResponse == nil {
+// IsTritonServerReady tells whether the Triton server is live, within the deadline of the context
+func (ts *triton) IsTritonServerReady(ctx context.Context) bool {
+	serverLiveResponse, err := ts.tritonClient.ServerLive(ctx, &inferenceserver.ServerLiveRequest{})
+	if err != nil {
+		log.Printf("Couldn't get server live: %v", err)
 		return false
 	}
-	// fmt.Printf("Triton Health - Live: %v\n", serverLiveResponse.Live)
 	return serverLiveResponse.Live
 }
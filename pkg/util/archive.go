@@ -0,0 +1,271 @@
+package util
+
+import (
+	"archive/tar"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/klauspost/compress/zstd"
+
+	"github.com/instill-ai/model-backend/pkg/datamodel"
+
+	modelPB "github.com/instill-ai/protogen-go/model/model/v1alpha"
+)
+
+// modelArchiveExtractor writes the entries of a model archive into the model repository. The top folders,
+// i.e. the Triton models, are renamed to owner#modelID#folder#latest and the config files are updated accordingly.
+type modelArchiveExtractor struct {
+	dstDir           string
+	owner            string
+	model            *datamodel.Model
+	readmeFilePath   string
+	ensembleFilePath string
+	createdTModels   []datamodel.TritonModel
+	newModelNameMap  map[string]string
+	configFiles      []string
+	dirs             map[string]bool
+}
+
+func newModelArchiveExtractor(dstDir string, owner string, model *datamodel.Model) *modelArchiveExtractor {
+	return &modelArchiveExtractor{
+		dstDir:          dstDir,
+		owner:           owner,
+		model:           model,
+		newModelNameMap: map[string]string{},
+		dirs:            map[string]bool{},
+	}
+}
+
+func isIgnoredArchiveEntry(name string) bool {
+	return strings.Contains(name, "__MACOSX") || strings.Contains(name, "__pycache__") // ignore temp directory in macos
+}
+
+func (e *modelArchiveExtractor) checkPath(name string) error {
+	fPath := filepath.Join(e.dstDir, name)
+	if !strings.HasPrefix(fPath, filepath.Clean(e.dstDir)+string(os.PathSeparator)) {
+		return fmt.Errorf("invalid file path")
+	}
+	return nil
+}
+
+// addDir creates a model folder or a folder inside of a model, the parent folders missing from the archive are created first
+func (e *modelArchiveExtractor) addDir(name string) error {
+	if isIgnoredArchiveEntry(name) {
+		return nil
+	}
+	dirName := strings.TrimSuffix(name, "/")
+	if dirName == "" || e.dirs[dirName] {
+		return nil
+	}
+	if err := e.checkPath(dirName); err != nil {
+		return err
+	}
+	if idx := strings.LastIndex(dirName, "/"); idx >= 0 {
+		if err := e.addDir(dirName[:idx]); err != nil {
+			return err
+		}
+	}
+	e.dirs[dirName] = true
+
+	elems := strings.SplitN(dirName, "/", 2)
+	if len(elems) == 1 { // top directory model
+		dirName = fmt.Sprintf("%v#%v#%v#%v", e.owner, e.model.ID, dirName, "latest")
+		e.newModelNameMap[elems[0]] = dirName
+	} else { // version folder
+		currentNewModelName := e.newModelNameMap[elems[0]]
+		dirName = currentNewModelName + "/" + elems[1]
+		patternVersionFolder := fmt.Sprintf("^%v/[0-9]+$", regexp.QuoteMeta(currentNewModelName))
+		if match, _ := regexp.MatchString(patternVersionFolder, dirName); match {
+			if iVersion, err := strconv.ParseInt(elems[1], 10, 32); err == nil {
+				e.createdTModels = append(e.createdTModels, datamodel.TritonModel{
+					Name:    currentNewModelName, // Triton model name
+					State:   datamodel.ModelState(modelPB.Model_STATE_OFFLINE),
+					Version: int(iVersion),
+				})
+			}
+		}
+	}
+
+	fPath := filepath.Join(e.dstDir, dirName)
+	if err := ValidateFilePath(fPath); err != nil {
+		return err
+	}
+	return os.MkdirAll(fPath, os.ModePerm)
+}
+
+// addFile writes a file of the archive, the files at the top level of the archive such as README.md are renamed too
+func (e *modelArchiveExtractor) addFile(name string, mode os.FileMode, r io.Reader) error {
+	if isIgnoredArchiveEntry(name) {
+		return nil
+	}
+	if err := e.checkPath(name); err != nil {
+		return err
+	}
+	if idx := strings.LastIndex(name, "/"); idx >= 0 {
+		if err := e.addDir(name[:idx]); err != nil {
+			return err
+		}
+	}
+
+	// Update triton folder into format {model_name}#{task_name}#{task_version}
+	subStrs := strings.Split(name, "/")
+	// Triton modelname is folder name
+	oldModelName := subStrs[0]
+	subStrs[0] = fmt.Sprintf("%v#%v#%v#%v", e.owner, e.model.ID, subStrs[0], "latest")
+	newModelName := subStrs[0]
+	fPath := filepath.Join(e.dstDir, strings.Join(subStrs, "/"))
+	if strings.Contains(name, "README.md") {
+		e.readmeFilePath = fPath
+	}
+	if err := ValidateFilePath(fPath); err != nil {
+		return err
+	}
+	// ensure the parent folder existed
+	if err := os.MkdirAll(filepath.Dir(fPath), os.ModePerm); err != nil {
+		return err
+	}
+
+	dstFile, err := os.OpenFile(fPath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, mode)
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(dstFile, r)
+	dstFile.Close()
+	if err != nil {
+		return err
+	}
+
+	// Update ModelName in config.pbtxt
+	if filepath.Ext(fPath) == ".pbtxt" {
+		e.configFiles = append(e.configFiles, fPath)
+		if isEnsembleConfig(fPath) {
+			e.ensembleFilePath = fPath
+		}
+		if err := UpdateConfigModelName(fPath, oldModelName, newModelName); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// finish points the ensemble model to the renamed models and returns the README and ensemble config file paths
+func (e *modelArchiveExtractor) finish() (string, string, error) {
+	if e.ensembleFilePath == "" {
+		for _, filePath := range e.configFiles {
+			if couldBeEnsembleConfig(filePath) {
+				e.ensembleFilePath = filePath
+				break
+			}
+		}
+
+		for oldModelName, newModelName := range e.newModelNameMap {
+			err := UpdateModelName(filepath.Dir(e.ensembleFilePath)+"/1/model.py", oldModelName, newModelName) // TODO: replace in all files.
+			if err != nil {
+				return "", "", err
+			}
+		}
+	}
+	// Update ModelName in ensemble model config file
+	if e.ensembleFilePath != "" {
+		for oldModelName, newModelName := range e.newModelNameMap {
+			if err := UpdateConfigModelName(e.ensembleFilePath, oldModelName, newModelName); err != nil {
+				return "", "", err
+			}
+		}
+		for i := 0; i < len(e.createdTModels); i++ {
+			if strings.Contains(e.ensembleFilePath, e.createdTModels[i].Name) {
+				e.createdTModels[i].Platform = "ensemble"
+				break
+			}
+		}
+	}
+	e.model.TritonModels = e.createdTModels
+	return e.readmeFilePath, e.ensembleFilePath, nil
+}
+
+// Untar extracts a tar stream of a model into the model repository the same way as Unzip
+func Untar(r io.Reader, dstDir string, owner string, uploadedModel *datamodel.Model) (string, string, error) {
+	e := newModelArchiveExtractor(dstDir, owner, uploadedModel)
+	tr := tar.NewReader(r)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return "", "", err
+		}
+		name := strings.TrimPrefix(path.Clean(hdr.Name), "./")
+		if name == "." || name == "" {
+			continue
+		}
+		switch hdr.Typeflag {
+		case tar.TypeDir:
+			err = e.addDir(name)
+		case tar.TypeReg:
+			err = e.addFile(name, os.FileMode(hdr.Mode).Perm(), tr)
+		default: // links and special files have no place in a model repository
+			continue
+		}
+		if err != nil {
+			return "", "", err
+		}
+	}
+
+	return e.finish()
+}
+
+// ExtractModelArchive extracts a zip, tar, gzip or zstd compressed tar archive of a model into the model repository,
+// the format is detected from the content
+func ExtractModelArchive(fPath string, dstDir string, owner string, uploadedModel *datamodel.Model) (string, string, error) {
+	f, err := os.Open(fPath)
+	if err != nil {
+		return "", "", err
+	}
+	defer f.Close()
+
+	br := bufio.NewReader(f)
+	magic, _ := br.Peek(tarMagicOffset + len(tarMagic))
+	switch {
+	case bytes.HasPrefix(magic, []byte("PK\x03\x04")):
+		return Unzip(fPath, dstDir, owner, uploadedModel)
+	case bytes.HasPrefix(magic, []byte{0x1f, 0x8b}):
+		gr, err := gzip.NewReader(br)
+		if err != nil {
+			return "", "", err
+		}
+		defer gr.Close()
+		return Untar(gr, dstDir, owner, uploadedModel)
+	case bytes.HasPrefix(magic, []byte{0x28, 0xb5, 0x2f, 0xfd}):
+		return untarZstd(br, dstDir, owner, uploadedModel)
+	case len(magic) == tarMagicOffset+len(tarMagic) && bytes.Equal(magic[tarMagicOffset:], tarMagic):
+		return Untar(br, dstDir, owner, uploadedModel)
+	default:
+		return "", "", fmt.Errorf("unsupported archive format, only zip, tar, tar.gz and tar.zst are supported")
+	}
+}
+
+// tarMagic is found at the same offset in the header of both the POSIX and the GNU tar formats
+const tarMagicOffset = 257
+
+var tarMagic = []byte("ustar")
+
+// untarZstd decompresses a tar.zst archive and extracts the tar stream
+func untarZstd(r io.Reader, dstDir string, owner string, uploadedModel *datamodel.Model) (string, string, error) {
+	zr, err := zstd.NewReader(r)
+	if err != nil {
+		return "", "", err
+	}
+	defer zr.Close()
+	return Untar(zr, dstDir, owner, uploadedModel)
+}
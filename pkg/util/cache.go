@@ -0,0 +1,423 @@
+package util
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/instill-ai/model-backend/config"
+	"github.com/instill-ai/model-backend/pkg/logger"
+)
+
+// ErrModelCacheEntryNotFound is returned when evicting an entry which is not in the model cache
+var ErrModelCacheEntryNotFound = errors.New("model cache entry not found")
+
+// ErrModelCacheEntryInUse is returned when evicting an entry which is being fetched or restored
+var ErrModelCacheEntryInUse = errors.New("model cache entry is in use")
+
+// ModelCacheFile is a file of a cached artifact, its content is stored once by its hash
+type ModelCacheFile struct {
+	Path   string `json:"path"`
+	Sha256 string `json:"sha256"`
+	Size   int64  `json:"size"`
+}
+
+// ModelCacheEntry is an artifact of the model cache, the files of a source at a resolved revision
+type ModelCacheEntry struct {
+	Key        string           `json:"key"`
+	Source     string           `json:"source"`
+	Revision   string           `json:"revision"`
+	Size       int64            `json:"size"`
+	FileCount  int              `json:"file_count"`
+	Files      []ModelCacheFile `json:"files,omitempty"`
+	CreatedAt  time.Time        `json:"created_at"`
+	LastUsedAt time.Time        `json:"last_used_at"`
+}
+
+// GetModelCacheDir returns the root of the model cache, shared by the worker processes of a host
+func GetModelCacheDir() string {
+	if config.Config.Cache.ModelDir != "" {
+		return config.Config.Cache.ModelDir
+	}
+	return MODEL_CACHE_DIR
+}
+
+// ModelCacheKey returns the key of the artifact of a source, e.g. github:org/repo, at a resolved revision
+func ModelCacheKey(source string, revision string) string {
+	sum := sha256.Sum256([]byte(source + "\n" + revision))
+	return hex.EncodeToString(sum[:])
+}
+
+func modelCacheEntryPath(key string) string {
+	return filepath.Join(GetModelCacheDir(), "entries", key+".json")
+}
+
+func modelCacheBlobPath(hash string) string {
+	return filepath.Join(GetModelCacheDir(), "blobs", hash[:2], hash)
+}
+
+// lockModelCache takes an exclusive lock on a file of the cache, it fails with ErrModelCacheEntryInUse when the lock
+// is taken and wait is not set
+func lockModelCache(name string, wait bool) (func(), error) {
+	unlock, err := lockFile(filepath.Join(GetModelCacheDir(), "locks", name+".lock"), wait)
+	if errors.Is(err, errFileLocked) {
+		return nil, ErrModelCacheEntryInUse
+	}
+	return unlock, err
+}
+
+func readModelCacheEntry(key string) (*ModelCacheEntry, error) {
+	b, err := os.ReadFile(modelCacheEntryPath(key))
+	if err != nil {
+		return nil, err
+	}
+	entry := ModelCacheEntry{}
+	if err := json.Unmarshal(b, &entry); err != nil {
+		return nil, err
+	}
+	return &entry, nil
+}
+
+func writeModelCacheEntry(entry *ModelCacheEntry) error {
+	b, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	p := modelCacheEntryPath(entry.Key)
+	if err := os.MkdirAll(filepath.Dir(p), os.ModePerm); err != nil {
+		return err
+	}
+	// the entry is replaced atomically so that readers never see a partial manifest
+	tmp := p + ".tmp"
+	if err := os.WriteFile(tmp, b, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, p)
+}
+
+// linkOrCopy links src to dst, or copies it when they are not on the same file system
+func linkOrCopy(src string, dst string) error {
+	if err := os.Link(src, dst); err == nil {
+		return nil
+	}
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	out, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
+
+func hashFile(p string) (string, error) {
+	f, err := os.Open(p)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// restoreModelCacheEntry links the files of a cached artifact into dir, it returns false when the artifact is not
+// cached or some of its files were evicted
+func restoreModelCacheEntry(key string, dir string) (bool, error) {
+	unlock, err := lockModelCache("index", true)
+	if err != nil {
+		return false, err
+	}
+	entry, err := readModelCacheEntry(key)
+	if err == nil {
+		entry.LastUsedAt = time.Now()
+		err = writeModelCacheEntry(entry)
+	}
+	unlock()
+	if errors.Is(err, fs.ErrNotExist) {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+
+	for _, f := range entry.Files {
+		if _, err := os.Stat(modelCacheBlobPath(f.Sha256)); err != nil {
+			return false, nil
+		}
+	}
+	root := filepath.Clean(dir) + string(os.PathSeparator)
+	for _, f := range entry.Files {
+		dst := filepath.Join(dir, f.Path)
+		if !strings.HasPrefix(dst, root) {
+			return false, fmt.Errorf("cached file %s is outside of the model folder", f.Path)
+		}
+		if err := os.MkdirAll(filepath.Dir(dst), os.ModePerm); err != nil {
+			return false, err
+		}
+		if err := linkOrCopy(modelCacheBlobPath(f.Sha256), dst); err != nil {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+// storeModelCacheEntry stores the files of dir, but the Git metadata, as the artifact of a source at a revision
+func storeModelCacheEntry(source string, revision string, key string, dir string) error {
+	entry := ModelCacheEntry{
+		Key:      key,
+		Source:   source,
+		Revision: revision,
+		Files:    []ModelCacheFile{},
+	}
+	err := filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if d.Name() == ".git" {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		hash, err := hashFile(p)
+		if err != nil {
+			return err
+		}
+		blob := modelCacheBlobPath(hash)
+		if _, err := os.Stat(blob); err != nil {
+			if err := os.MkdirAll(filepath.Dir(blob), os.ModePerm); err != nil {
+				return err
+			}
+			tmp := fmt.Sprintf("%s.%d.%d.tmp", blob, os.Getpid(), time.Now().UnixNano())
+			_ = os.Remove(tmp)
+			if err := linkOrCopy(p, tmp); err != nil {
+				return err
+			}
+			if err := os.Rename(tmp, blob); err != nil {
+				return err
+			}
+		}
+		rel, _ := filepath.Rel(dir, p)
+		entry.Files = append(entry.Files, ModelCacheFile{Path: filepath.ToSlash(rel), Sha256: hash, Size: info.Size()})
+		entry.Size += info.Size()
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	entry.FileCount = len(entry.Files)
+	entry.CreatedAt = time.Now()
+	entry.LastUsedAt = entry.CreatedAt
+
+	unlock, err := lockModelCache("index", true)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	if err := writeModelCacheEntry(&entry); err != nil {
+		return err
+	}
+	if limit := config.Config.Cache.ModelSizeLimit; limit > 0 {
+		_, err := evictModelCacheEntries(limit, map[string]bool{key: true})
+		return err
+	}
+	return nil
+}
+
+// FetchModelArtifact fetches the artifact of a source at a resolved revision into dir through the model cache: the
+// cached files are linked into dir when there are some, otherwise the artifact is fetched and cached. The cache is
+// bypassed when it is disabled or the revision is unknown. It returns whether the artifact was cached.
+func FetchModelArtifact(ctx context.Context, source string, revision string, dir string, fetch func(dir string) error) (bool, error) {
+	if !config.Config.Cache.Model || revision == "" {
+		return false, fetch(dir)
+	}
+	logger, _ := logger.GetZapLogger(ctx)
+
+	// the key stays locked while the artifact is fetched, so that concurrent deployments fetch it once
+	key := ModelCacheKey(source, revision)
+	unlock, err := lockModelCache(key, true)
+	if err != nil {
+		return false, err
+	}
+	defer unlock()
+
+	if hit, err := restoreModelCacheEntry(key, dir); hit {
+		logger.Info(fmt.Sprintf("model cache hit for %s@%s", source, revision))
+		return true, nil
+	} else if err != nil {
+		logger.Warn(fmt.Sprintf("unable to restore %s@%s from the model cache: %s", source, revision, err))
+	}
+	_ = os.RemoveAll(dir)
+
+	if err := fetch(dir); err != nil {
+		return false, err
+	}
+	// a failure to cache the artifact does not fail its fetch
+	if err := storeModelCacheEntry(source, revision, key, dir); err != nil {
+		logger.Warn(fmt.Sprintf("unable to store %s@%s in the model cache: %s", source, revision, err))
+	}
+	return false, nil
+}
+
+func listModelCacheEntries() ([]*ModelCacheEntry, error) {
+	paths, err := filepath.Glob(filepath.Join(GetModelCacheDir(), "entries", "*.json"))
+	if err != nil {
+		return nil, err
+	}
+	entries := []*ModelCacheEntry{}
+	for _, p := range paths {
+		entry, err := readModelCacheEntry(strings.TrimSuffix(filepath.Base(p), ".json"))
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry)
+	}
+	// the least recently used entries first
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].LastUsedAt.Before(entries[j].LastUsedAt)
+	})
+	return entries, nil
+}
+
+// evictModelCacheEntries evicts the least recently used entries, but the kept and the in use ones, until the files of
+// the cache fit in limit. The files shared with a remaining entry are kept. It returns the evicted entries. The index
+// lock must be held.
+func evictModelCacheEntries(limit int64, keep map[string]bool) ([]*ModelCacheEntry, error) {
+	entries, err := listModelCacheEntries()
+	if err != nil {
+		return nil, err
+	}
+	refs := map[string]int{}
+	sizes := map[string]int64{}
+	var total int64
+	for _, entry := range entries {
+		for _, f := range entry.Files {
+			if refs[f.Sha256] == 0 {
+				sizes[f.Sha256] = f.Size
+				total += f.Size
+			}
+			refs[f.Sha256]++
+		}
+	}
+
+	evicted := []*ModelCacheEntry{}
+	for _, entry := range entries {
+		if total <= limit {
+			break
+		}
+		if keep[entry.Key] {
+			continue
+		}
+		unlock, err := lockModelCache(entry.Key, false)
+		if errors.Is(err, ErrModelCacheEntryInUse) {
+			continue
+		} else if err != nil {
+			return evicted, err
+		}
+		err = os.Remove(modelCacheEntryPath(entry.Key))
+		unlock()
+		if err != nil {
+			return evicted, err
+		}
+		for _, f := range entry.Files {
+			refs[f.Sha256]--
+			if refs[f.Sha256] == 0 {
+				_ = os.Remove(modelCacheBlobPath(f.Sha256))
+				total -= sizes[f.Sha256]
+			}
+		}
+		evicted = append(evicted, entry)
+	}
+	return evicted, nil
+}
+
+// ListModelCache returns the entries of the model cache, the least recently used first, without their files, and
+// the size of the files stored once
+func ListModelCache() ([]ModelCacheEntry, int64, error) {
+	unlock, err := lockModelCache("index", true)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer unlock()
+
+	entries, err := listModelCacheEntries()
+	if err != nil {
+		return nil, 0, err
+	}
+	list := []ModelCacheEntry{}
+	blobs := map[string]bool{}
+	var total int64
+	for _, entry := range entries {
+		for _, f := range entry.Files {
+			if !blobs[f.Sha256] {
+				blobs[f.Sha256] = true
+				total += f.Size
+			}
+		}
+		entry.Files = nil
+		list = append(list, *entry)
+	}
+	return list, total, nil
+}
+
+// EvictModelCache evicts an entry of the model cache, or every entry not in use when key is empty, and returns the
+// evicted entries
+func EvictModelCache(key string) ([]ModelCacheEntry, error) {
+	unlock, err := lockModelCache("index", true)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	keep := map[string]bool{}
+	if key != "" {
+		if _, err := os.Stat(modelCacheEntryPath(key)); err != nil {
+			return nil, ErrModelCacheEntryNotFound
+		}
+		entries, err := listModelCacheEntries()
+		if err != nil {
+			return nil, err
+		}
+		for _, entry := range entries {
+			keep[entry.Key] = entry.Key != key
+		}
+	}
+	evicted, err := evictModelCacheEntries(-1, keep)
+	if err == nil && key != "" && len(evicted) == 0 {
+		err = ErrModelCacheEntryInUse
+	}
+
+	list := []ModelCacheEntry{}
+	for _, entry := range evicted {
+		entry.Files = nil
+		list = append(list, *entry)
+	}
+	return list, err
+}
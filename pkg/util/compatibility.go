@@ -0,0 +1,87 @@
+package util
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/instill-ai/model-backend/pkg/datamodel"
+	"github.com/instill-ai/model-backend/pkg/triton/inferenceserver"
+
+	modelPB "github.com/instill-ai/protogen-go/model/model/v1alpha"
+)
+
+// tritonPlatformBackends are the backends serving the Triton platforms, the ensemble platform is built in
+var tritonPlatformBackends = map[string]string{
+	"onnxruntime_onnx":      "onnxruntime",
+	"pytorch_libtorch":      "pytorch",
+	"tensorrt_plan":         "tensorrt",
+	"tensorflow_graphdef":   "tensorflow",
+	"tensorflow_savedmodel": "tensorflow",
+}
+
+// GetTritonBackend returns the backend serving a Triton model, empty for an ensemble
+func GetTritonBackend(modelConfig *inferenceserver.ModelConfig) string {
+	if modelConfig.Backend != "" {
+		return modelConfig.Backend
+	}
+	return tritonPlatformBackends[modelConfig.Platform]
+}
+
+// CheckTritonCompatibility checks that the Triton server supports the Triton models of a model, their backends
+// against the installed ones, unchecked when none is given, and the extensions they are deployed with against those
+// of the server. The Triton models whose configuration is not in the model repository yet are skipped. All the
+// incompatibilities are reported at once.
+func CheckTritonCompatibility(modelRepository string, tritonModels []datamodel.TritonModel, task modelPB.Model_Task, hasDeployConfig bool, serverExtensions []string, backends []string) error {
+	installed := map[string]bool{}
+	for _, b := range backends {
+		installed[b] = true
+	}
+	supported := map[string]bool{}
+	for _, e := range serverExtensions {
+		supported[e] = true
+	}
+
+	// the models are loaded and unloaded explicitly, with their deploy configuration applied when there is one
+	required := map[string]string{"model_repository": "the models are loaded explicitly"}
+	if hasDeployConfig {
+		required["model_configuration"] = "the model has a deploy configuration"
+	}
+	if task == modelPB.Model_TASK_CLASSIFICATION {
+		required["classification"] = "the classification outputs are requested as classes"
+	}
+
+	problems := []string{}
+	for _, tm := range tritonModels {
+		modelConfig, err := ReadTritonModelConfig(modelRepository, tm.Name)
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return err
+		}
+		if backend := GetTritonBackend(modelConfig); backend != "" && len(installed) > 0 && !installed[backend] {
+			problems = append(problems, fmt.Sprintf("triton model %s needs the %s backend, which is not installed", tm.Name, backend))
+		}
+		if modelConfig.GetSequenceBatching() != nil {
+			required["sequence"] = fmt.Sprintf("triton model %s uses sequence batching", tm.Name)
+		}
+	}
+
+	extensions := make([]string, 0, len(required))
+	for extension := range required {
+		extensions = append(extensions, extension)
+	}
+	sort.Strings(extensions)
+	for _, extension := range extensions {
+		if !supported[extension] {
+			problems = append(problems, fmt.Sprintf("the server lacks the %s extension, %s", extension, required[extension]))
+		}
+	}
+
+	if len(problems) > 0 {
+		return fmt.Errorf("the triton server cannot serve the model: %s", strings.Join(problems, "; "))
+	}
+	return nil
+}
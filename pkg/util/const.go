@@ -80,5 +80,8 @@ const (
 	TEXT_GENERATION_SEED       = int64(0)
 )
 
+// MODEL_CACHE_DIR is the default root of the model artifact cache
 const MODEL_CACHE_DIR = "/.cache/models"
-const MODEL_CACHE_FILE = "cached_models.json"
+
+// TMP_DIR is where model archives, clones and credentials are staged before landing in the model store
+const TMP_DIR = "/tmp"
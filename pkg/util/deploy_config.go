@@ -0,0 +1,183 @@
+package util
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"google.golang.org/protobuf/encoding/prototext"
+	"gorm.io/datatypes"
+
+	"github.com/instill-ai/model-backend/pkg/datamodel"
+	"github.com/instill-ai/model-backend/pkg/triton/inferenceserver"
+
+	modelPB "github.com/instill-ai/protogen-go/model/model/v1alpha"
+)
+
+const tritonEnsemblePlatform = "ensemble"
+
+// ReadTritonModelConfig parses the config.pbtxt shipped with a Triton model
+func ReadTritonModelConfig(modelRepository string, tritonModelName string) (*inferenceserver.ModelConfig, error) {
+	b, err := os.ReadFile(filepath.Join(modelRepository, tritonModelName, "config.pbtxt"))
+	if err != nil {
+		return nil, err
+	}
+	// Unknown fields are an error on purpose, dropping them would silently alter the model once overridden
+	modelConfig := &inferenceserver.ModelConfig{}
+	if err := prototext.Unmarshal(b, modelConfig); err != nil {
+		return nil, fmt.Errorf("unable to parse the config.pbtxt of triton model %s: %w", tritonModelName, err)
+	}
+
+	return modelConfig, nil
+}
+
+// ValidateModelDeployConfig checks a deploy configuration against the shipped configurations of the Triton models
+// and the batch size limit of the model task
+func ValidateModelDeployConfig(modelRepository string, tritonModels []datamodel.TritonModel, task datamodel.ModelTask, deployConfig datamodel.ModelDeployConfiguration) error {
+	if ig := deployConfig.InstanceGroup; ig != nil {
+		if _, ok := inferenceserver.ModelInstanceGroup_Kind_value[ig.Kind]; !ok || ig.Kind == inferenceserver.ModelInstanceGroup_KIND_MODEL.String() {
+			return fmt.Errorf("instance group kind must be one of KIND_AUTO, KIND_CPU or KIND_GPU")
+		}
+		if ig.Count < 1 {
+			return fmt.Errorf("instance group count must be at least 1")
+		}
+		if len(ig.Gpus) > 0 && ig.Kind != inferenceserver.ModelInstanceGroup_KIND_GPU.String() {
+			return fmt.Errorf("instance group gpus can only be set with KIND_GPU")
+		}
+		for _, gpu := range ig.Gpus {
+			if gpu < 0 {
+				return fmt.Errorf("instance group gpus must not be negative")
+			}
+		}
+	}
+
+	if deployConfig.MaxBatchSize != nil {
+		if *deployConfig.MaxBatchSize < 1 {
+			return fmt.Errorf("max batch size must be at least 1")
+		}
+		if limit := GetSupportedBatchSize(task); limit > 0 && int(*deployConfig.MaxBatchSize) > limit {
+			return fmt.Errorf("max batch size must not exceed %d for this model task", limit)
+		}
+	}
+
+	if w := deployConfig.Warmup; w != nil && w.Sample != "" {
+		if _, err := getLocalPath(w.Sample); err != nil {
+			return fmt.Errorf("warm-up sample %s: %w", w.Sample, err)
+		}
+	}
+
+	for _, tm := range tritonModels {
+		modelConfig, err := ReadTritonModelConfig(modelRepository, tm.Name)
+		if err != nil {
+			return err
+		}
+		// Batching cannot be turned on for a model whose tensors have no batch dimension
+		if modelConfig.MaxBatchSize == 0 && (deployConfig.MaxBatchSize != nil || deployConfig.DynamicBatching != nil) {
+			return fmt.Errorf("triton model %s does not support batching", tm.Name)
+		}
+		maxBatchSize := modelConfig.MaxBatchSize
+		if deployConfig.MaxBatchSize != nil {
+			maxBatchSize = *deployConfig.MaxBatchSize
+		}
+		if db := deployConfig.DynamicBatching; db != nil && modelConfig.Platform != tritonEnsemblePlatform {
+			for _, size := range db.PreferredBatchSize {
+				if size < 1 || size > maxBatchSize {
+					return fmt.Errorf("preferred batch size %d of triton model %s must be between 1 and %d", size, tm.Name, maxBatchSize)
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// ApplyModelDeployConfig rewrites a shipped Triton model configuration with a deploy configuration,
+// ensemble models only take the max batch size
+func ApplyModelDeployConfig(modelConfig *inferenceserver.ModelConfig, deployConfig datamodel.ModelDeployConfiguration) {
+	if deployConfig.MaxBatchSize != nil && modelConfig.MaxBatchSize > 0 {
+		modelConfig.MaxBatchSize = *deployConfig.MaxBatchSize
+	}
+
+	if modelConfig.Platform == tritonEnsemblePlatform {
+		return
+	}
+
+	if ig := deployConfig.InstanceGroup; ig != nil {
+		modelConfig.InstanceGroup = []*inferenceserver.ModelInstanceGroup{{
+			Kind:  inferenceserver.ModelInstanceGroup_Kind(inferenceserver.ModelInstanceGroup_Kind_value[ig.Kind]),
+			Count: ig.Count,
+			Gpus:  ig.Gpus,
+		}}
+	}
+
+	if db := deployConfig.DynamicBatching; db != nil && modelConfig.MaxBatchSize > 0 {
+		modelConfig.SchedulingChoice = &inferenceserver.ModelConfig_DynamicBatching{
+			DynamicBatching: &inferenceserver.ModelDynamicBatching{
+				PreferredBatchSize:        db.PreferredBatchSize,
+				MaxQueueDelayMicroseconds: db.MaxQueueDelayMicroseconds,
+			},
+		}
+	}
+}
+
+// GetModelConfigOverride returns the configuration a Triton model has to be loaded with,
+// or nil when the model has no deploy configuration and the shipped config.pbtxt is used as is
+func GetModelConfigOverride(modelRepository string, tritonModelName string, deployConfig datatypes.JSON) (*inferenceserver.ModelConfig, error) {
+	if len(deployConfig) == 0 || string(deployConfig) == "null" {
+		return nil, nil
+	}
+
+	var cfg datamodel.ModelDeployConfiguration
+	if err := json.Unmarshal(deployConfig, &cfg); err != nil {
+		return nil, err
+	}
+	modelConfig, err := ReadTritonModelConfig(modelRepository, tritonModelName)
+	if err != nil {
+		return nil, err
+	}
+	ApplyModelDeployConfig(modelConfig, cfg)
+
+	return modelConfig, nil
+}
+
+// warmupSamples are the names of the samples bundled for the model tasks, the models of the other tasks are only
+// warmed up with a sample of their own
+var warmupSamples = map[modelPB.Model_Task]string{
+	modelPB.Model_TASK_CLASSIFICATION:        "image.jpg",
+	modelPB.Model_TASK_DETECTION:             "image.jpg",
+	modelPB.Model_TASK_KEYPOINT:              "image.jpg",
+	modelPB.Model_TASK_OCR:                   "image.jpg",
+	modelPB.Model_TASK_INSTANCE_SEGMENTATION: "image.jpg",
+	modelPB.Model_TASK_SEMANTIC_SEGMENTATION: "image.jpg",
+	modelPB.Model_TASK_TEXT_TO_IMAGE:         "prompt.txt",
+	modelPB.Model_TASK_TEXT_GENERATION:       "prompt.txt",
+}
+
+// getLocalPath cleans a path that must stay within the folder it is relative to
+func getLocalPath(path string) (string, error) {
+	cleaned := filepath.Clean(path)
+	if filepath.IsAbs(cleaned) || cleaned == ".." || strings.HasPrefix(cleaned, "../") {
+		return "", fmt.Errorf("the path must be relative to the model folder")
+	}
+	return cleaned, nil
+}
+
+// ReadWarmupSample returns the sample input a model is warmed up with, the sample of its deploy configuration in the
+// folder of its ensemble Triton model or else the sample bundled for its task in the sample folder. It returns nil
+// when there is no sample for the task.
+func ReadWarmupSample(modelRepository string, ensembleModelName string, sampleDir string, task modelPB.Model_Task, warmup *datamodel.ModelDeployWarmup) ([]byte, error) {
+	if warmup != nil && warmup.Sample != "" {
+		path, err := getLocalPath(warmup.Sample)
+		if err != nil {
+			return nil, fmt.Errorf("warm-up sample %s: %w", warmup.Sample, err)
+		}
+		return os.ReadFile(filepath.Join(modelRepository, ensembleModelName, path))
+	}
+	name, ok := warmupSamples[task]
+	if !ok {
+		return nil, nil
+	}
+	return os.ReadFile(filepath.Join(sampleDir, name))
+}
@@ -0,0 +1,205 @@
+package util
+
+import (
+	"context"
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/instill-ai/model-backend/config"
+)
+
+const (
+	downloadMaxAttempts = 5
+	downloadRetryDelay  = 2 * time.Second
+	downloadTimeout     = 2 * time.Hour
+)
+
+// downloadError tells whether a failed download is worth resuming
+type downloadError struct {
+	err       error
+	retryable bool
+}
+
+func (e *downloadError) Error() string {
+	return e.err.Error()
+}
+
+func (e *downloadError) Unwrap() error {
+	return e.err
+}
+
+// ParseChecksum parses a checksum given as algorithm:hex, a bare hex digest is a SHA-256 one,
+// and returns the hash to compute along with the expected digest
+func ParseChecksum(checksum string) (hash.Hash, string, error) {
+	algorithm, digest, found := strings.Cut(checksum, ":")
+	if !found {
+		algorithm, digest = "sha256", checksum
+	}
+	digest = strings.ToLower(digest)
+	if _, err := hex.DecodeString(digest); err != nil {
+		return nil, "", fmt.Errorf("checksum %q is not hex encoded", checksum)
+	}
+	switch strings.ToLower(algorithm) {
+	case "sha256":
+		if len(digest) == sha256.Size*2 {
+			return sha256.New(), digest, nil
+		}
+	case "md5":
+		if len(digest) == md5.Size*2 {
+			return md5.New(), digest, nil
+		}
+	default:
+		return nil, "", fmt.Errorf("checksum algorithm %q is not supported, use sha256 or md5", algorithm)
+	}
+	return nil, "", fmt.Errorf("checksum %q has an invalid length", checksum)
+}
+
+// GetURLCredentialHeaders returns the HTTP headers of an archive credential configured on the server side
+func GetURLCredentialHeaders(credentialRef string) (map[string]string, error) {
+	if credentialRef == "" {
+		return nil, nil
+	}
+	credential, ok := config.Config.Archive.Credentials[credentialRef]
+	if !ok {
+		return nil, fmt.Errorf("archive credential %q is not configured", credentialRef)
+	}
+	return credential.Headers, nil
+}
+
+// CheckURL checks that a URL can be downloaded by requesting its first byte only
+func CheckURL(ctx context.Context, url string, headers map[string]string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+	req.Header.Set("Range", "bytes=0-0")
+
+	resp, err := (&http.Client{Timeout: time.Minute}).Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusPartialContent {
+		return fmt.Errorf("download of %s failed with status %s", url, resp.Status)
+	}
+	return nil
+}
+
+// DownloadFile downloads a URL into dst, resuming from the bytes already in dst when the server supports ranges,
+// and verifies the checksum of the complete file when one is given. A partial dst is kept on failure so that the
+// next attempt resumes it, a dst with a mismatching checksum is removed. The download stops when ctx is done.
+func DownloadFile(ctx context.Context, url string, dst string, headers map[string]string, checksum string) error {
+	return downloadFile(ctx, url, dst, headers, checksum, nil)
+}
+
+// downloadFile is DownloadFile with a progress callback, if any, receiving the size of dst as it is written
+func downloadFile(ctx context.Context, url string, dst string, headers map[string]string, checksum string, progress func(written int64)) error {
+	var h hash.Hash
+	var digest string
+	if checksum != "" {
+		var err error
+		if h, digest, err = ParseChecksum(checksum); err != nil {
+			return err
+		}
+	}
+
+	client := &http.Client{Timeout: downloadTimeout}
+	for attempt := 1; ; attempt++ {
+		err := downloadRange(ctx, client, url, dst, headers, progress)
+		if err == nil {
+			break
+		}
+		if de, ok := err.(*downloadError); !ok || !de.retryable || attempt >= downloadMaxAttempts || ctx.Err() != nil {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(time.Duration(attempt) * downloadRetryDelay):
+		}
+	}
+
+	if h == nil {
+		return nil
+	}
+	f, err := os.Open(dst)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	if _, err := io.Copy(h, f); err != nil {
+		return err
+	}
+	if got := hex.EncodeToString(h.Sum(nil)); got != digest {
+		_ = os.Remove(dst)
+		return fmt.Errorf("checksum mismatch for %s: expected %s, got %s", url, digest, got)
+	}
+
+	return nil
+}
+
+func downloadRange(ctx context.Context, client *http.Client, url string, dst string, headers map[string]string, progress func(written int64)) error {
+	var offset int64
+	if fi, err := os.Stat(dst); err == nil {
+		offset = fi.Size()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+	if offset > 0 {
+		req.Header.Set("Range", fmt.Sprintf("bytes=%d-", offset))
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return &downloadError{err: err, retryable: true}
+	}
+	defer resp.Body.Close()
+
+	flag := os.O_WRONLY | os.O_CREATE
+	switch resp.StatusCode {
+	case http.StatusPartialContent:
+		flag |= os.O_APPEND
+	case http.StatusOK: // ranges not supported, start over
+		flag |= os.O_TRUNC
+	case http.StatusRequestedRangeNotSatisfiable: // already complete
+		return nil
+	default:
+		retryable := resp.StatusCode >= 500 || resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode == http.StatusRequestTimeout
+		return &downloadError{err: fmt.Errorf("download of %s failed with status %s", url, resp.Status), retryable: retryable}
+	}
+
+	f, err := os.OpenFile(dst, flag, 0o600)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	var w io.Writer = f
+	if progress != nil {
+		if resp.StatusCode == http.StatusOK {
+			offset = 0
+		}
+		w = &progressWriter{w: f, written: offset, progress: progress}
+	}
+	if _, err := io.Copy(w, resp.Body); err != nil {
+		return &downloadError{err: err, retryable: true}
+	}
+
+	return nil
+}
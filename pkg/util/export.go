@@ -0,0 +1,256 @@
+package util
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/instill-ai/model-backend/pkg/datamodel"
+)
+
+// ModelArchiveFormats are the archive formats a model can be exported to, with their content type
+var ModelArchiveFormats = map[string]string{
+	"zip":    "application/zip",
+	"tar.gz": "application/gzip",
+}
+
+// ModelExportManifest is written as manifest.json at the top of an exported model archive
+type ModelExportManifest struct {
+	ID              string      `json:"id"`
+	ModelDefinition string      `json:"model_definition"`
+	Description     string      `json:"description"`
+	Task            string      `json:"task"`
+	Visibility      string      `json:"visibility"`
+	Configuration   interface{} `json:"configuration"`
+	TritonModels    []string    `json:"triton_models"`
+	ExportTime      string      `json:"export_time"`
+}
+
+// exportedConfigurationFields are the fields of the model configurations written into the manifests, the credentials
+// and the references to the credentials of the server are left out
+var exportedConfigurationFields = map[string]bool{
+	"repository":     true,
+	"tag":            true,
+	"html_url":       true,
+	"url":            true,
+	"repo_id":        true,
+	"revision":       true,
+	"commit":         true,
+	"allow_patterns": true,
+	"pipeline":       true,
+	"endpoint":       true,
+	"bucket":         true,
+	"prefix":         true,
+	"region":         true,
+	"checksum":       true,
+	"content":        true,
+}
+
+// GetExportedModelConfiguration returns the fields of a model configuration that can be exported. The user info and
+// the query of the URLs, which may hold tokens, are removed as well.
+func GetExportedModelConfiguration(configuration []byte) map[string]interface{} {
+	fields := map[string]interface{}{}
+	_ = json.Unmarshal(configuration, &fields)
+	exported := map[string]interface{}{}
+	for k, v := range fields {
+		if !exportedConfigurationFields[k] {
+			continue
+		}
+		if s, ok := v.(string); ok && (k == "url" || k == "endpoint" || k == "html_url") {
+			if u, err := url.Parse(s); err == nil {
+				u.User = nil
+				u.RawQuery = ""
+				u.Fragment = ""
+				v = u.String()
+			}
+		}
+		exported[k] = v
+	}
+	return exported
+}
+
+// modelArchiveWriter adds the folders and the files of an exported model to an archive
+type modelArchiveWriter interface {
+	addDir(name string, mode os.FileMode) error
+	addFile(name string, mode os.FileMode, size int64, r io.Reader) error
+	Close() error
+}
+
+type zipModelArchiveWriter struct {
+	w *zip.Writer
+}
+
+func (z *zipModelArchiveWriter) addDir(name string, mode os.FileMode) error {
+	header := &zip.FileHeader{Name: name + "/", Method: zip.Store}
+	header.SetMode(mode | os.ModeDir)
+	_, err := z.w.CreateHeader(header)
+	return err
+}
+
+func (z *zipModelArchiveWriter) addFile(name string, mode os.FileMode, size int64, r io.Reader) error {
+	header := &zip.FileHeader{Name: name, Method: zip.Deflate}
+	header.SetMode(mode)
+	f, err := z.w.CreateHeader(header)
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(f, r)
+	return err
+}
+
+func (z *zipModelArchiveWriter) Close() error {
+	return z.w.Close()
+}
+
+type tarGzModelArchiveWriter struct {
+	gw *gzip.Writer
+	tw *tar.Writer
+}
+
+func (t *tarGzModelArchiveWriter) addDir(name string, mode os.FileMode) error {
+	return t.tw.WriteHeader(&tar.Header{Typeflag: tar.TypeDir, Name: name + "/", Mode: int64(mode.Perm())})
+}
+
+func (t *tarGzModelArchiveWriter) addFile(name string, mode os.FileMode, size int64, r io.Reader) error {
+	if err := t.tw.WriteHeader(&tar.Header{Typeflag: tar.TypeReg, Name: name, Mode: int64(mode.Perm()), Size: size}); err != nil {
+		return err
+	}
+	_, err := io.Copy(t.tw, r)
+	return err
+}
+
+func (t *tarGzModelArchiveWriter) Close() error {
+	if err := t.tw.Close(); err != nil {
+		return err
+	}
+	return t.gw.Close()
+}
+
+func newModelArchiveWriter(w io.Writer, format string) (modelArchiveWriter, error) {
+	switch format {
+	case "zip":
+		return &zipModelArchiveWriter{w: zip.NewWriter(w)}, nil
+	case "tar.gz":
+		gw := gzip.NewWriter(w)
+		return &tarGzModelArchiveWriter{gw: gw, tw: tar.NewWriter(gw)}, nil
+	default:
+		return nil, fmt.Errorf("unsupported archive format %q, only zip and tar.gz are supported", format)
+	}
+}
+
+// GetModelPackageEntries returns the entries of a model in the model repository, i.e. its Triton models and top
+// level files such as README.md, by their name in the model package
+func GetModelPackageEntries(modelRepository string, owner string, model *datamodel.Model) (map[string]string, error) {
+	var modelConfiguration datamodel.GitHubModelConfiguration
+	_ = json.Unmarshal(model.Configuration, &modelConfiguration)
+	prefix := fmt.Sprintf("%s#%s#", owner, model.ID)
+	suffix := fmt.Sprintf("#%s", modelConfiguration.Tag)
+	paths, err := filepath.Glob(filepath.Join(modelRepository, prefix+"*"+suffix))
+	if err != nil {
+		return nil, err
+	}
+
+	entries := map[string]string{}
+	for _, p := range paths {
+		name, err := filepath.Rel(modelRepository, p)
+		if err != nil {
+			return nil, err
+		}
+		entries[strings.TrimSuffix(strings.TrimPrefix(name, prefix), suffix)] = name
+	}
+	return entries, nil
+}
+
+// ExportModelArchive writes the model package of a model as a zip or tar.gz archive: the owner#id#folder#tag names of
+// the model repository are restored to the folder names, also in the Triton configurations and Python models, and
+// the manifest is added as manifest.json
+func ExportModelArchive(w io.Writer, format string, modelRepository string, owner string, model *datamodel.Model, manifest *ModelExportManifest) error {
+	entries, err := GetModelPackageEntries(modelRepository, owner, model)
+	if err != nil {
+		return err
+	}
+	if len(entries) == 0 {
+		return fmt.Errorf("model %s has no file in the model repository", model.ID)
+	}
+	aw, err := newModelArchiveWriter(w, format)
+	if err != nil {
+		return err
+	}
+
+	// longer names first so that a Triton model name is not replaced inside of another one
+	names := make([]string, 0, len(entries))
+	for name := range entries {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool { return len(entries[names[i]]) > len(entries[names[j]]) })
+	oldnew := []string{}
+	for _, name := range names {
+		oldnew = append(oldnew, entries[name], name)
+	}
+	restorer := strings.NewReplacer(oldnew...)
+
+	sort.Strings(names)
+	manifest.TritonModels = []string{}
+	for _, name := range names {
+		// the manifest of a model created from an exported archive is written again
+		if name == "manifest.json" {
+			continue
+		}
+		root := filepath.Join(modelRepository, entries[name])
+		if info, err := os.Stat(root); err == nil && info.IsDir() {
+			manifest.TritonModels = append(manifest.TritonModels, name)
+		}
+		err := filepath.Walk(root, func(p string, info os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
+			rel, err := filepath.Rel(root, p)
+			if err != nil {
+				return err
+			}
+			entryName := path.Join(name, filepath.ToSlash(rel))
+			switch {
+			case info.IsDir():
+				return aw.addDir(entryName, info.Mode().Perm())
+			case !info.Mode().IsRegular():
+				return nil
+			case strings.HasSuffix(p, ".pbtxt") || strings.HasSuffix(p, ".py"):
+				b, err := os.ReadFile(p)
+				if err != nil {
+					return err
+				}
+				content := restorer.Replace(string(b))
+				return aw.addFile(entryName, info.Mode().Perm(), int64(len(content)), strings.NewReader(content))
+			default:
+				f, err := os.Open(p)
+				if err != nil {
+					return err
+				}
+				defer f.Close()
+				return aw.addFile(entryName, info.Mode().Perm(), info.Size(), f)
+			}
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	b, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := aw.addFile("manifest.json", 0644, int64(len(b)), strings.NewReader(string(b))); err != nil {
+		return err
+	}
+
+	return aw.Close()
+}
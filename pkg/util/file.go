@@ -4,6 +4,7 @@ import (
 	"archive/zip"
 	"database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
@@ -13,6 +14,8 @@ import (
 	"regexp"
 	"strconv"
 	"strings"
+	"syscall"
+	"time"
 
 	"github.com/gofrs/uuid"
 	"github.com/instill-ai/model-backend/internal/resource"
@@ -27,6 +30,36 @@ type FileMeta struct {
 	fInfo os.FileInfo
 }
 
+// errFileLocked is returned when a lock file is locked by another process or goroutine
+var errFileLocked = errors.New("file is locked")
+
+// lockFile takes an exclusive lock on a file, shared by the processes of a host, which is released when the returned
+// function is called or the process exits. It fails with errFileLocked when the lock is taken and wait is not set.
+func lockFile(p string, wait bool) (func(), error) {
+	if err := os.MkdirAll(filepath.Dir(p), os.ModePerm); err != nil {
+		return nil, err
+	}
+	f, err := os.OpenFile(p, os.O_CREATE|os.O_RDWR, 0644)
+	if err != nil {
+		return nil, err
+	}
+	how := syscall.LOCK_EX
+	if !wait {
+		how |= syscall.LOCK_NB
+	}
+	if err := syscall.Flock(int(f.Fd()), how); err != nil {
+		f.Close()
+		if errors.Is(err, syscall.EWOULDBLOCK) {
+			return nil, errFileLocked
+		}
+		return nil, err
+	}
+	return func() {
+		_ = syscall.Flock(int(f.Fd()), syscall.LOCK_UN)
+		f.Close()
+	}, nil
+}
+
 func isEnsembleConfig(configPath string) bool {
 	fileData, _ := os.ReadFile(configPath)
 	fileString := string(fileData)
@@ -58,7 +91,6 @@ func WriteToFp(fp *os.File, data []byte) error {
 	}
 }
 
-// TODO: need to clean up this function
 func Unzip(fPath string, dstDir string, owner string, uploadedModel *datamodel.Model) (string, string, error) {
 	archive, err := zip.OpenReader(fPath)
 	if err != nil {
@@ -66,144 +98,27 @@ func Unzip(fPath string, dstDir string, owner string, uploadedModel *datamodel.M
 		return "", "", err
 	}
 	defer archive.Close()
-	var readmeFilePath string
-	var createdTModels []datamodel.TritonModel
-	var currentNewModelName string
-	var currentOldModelName string
-	var ensembleFilePath string
-	var newModelNameMap = make(map[string]string)
-	var configFiles []string
-	for _, f := range archive.File {
-		if strings.Contains(f.Name, "__MACOSX") || strings.Contains(f.Name, "__pycache__") { // ignore temp directory in macos
-			continue
-		}
-		fPath := filepath.Join(dstDir, f.Name)
-		fmt.Println("unzipping file ", fPath)
 
-		if !strings.HasPrefix(fPath, filepath.Clean(dstDir)+string(os.PathSeparator)) {
-			fmt.Println("invalid file path")
-			return "", "", fmt.Errorf("invalid file path")
-		}
+	e := newModelArchiveExtractor(dstDir, owner, uploadedModel)
+	for _, f := range archive.File {
 		if f.FileInfo().IsDir() {
-			dirName := f.Name
-			if string(dirName[len(dirName)-1]) == "/" {
-				dirName = dirName[:len(dirName)-1]
-			}
-			if !strings.Contains(dirName, "/") { // top directory model
-				currentOldModelName = dirName
-				dirName = fmt.Sprintf("%v#%v#%v#%v", owner, uploadedModel.ID, dirName, "latest")
-				currentNewModelName = dirName
-				newModelNameMap[currentOldModelName] = currentNewModelName
-			} else { // version folder
-				dirName = strings.Replace(dirName, currentOldModelName, currentNewModelName, 1)
-				patternVersionFolder := fmt.Sprintf("^%v/[0-9]+$", currentNewModelName)
-				match, _ := regexp.MatchString(patternVersionFolder, dirName)
-				if match {
-					elems := strings.Split(dirName, "/")
-					sVersion := elems[len(elems)-1]
-					iVersion, err := strconv.ParseInt(sVersion, 10, 32)
-					if err == nil {
-						createdTModels = append(createdTModels, datamodel.TritonModel{
-							Name:    currentNewModelName, // Triton model name
-							State:   datamodel.ModelState(modelPB.Model_STATE_OFFLINE),
-							Version: int(iVersion),
-						})
-					}
-				}
-			}
-			fPath := filepath.Join(dstDir, dirName)
-			if err := ValidateFilePath(fPath); err != nil {
+			if err := e.addDir(f.Name); err != nil {
 				return "", "", err
 			}
-			err = os.MkdirAll(fPath, os.ModePerm)
-			if err != nil {
-				return "", "", err
-			}
-			continue
-		}
-
-		// Update triton folder into format {model_name}#{task_name}#{task_version}
-		subStrs := strings.Split(f.Name, "/")
-		if len(subStrs) < 1 {
 			continue
 		}
-		// Triton modelname is folder name
-		oldModelName := subStrs[0]
-		subStrs[0] = fmt.Sprintf("%v#%v#%v#%v", owner, uploadedModel.ID, subStrs[0], "latest")
-		newModelName := subStrs[0]
-		fPath = filepath.Join(dstDir, strings.Join(subStrs, "/"))
-		if strings.Contains(f.Name, "README.md") {
-			readmeFilePath = fPath
-		}
-		if err := ValidateFilePath(fPath); err != nil {
-			return "", "", err
-		}
-		// ensure the parent folder existed
-		if _, err := os.Stat(filepath.Dir(fPath)); os.IsNotExist(err) {
-			if err := os.MkdirAll(filepath.Dir(fPath), os.ModePerm); err != nil {
-				return "", "", err
-			}
-		}
-
-		dstFile, err := os.OpenFile(fPath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, f.Mode())
-		if err != nil {
-			return "", "", err
-		}
 		fileInArchive, err := f.Open()
 		if err != nil {
 			return "", "", err
 		}
-		if _, err := io.Copy(dstFile, fileInArchive); err != nil {
-			return "", "", err
-		}
-
-		dstFile.Close()
+		err = e.addFile(f.Name, f.Mode(), fileInArchive)
 		fileInArchive.Close()
-		// Update ModelName in config.pbtxt
-		fileExtension := filepath.Ext(fPath)
-		if fileExtension == ".pbtxt" {
-			configFiles = append(configFiles, fPath)
-			if isEnsembleConfig(fPath) {
-				ensembleFilePath = fPath
-			}
-			err = UpdateConfigModelName(fPath, oldModelName, newModelName)
-			if err != nil {
-				return "", "", err
-			}
+		if err != nil {
+			return "", "", err
 		}
 	}
-	if ensembleFilePath == "" {
-		for _, filePath := range configFiles {
-			if couldBeEnsembleConfig(filePath) {
-				ensembleFilePath = filePath
-				break
-			}
-		}
 
-		for oldModelName, newModelName := range newModelNameMap {
-			err = UpdateModelName(filepath.Dir(ensembleFilePath)+"/1/model.py", oldModelName, newModelName) // TODO: replace in all files.
-			if err != nil {
-				return "", "", err
-			}
-		}
-	}
-	// Update ModelName in ensemble model config file
-	if ensembleFilePath != "" {
-		for oldModelName, newModelName := range newModelNameMap {
-			err = UpdateConfigModelName(ensembleFilePath, oldModelName, newModelName)
-			if err != nil {
-				return "", "", err
-			}
-		}
-		for i := 0; i < len(createdTModels); i++ {
-			if strings.Contains(ensembleFilePath, createdTModels[i].Name) {
-				createdTModels[i].Platform = "ensemble"
-				break
-			}
-		}
-	}
-	uploadedModel.TritonModels = createdTModels
-	return readmeFilePath, ensembleFilePath, nil
+	return e.finish()
 }
 
 // modelDir and dstDir are absolute path
@@ -414,3 +329,53 @@ func GetJSON(url string, result interface{}) error {
 
 	return nil
 }
+
+// tritonModelNamePattern matches the names this backend gives to the Triton models, owner#modelID#folder#tag with
+// the owner permalink, so that the models of other backends sharing the Triton server are told apart
+var tritonModelNamePattern = regexp.MustCompile(`^(users|orgs)/[^/#]+#[^/#]+#[^/#]+#[^/#]+$`)
+
+// IsTritonModelName tells whether a Triton model or model directory is named after the scheme of this backend
+func IsTritonModelName(name string) bool {
+	return tritonModelNamePattern.MatchString(filepath.ToSlash(name))
+}
+
+// FindOrphanedModelDirs returns the paths, relative to the model repository, of the model directories
+// that do not belong to any of the given Triton models. Model directories are named after the Triton
+// model they hold, i.e. owner#modelID#folder#tag, where the owner permalink adds one directory level.
+// Directories modified within the grace period are skipped since they may belong to a model that is
+// still being created.
+func FindOrphanedModelDirs(modelRepository string, tritonModelNames map[string]bool, gracePeriod time.Duration) ([]string, error) {
+	orphans := []string{}
+	err := filepath.WalkDir(modelRepository, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() || path == modelRepository {
+			return nil
+		}
+		if !strings.Contains(d.Name(), "#") {
+			return nil
+		}
+
+		name, err := filepath.Rel(modelRepository, path)
+		if err != nil {
+			return err
+		}
+		if !IsTritonModelName(name) {
+			return filepath.SkipDir
+		}
+		if !tritonModelNames[name] {
+			if info, err := d.Info(); err == nil && time.Since(info.ModTime()) >= gracePeriod {
+				orphans = append(orphans, name)
+			}
+		}
+
+		// the content of a model directory is not made of model directories
+		return filepath.SkipDir
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return orphans, nil
+}
@@ -0,0 +1,118 @@
+package util
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/gofrs/uuid"
+)
+
+// Kinds of garbage left behind in the model store or the staging directory
+const (
+	GarbageKindModelDir = "model-dir"
+	GarbageKindReadme   = "readme"
+	GarbageKindTmp      = "tmp"
+)
+
+// Garbage is a file or a directory that no longer belongs to any model
+type Garbage struct {
+	Path  string `json:"path"`
+	Kind  string `json:"kind"`
+	Size  int64  `json:"size"`
+	Error string `json:"error,omitempty"`
+}
+
+// FindOrphanedReadmes returns the paths, relative to the model repository, of the README files
+// named owner#modelID#README.md[#tag] whose owner#modelID prefix is not in the given models
+func FindOrphanedReadmes(modelRepository string, models map[string]bool, gracePeriod time.Duration) ([]string, error) {
+	orphans := []string{}
+	err := filepath.WalkDir(modelRepository, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			// README files never live inside a model directory
+			if path != modelRepository && strings.Contains(d.Name(), "#") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		name, err := filepath.Rel(modelRepository, path)
+		if err != nil {
+			return err
+		}
+		idx := strings.Index(name, "#README.md")
+		if idx < 0 || models[name[:idx]] {
+			return nil
+		}
+		if info, err := d.Info(); err == nil && time.Since(info.ModTime()) >= gracePeriod {
+			orphans = append(orphans, name)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return orphans, nil
+}
+
+// FindStaleTmpEntries returns the names of the staged archives, clones and credential files,
+// all named after a random UUID, that are older than the grace period
+func FindStaleTmpEntries(tmpDir string, gracePeriod time.Duration) ([]string, error) {
+	entries, err := os.ReadDir(tmpDir)
+	if err != nil {
+		return nil, err
+	}
+
+	stale := []string{}
+	for _, entry := range entries {
+		if _, err := uuid.FromString(strings.TrimSuffix(entry.Name(), ".zip")); err != nil {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil || time.Since(info.ModTime()) < gracePeriod {
+			continue
+		}
+		stale = append(stale, entry.Name())
+	}
+
+	return stale, nil
+}
+
+// DiskUsage returns the total size in bytes of a file or a directory tree
+func DiskUsage(path string) (int64, error) {
+	var size int64
+	err := filepath.WalkDir(path, func(_ string, d os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		size += info.Size()
+		return nil
+	})
+	return size, err
+}
+
+// RemoveUnder removes a file or a directory tree given by its path relative to root,
+// refusing any path that would escape root
+func RemoveUnder(root string, name string) error {
+	if err := ValidateFilePath(name); err != nil {
+		return err
+	}
+	target := filepath.Join(root, name)
+	if !strings.HasPrefix(target, filepath.Clean(root)+string(os.PathSeparator)) {
+		return fmt.Errorf("path %s is outside of %s", name, root)
+	}
+	return os.RemoveAll(target)
+}
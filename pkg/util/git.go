@@ -0,0 +1,379 @@
+package util
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	gitconfig "github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/transport"
+	githttp "github.com/go-git/go-git/v5/plumbing/transport/http"
+	gitssh "github.com/go-git/go-git/v5/plumbing/transport/ssh"
+	"github.com/go-git/go-git/v5/storage/memory"
+
+	"github.com/instill-ai/model-backend/config"
+)
+
+const (
+	gitDefaultUser     = "git"
+	gitLFSPointerLimit = 1024
+	gitLFSMediaType    = "application/vnd.git-lfs+json"
+	gitLFSTimeout      = 5 * time.Minute
+)
+
+type gitLFSPointer struct {
+	Oid  string `json:"oid"`
+	Size int64  `json:"size"`
+}
+
+type gitLFSBatchRequest struct {
+	Operation string          `json:"operation"`
+	Transfers []string        `json:"transfers"`
+	Objects   []gitLFSPointer `json:"objects"`
+}
+
+type gitLFSBatchResponse struct {
+	Objects []struct {
+		gitLFSPointer
+		Actions struct {
+			Download *struct {
+				Href   string            `json:"href"`
+				Header map[string]string `json:"header"`
+			} `json:"download"`
+		} `json:"actions"`
+		Error *struct {
+			Code    int    `json:"code"`
+			Message string `json:"message"`
+		} `json:"error"`
+	} `json:"objects"`
+}
+
+// GetGitCredential returns a Git credential configured on the server side
+func GetGitCredential(credentialRef string) (*config.GitCredentialConfig, error) {
+	if credentialRef == "" {
+		return nil, nil
+	}
+	credential, ok := config.Config.Git.Credentials[credentialRef]
+	if !ok {
+		return nil, fmt.Errorf("git credential %q is not configured", credentialRef)
+	}
+	return &credential, nil
+}
+
+// getGitAuth returns the authentication of a remote, a token for HTTP(S) remotes and a deploy key for SSH remotes
+func getGitAuth(endpoint *transport.Endpoint, credentialRef string) (transport.AuthMethod, error) {
+	credential, err := GetGitCredential(credentialRef)
+	if err != nil || credential == nil {
+		return nil, err
+	}
+
+	user := credential.Username
+	if user == "" {
+		user = gitDefaultUser
+	}
+	switch endpoint.Protocol {
+	case "ssh":
+		if credential.SSHKey == "" {
+			return nil, fmt.Errorf("git credential %q has no ssh key for the ssh remote %s", credentialRef, endpoint.Host)
+		}
+		auth, err := gitssh.NewPublicKeys(user, []byte(credential.SSHKey), credential.SSHKeyPassphrase)
+		if err != nil {
+			return nil, fmt.Errorf("git credential %q has an invalid ssh key: %w", credentialRef, err)
+		}
+		if credential.KnownHostsFile != "" {
+			if auth.HostKeyCallback, err = gitssh.NewKnownHostsCallback(credential.KnownHostsFile); err != nil {
+				return nil, err
+			}
+		}
+		return auth, nil
+	case "http", "https":
+		if credential.Token == "" {
+			return nil, fmt.Errorf("git credential %q has no token for the http remote %s", credentialRef, endpoint.Host)
+		}
+		return &githttp.BasicAuth{Username: user, Password: credential.Token}, nil
+	default:
+		return nil, fmt.Errorf("git credentials are not supported for %s remotes", endpoint.Protocol)
+	}
+}
+
+// getGitEndpoint parses the URL of a Git remote, only HTTP(S) and SSH remotes are allowed so that the repositories
+// on the filesystem of the server cannot be imported
+func getGitEndpoint(url string) (*transport.Endpoint, error) {
+	endpoint, err := transport.NewEndpoint(url)
+	if err != nil {
+		return nil, fmt.Errorf("invalid git url %q: %w", url, err)
+	}
+	switch endpoint.Protocol {
+	case "http", "https", "ssh":
+		return endpoint, nil
+	default:
+		return nil, fmt.Errorf("invalid git url %q: only http, https and ssh remotes are supported", url)
+	}
+}
+
+// ValidateGitURL checks that a Git remote is an HTTP(S) or SSH URL
+func ValidateGitURL(url string) error {
+	_, err := getGitEndpoint(url)
+	return err
+}
+
+// GitClone shallow clones the tag or the branch of a Git remote, an HTTP(S) or SSH URL, into dir. The Git LFS objects
+// are only downloaded when isWithLargeFile is set, the pointer files are kept otherwise. The progress callback, if any,
+// receives the bytes of the Git LFS objects downloaded. The clone is interrupted when ctx is done.
+func GitClone(ctx context.Context, dir string, url string, tag string, credentialRef string, isWithLargeFile bool, progress ProgressFunc) error {
+	endpoint, err := getGitEndpoint(url)
+	if err != nil {
+		return err
+	}
+	auth, err := getGitAuth(endpoint, credentialRef)
+	if err != nil {
+		return err
+	}
+
+	cloneOptions := &git.CloneOptions{
+		URL:          url,
+		Auth:         auth,
+		SingleBranch: true,
+		Depth:        1,
+		Tags:         git.NoTags,
+	}
+	if tag != "" {
+		cloneOptions.ReferenceName = plumbing.NewTagReferenceName(tag)
+	}
+	_, err = git.PlainCloneContext(ctx, dir, false, cloneOptions)
+	if tag != "" && errors.Is(err, git.NoMatchingRefSpecError{}) { // like git clone -b, the tag may be a branch
+		_ = os.RemoveAll(dir)
+		cloneOptions.ReferenceName = plumbing.NewBranchReferenceName(tag)
+		_, err = git.PlainCloneContext(ctx, dir, false, cloneOptions)
+	}
+	if err != nil {
+		return fmt.Errorf("git clone %s@%s failed: %w", endpoint.Host+"/"+strings.TrimPrefix(endpoint.Path, "/"), tag, err)
+	}
+
+	if !isWithLargeFile {
+		return nil
+	}
+	pointers, err := findGitLFSPointers(dir)
+	if err != nil || len(pointers) == 0 {
+		return err
+	}
+	if endpoint.Protocol != "http" && endpoint.Protocol != "https" {
+		return fmt.Errorf("git lfs objects can only be downloaded from http remotes, %s is a %s remote", endpoint.Host, endpoint.Protocol)
+	}
+	var basicAuth *githttp.BasicAuth
+	if auth != nil {
+		basicAuth = auth.(*githttp.BasicAuth)
+	}
+
+	return fetchGitLFSObjects(ctx, getGitLFSURL(url), basicAuth, pointers, progress)
+}
+
+// ResolveGitRevision resolves the tag, or the branch, of a Git remote to its commit
+func ResolveGitRevision(ctx context.Context, url string, tag string, credentialRef string) (string, error) {
+	endpoint, err := getGitEndpoint(url)
+	if err != nil {
+		return "", err
+	}
+	auth, err := getGitAuth(endpoint, credentialRef)
+	if err != nil {
+		return "", err
+	}
+	remote := git.NewRemote(memory.NewStorage(), &gitconfig.RemoteConfig{Name: git.DefaultRemoteName, URLs: []string{url}})
+	refs, err := remote.ListContext(ctx, &git.ListOptions{Auth: auth, PeelingOption: git.AppendPeeled})
+	if err != nil {
+		return "", fmt.Errorf("git ls-remote %s failed: %w", endpoint.Host+"/"+strings.TrimPrefix(endpoint.Path, "/"), err)
+	}
+
+	names := []plumbing.ReferenceName{plumbing.NewTagReferenceName(tag), plumbing.NewBranchReferenceName(tag)}
+	if tag == "" {
+		names = []plumbing.ReferenceName{plumbing.HEAD}
+	}
+	for _, name := range names {
+		// an annotated tag is resolved to its commit by its peeled reference
+		for _, peeled := range []bool{true, false} {
+			for _, ref := range refs {
+				refName := ref.Name().String()
+				if peeled != strings.HasSuffix(refName, "^{}") || plumbing.ReferenceName(strings.TrimSuffix(refName, "^{}")) != name {
+					continue
+				}
+				if ref.Type() == plumbing.HashReference {
+					return ref.Hash().String(), nil
+				}
+				for _, target := range refs {
+					if target.Name() == ref.Target() && target.Type() == plumbing.HashReference {
+						return target.Hash().String(), nil
+					}
+				}
+			}
+		}
+	}
+	return "", fmt.Errorf("git remote %s has no tag or branch %s", endpoint.Host+"/"+strings.TrimPrefix(endpoint.Path, "/"), tag)
+}
+
+// getGitLFSURL returns the Git LFS server of an HTTP(S) remote as discovered by git-lfs by default
+func getGitLFSURL(url string) string {
+	url = strings.TrimSuffix(url, "/")
+	if !strings.HasSuffix(url, ".git") {
+		url += ".git"
+	}
+	return url + "/info/lfs"
+}
+
+// findGitLFSPointers returns the Git LFS pointer files of a working tree by their path
+func findGitLFSPointers(dir string) (map[string]gitLFSPointer, error) {
+	pointers := map[string]gitLFSPointer{}
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if d.Name() == ".git" {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		if info.Size() > gitLFSPointerLimit {
+			return nil
+		}
+		b, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		if pointer, ok := parseGitLFSPointer(b); ok {
+			pointers[path] = pointer
+		}
+		return nil
+	})
+
+	return pointers, err
+}
+
+func parseGitLFSPointer(b []byte) (gitLFSPointer, bool) {
+	if !bytes.HasPrefix(b, []byte("version https://git-lfs.github.com/spec/v1\n")) {
+		return gitLFSPointer{}, false
+	}
+	pointer := gitLFSPointer{Size: -1}
+	scanner := bufio.NewScanner(bytes.NewReader(b))
+	for scanner.Scan() {
+		key, value, _ := strings.Cut(scanner.Text(), " ")
+		switch key {
+		case "oid":
+			pointer.Oid = strings.TrimPrefix(value, "sha256:")
+		case "size":
+			size, err := strconv.ParseInt(value, 10, 64)
+			if err != nil {
+				return gitLFSPointer{}, false
+			}
+			pointer.Size = size
+		}
+	}
+	if len(pointer.Oid) != 64 || pointer.Size < 0 {
+		return gitLFSPointer{}, false
+	}
+	return pointer, true
+}
+
+// fetchGitLFSObjects replaces the pointer files by their objects downloaded through the Git LFS batch API
+func fetchGitLFSObjects(ctx context.Context, lfsURL string, auth *githttp.BasicAuth, pointers map[string]gitLFSPointer, progress ProgressFunc) error {
+	batchRequest := gitLFSBatchRequest{Operation: "download", Transfers: []string{"basic"}}
+	seen := map[string]bool{}
+	for _, pointer := range pointers {
+		if !seen[pointer.Oid] {
+			seen[pointer.Oid] = true
+			batchRequest.Objects = append(batchRequest.Objects, pointer)
+		}
+	}
+	b, err := json.Marshal(batchRequest)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, lfsURL+"/objects/batch", bytes.NewReader(b))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", gitLFSMediaType)
+	req.Header.Set("Content-Type", gitLFSMediaType)
+	if auth != nil {
+		req.SetBasicAuth(auth.Username, auth.Password)
+	}
+	resp, err := (&http.Client{Timeout: gitLFSTimeout}).Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("git lfs batch request failed with status %s", resp.Status)
+	}
+	var batchResponse gitLFSBatchResponse
+	if err := json.NewDecoder(resp.Body).Decode(&batchResponse); err != nil {
+		return err
+	}
+
+	type download struct {
+		href   string
+		header map[string]string
+	}
+	downloads := map[string]download{}
+	for _, object := range batchResponse.Objects {
+		if object.Error != nil {
+			return fmt.Errorf("git lfs object %s: %s", object.Oid, object.Error.Message)
+		}
+		if object.Actions.Download == nil {
+			return fmt.Errorf("git lfs object %s has no download action", object.Oid)
+		}
+		downloads[object.Oid] = download{href: object.Actions.Download.Href, header: object.Actions.Download.Header}
+	}
+
+	var total, done int64
+	for _, pointer := range pointers {
+		total += pointer.Size
+	}
+	for path, pointer := range pointers {
+		d, ok := downloads[pointer.Oid]
+		if !ok {
+			return fmt.Errorf("git lfs object %s is missing from the batch response", pointer.Oid)
+		}
+		// the object is downloaded next to its pointer file and replaces it once verified
+		dst := path + ".lfs"
+		_ = os.Remove(dst)
+		var objectProgress func(int64)
+		if progress != nil {
+			objectProgress = func(written int64) {
+				progress(done+written, total)
+			}
+		}
+		if err := downloadFile(ctx, d.href, dst, d.header, "sha256:"+pointer.Oid, objectProgress); err != nil {
+			_ = os.Remove(dst)
+			return err
+		}
+		if err := os.Rename(dst, path); err != nil {
+			return err
+		}
+		done += pointer.Size
+		if progress != nil {
+			progress(done, total)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,368 @@
+package util
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/instill-ai/model-backend/config"
+	"github.com/instill-ai/model-backend/pkg/datamodel"
+
+	modelPB "github.com/instill-ai/protogen-go/model/model/v1alpha"
+)
+
+const huggingFaceDefaultRevision = "main"
+
+// HuggingFaceEndpoint is the Hugging Face Hub models are downloaded from
+var HuggingFaceEndpoint = "https://huggingface.co"
+
+// huggingFaceWeightExts are the weight formats of a repository, only one of them is downloaded by default
+var huggingFaceWeightExts = []string{".safetensors", ".bin", ".h5", ".msgpack", ".ot", ".onnx", ".tflite", ".pt", ".pth", ".ckpt", ".gguf"}
+
+// HuggingFaceTemplate is the Triton model template of a Hugging Face pipeline, an ONNX exported model when OnnxFeature
+// is set and a Python backend running transformers otherwise
+type HuggingFaceTemplate struct {
+	Dir         string
+	Task        modelPB.Model_Task
+	OnnxFeature string
+}
+
+// HuggingFaceTemplates are the supported Hugging Face pipelines
+var HuggingFaceTemplates = map[string]HuggingFaceTemplate{
+	"image-classification": {Dir: "assets/huggingface-vit-template", Task: modelPB.Model_TASK_CLASSIFICATION, OnnxFeature: "image-classification"},
+	"object-detection":     {Dir: "assets/huggingface-detection-template", Task: modelPB.Model_TASK_DETECTION},
+	"text-classification":  {Dir: "assets/huggingface-text-classification-template", Task: modelPB.Model_TASK_UNSPECIFIED},
+	"feature-extraction":   {Dir: "assets/huggingface-feature-extraction-template", Task: modelPB.Model_TASK_UNSPECIFIED},
+	"text-generation":      {Dir: "assets/huggingface-text-generation-template", Task: modelPB.Model_TASK_TEXT_GENERATION},
+}
+
+// huggingFacePipelineAliases are the pipeline tags served by the template of another pipeline
+var huggingFacePipelineAliases = map[string]string{
+	"sentence-similarity": "feature-extraction",
+}
+
+// huggingFaceArchitectureSuffixes map the architectures of config.json, e.g. BertForSequenceClassification, to their
+// pipeline, the more specific suffixes first
+var huggingFaceArchitectureSuffixes = []struct {
+	suffix   string
+	pipeline string
+}{
+	{"ForImageClassification", "image-classification"},
+	{"ForObjectDetection", "object-detection"},
+	{"ForSequenceClassification", "text-classification"},
+	{"ForCausalLM", "text-generation"},
+	{"LMHeadModel", "text-generation"},
+	{"Model", "feature-extraction"},
+}
+
+// GetHuggingFaceTemplate returns the template of a pipeline, the empty pipeline of the models created before the
+// pipeline was recorded is image classification
+func GetHuggingFaceTemplate(pipeline string) (HuggingFaceTemplate, error) {
+	if pipeline == "" {
+		pipeline = "image-classification"
+	}
+	template, ok := HuggingFaceTemplates[pipeline]
+	if !ok {
+		return HuggingFaceTemplate{}, fmt.Errorf("hugging face pipeline %s is not supported", pipeline)
+	}
+	return template, nil
+}
+
+// GetHuggingFacePipeline returns the pipeline of a repository from its pipeline tag or, when it has none, from the
+// architectures of its config.json in confDir
+func GetHuggingFacePipeline(pipelineTag string, confDir string) (string, error) {
+	supported := make([]string, 0, len(HuggingFaceTemplates))
+	for pipeline := range HuggingFaceTemplates {
+		supported = append(supported, pipeline)
+	}
+	sort.Strings(supported)
+
+	if pipelineTag != "" {
+		if alias, ok := huggingFacePipelineAliases[pipelineTag]; ok {
+			pipelineTag = alias
+		}
+		if _, ok := HuggingFaceTemplates[pipelineTag]; !ok {
+			return "", fmt.Errorf("hugging face pipeline %s is not supported, the supported pipelines are %s", pipelineTag, strings.Join(supported, ", "))
+		}
+		return pipelineTag, nil
+	}
+
+	b, err := os.ReadFile(filepath.Join(confDir, "config.json"))
+	if err != nil {
+		return "", fmt.Errorf("hugging face repository has neither a pipeline tag nor a config.json: %w", err)
+	}
+	modelConfig := struct {
+		Architectures []string `json:"architectures"`
+	}{}
+	if err := json.Unmarshal(b, &modelConfig); err != nil {
+		return "", fmt.Errorf("invalid hugging face config.json: %w", err)
+	}
+	for _, s := range huggingFaceArchitectureSuffixes {
+		for _, architecture := range modelConfig.Architectures {
+			if strings.HasSuffix(architecture, s.suffix) {
+				return s.pipeline, nil
+			}
+		}
+	}
+	return "", fmt.Errorf("hugging face architectures %v are not supported, the supported pipelines are %s", modelConfig.Architectures, strings.Join(supported, ", "))
+}
+
+// HuggingFaceRepoInfo is the revision of a Hugging Face repository as returned by the Hub API
+type HuggingFaceRepoInfo struct {
+	Sha         string `json:"sha"`
+	PipelineTag string `json:"pipeline_tag"`
+	Private     bool   `json:"private"`
+	Siblings    []struct {
+		Rfilename string `json:"rfilename"`
+		Size      int64  `json:"size"`
+		// Lfs is set for the files stored with Git LFS, their checksum is listed
+		Lfs *struct {
+			Sha256 string `json:"sha256"`
+		} `json:"lfs,omitempty"`
+	} `json:"siblings"`
+}
+
+// GetHuggingFaceCredentialHeaders returns the authorization header of a Hugging Face token configured on the server side
+func GetHuggingFaceCredentialHeaders(credentialRef string) (map[string]string, error) {
+	if credentialRef == "" {
+		return nil, nil
+	}
+	credential, ok := config.Config.HuggingFace.Credentials[credentialRef]
+	if !ok {
+		return nil, fmt.Errorf("hugging face credential %q is not configured", credentialRef)
+	}
+	return map[string]string{"Authorization": "Bearer " + credential.Token}, nil
+}
+
+// GetHuggingFaceRepoInfo gets the commit and the files of a revision, a branch, a tag or a commit SHA, of a repository
+func GetHuggingFaceRepoInfo(ctx context.Context, repoID string, revision string, credentialRef string) (*HuggingFaceRepoInfo, error) {
+	headers, err := GetHuggingFaceCredentialHeaders(credentialRef)
+	if err != nil {
+		return nil, err
+	}
+	if revision == "" {
+		revision = huggingFaceDefaultRevision
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("%s/api/models/%s/revision/%s?blobs=true", HuggingFaceEndpoint, repoID, url.PathEscape(revision)), nil)
+	if err != nil {
+		return nil, err
+	}
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+	resp, err := (&http.Client{Timeout: time.Minute}).Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusUnauthorized:
+		return nil, fmt.Errorf("hugging face repository %s is private or does not exist, a valid access token is required", repoID)
+	case http.StatusForbidden:
+		return nil, fmt.Errorf("hugging face repository %s is gated, the access token owner has to accept its conditions first", repoID)
+	case http.StatusNotFound:
+		return nil, fmt.Errorf("hugging face repository %s has no revision %s", repoID, revision)
+	default:
+		return nil, fmt.Errorf("hugging face api request for %s failed with status %s", repoID, resp.Status)
+	}
+
+	info := HuggingFaceRepoInfo{}
+	if err := json.NewDecoder(resp.Body).Decode(&info); err != nil {
+		return nil, err
+	}
+	return &info, nil
+}
+
+func isHuggingFaceWeightFile(name string) bool {
+	ext := strings.ToLower(path.Ext(name))
+	for _, weightExt := range huggingFaceWeightExts {
+		if ext == weightExt {
+			return true
+		}
+	}
+	return false
+}
+
+// SelectHuggingFaceFiles selects the files of a repository to download. The files matching one of the allow patterns
+// are selected when there are some, otherwise the non weight files and the PyTorch weights, in safetensors format
+// when available, are. The weight files are left out unless withLargeFiles is set.
+func SelectHuggingFaceFiles(files []string, allowPatterns []string, withLargeFiles bool) []string {
+	weightExt := ".bin"
+	for _, f := range files {
+		if strings.HasSuffix(f, ".safetensors") {
+			weightExt = ".safetensors"
+			break
+		}
+	}
+
+	selected := []string{}
+	for _, f := range files {
+		isWeight := isHuggingFaceWeightFile(f)
+		if isWeight && !withLargeFiles {
+			continue
+		}
+		if len(allowPatterns) > 0 {
+			for _, pattern := range allowPatterns {
+				if match, _ := path.Match(pattern, f); match {
+					selected = append(selected, f)
+					break
+				}
+			}
+		} else if !isWeight || strings.HasSuffix(f, weightExt) {
+			selected = append(selected, f)
+		}
+	}
+	return selected
+}
+
+// HuggingFaceDownload downloads the selected files of a pinned commit of a repository into dir, the progress callback,
+// if any, receives the bytes downloaded as they are written. The download stops when ctx is done.
+func HuggingFaceDownload(ctx context.Context, dir string, modelConfig datamodel.HuggingFaceModelConfiguration, withLargeFiles bool, progress ProgressFunc) (*HuggingFaceRepoInfo, error) {
+	revision := modelConfig.Commit
+	if revision == "" {
+		revision = modelConfig.Revision
+	}
+	info, err := GetHuggingFaceRepoInfo(ctx, modelConfig.RepoId, revision, modelConfig.CredentialRef)
+	if err != nil {
+		return nil, err
+	}
+	headers, err := GetHuggingFaceCredentialHeaders(modelConfig.CredentialRef)
+	if err != nil {
+		return nil, err
+	}
+
+	sizes := map[string]int64{}
+	checksums := map[string]string{}
+	files := []string{}
+	for _, sibling := range info.Siblings {
+		sizes[sibling.Rfilename] = sibling.Size
+		if sibling.Lfs != nil && sibling.Lfs.Sha256 != "" {
+			checksums[sibling.Rfilename] = "sha256:" + sibling.Lfs.Sha256
+		}
+		files = append(files, sibling.Rfilename)
+	}
+	selected := SelectHuggingFaceFiles(files, modelConfig.AllowPatterns, withLargeFiles)
+	if len(selected) == 0 {
+		return nil, fmt.Errorf("no file of hugging face repository %s matches %v", modelConfig.RepoId, modelConfig.AllowPatterns)
+	}
+	var total, done int64
+	for _, f := range selected {
+		total += sizes[f]
+	}
+
+	root := filepath.Clean(dir) + string(os.PathSeparator)
+	for _, f := range selected {
+		dst := filepath.Join(dir, f)
+		if !strings.HasPrefix(dst, root) {
+			return nil, fmt.Errorf("hugging face file %s is outside of the model folder", f)
+		}
+		if err := os.MkdirAll(filepath.Dir(dst), os.ModePerm); err != nil {
+			return nil, err
+		}
+		fileURL := fmt.Sprintf("%s/%s/resolve/%s/%s", HuggingFaceEndpoint, modelConfig.RepoId, info.Sha, f)
+		var fileProgress func(int64)
+		if progress != nil {
+			fileProgress = func(written int64) {
+				if written > sizes[f] {
+					written = sizes[f]
+				}
+				progress(done+written, total)
+			}
+		}
+		if err := downloadFile(ctx, fileURL, dst, headers, checksums[f], fileProgress); err != nil {
+			return nil, err
+		}
+		done += sizes[f]
+		if progress != nil {
+			progress(done, total)
+		}
+	}
+
+	return info, nil
+}
+
+// GetHuggingFaceArtifact returns the model cache source and revision of the export of a repository, the revision is
+// empty when the repository was not pinned to a commit
+func GetHuggingFaceArtifact(modelConfig datamodel.HuggingFaceModelConfiguration) (string, string) {
+	if modelConfig.Commit == "" {
+		return "huggingface:" + modelConfig.RepoId, ""
+	}
+	pipeline := modelConfig.Pipeline
+	if pipeline == "" {
+		pipeline = "image-classification"
+	}
+	revision := modelConfig.Commit + "#" + pipeline
+	if len(modelConfig.AllowPatterns) > 0 {
+		patterns := append([]string{}, modelConfig.AllowPatterns...)
+		sort.Strings(patterns)
+		revision += "#" + strings.Join(patterns, ",")
+	}
+	return "huggingface:" + modelConfig.RepoId, revision
+}
+
+// HuggingFaceClone downloads the configuration files of a Hugging Face repository, without the weights
+func HuggingFaceClone(ctx context.Context, dir string, modelConfig datamodel.HuggingFaceModelConfiguration) error {
+	if err := os.MkdirAll(dir, os.ModePerm); err != nil {
+		return err
+	}
+	_, err := HuggingFaceDownload(ctx, dir, modelConfig, false, nil)
+	return err
+}
+
+// HuggingFaceExport downloads a Hugging Face repository in a folder structure similar with Triton to support copying
+// the model into the model repository later. The repository is exported to ONNX for the ONNX templates and kept as is
+// for the Python backend ones. In the integration tests, a local folder named by the repository ID is exported as is.
+func HuggingFaceExport(ctx context.Context, dir string, modelConfig datamodel.HuggingFaceModelConfiguration, modelID string, progress ProgressFunc) error {
+	template, err := GetHuggingFaceTemplate(modelConfig.Pipeline)
+	if err != nil {
+		return err
+	}
+	inferDir := fmt.Sprintf("%s/%s-infer/1", dir, modelID)
+	if err := os.MkdirAll(inferDir, os.ModePerm); err != nil {
+		return err
+	}
+
+	// a repository ID is never read from the filesystem of the server otherwise
+	isLocal := false
+	if config.Config.Server.ItMode.Enabled {
+		_, err := os.Stat(modelConfig.RepoId)
+		isLocal = err == nil
+	}
+
+	if template.OnnxFeature == "" {
+		if isLocal {
+			return exec.CommandContext(ctx, "cp", "-rf", modelConfig.RepoId+"/.", inferDir).Run()
+		}
+		_, err := HuggingFaceDownload(ctx, inferDir, modelConfig, true, progress)
+		return err
+	}
+
+	modelPath := modelConfig.RepoId
+	if !isLocal {
+		modelPath = fmt.Sprintf("%s/snapshot", dir)
+		if _, err := HuggingFaceDownload(ctx, modelPath, modelConfig, true, progress); err != nil {
+			return err
+		}
+		defer os.RemoveAll(modelPath)
+	}
+
+	// atol 0.001 mean that accept difference with 0.1%
+	cmd := exec.CommandContext(ctx, "python3", "-m", "transformers.onnx", "--feature="+template.OnnxFeature, "--atol", "0.001",
+		"--model="+modelPath, inferDir)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("onnx export failed: %v: %s", err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
@@ -0,0 +1,271 @@
+package util
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/instill-ai/model-backend/pkg/datamodel"
+	"github.com/instill-ai/model-backend/pkg/triton/inferenceserver"
+
+	modelPB "github.com/instill-ai/protogen-go/model/model/v1alpha"
+)
+
+// The types of the model package violations
+const (
+	ModelPackageViolationPackage  = "MODEL PACKAGE"
+	ModelPackageViolationConfig   = "MODEL CONFIG"
+	ModelPackageViolationVersion  = "MODEL VERSION"
+	ModelPackageViolationEnsemble = "ENSEMBLE"
+	ModelPackageViolationTask     = "MODEL TASK"
+)
+
+// ModelPackageViolation is a problem found in the Triton models of a model package
+type ModelPackageViolation struct {
+	Type        string `json:"type"`
+	Subject     string `json:"subject"`
+	Description string `json:"description"`
+}
+
+// taskTensors are the data types of the tensors exchanged with the served model by the inference of a task, in
+// order. The inputs are all sent and the outputs are the least the post-processing reads. Only the string and the
+// numeric tensors are told apart, TYPE_INVALID is any type.
+var taskTensors = map[modelPB.Model_Task]struct {
+	inputs  []inferenceserver.DataType
+	outputs []inferenceserver.DataType
+}{
+	modelPB.Model_TASK_CLASSIFICATION: {
+		inputs:  []inferenceserver.DataType{inferenceserver.DataType_TYPE_INVALID},
+		outputs: []inferenceserver.DataType{inferenceserver.DataType_TYPE_INVALID},
+	},
+	modelPB.Model_TASK_DETECTION: {
+		inputs:  []inferenceserver.DataType{inferenceserver.DataType_TYPE_INVALID},
+		outputs: []inferenceserver.DataType{inferenceserver.DataType_TYPE_FP32, inferenceserver.DataType_TYPE_STRING},
+	},
+	modelPB.Model_TASK_KEYPOINT: {
+		inputs:  []inferenceserver.DataType{inferenceserver.DataType_TYPE_INVALID},
+		outputs: []inferenceserver.DataType{inferenceserver.DataType_TYPE_FP32, inferenceserver.DataType_TYPE_FP32, inferenceserver.DataType_TYPE_FP32},
+	},
+	modelPB.Model_TASK_OCR: {
+		inputs:  []inferenceserver.DataType{inferenceserver.DataType_TYPE_INVALID},
+		outputs: []inferenceserver.DataType{inferenceserver.DataType_TYPE_FP32, inferenceserver.DataType_TYPE_STRING},
+	},
+	modelPB.Model_TASK_INSTANCE_SEGMENTATION: {
+		inputs:  []inferenceserver.DataType{inferenceserver.DataType_TYPE_INVALID},
+		outputs: []inferenceserver.DataType{inferenceserver.DataType_TYPE_STRING, inferenceserver.DataType_TYPE_FP32, inferenceserver.DataType_TYPE_STRING, inferenceserver.DataType_TYPE_FP32},
+	},
+	modelPB.Model_TASK_SEMANTIC_SEGMENTATION: {
+		inputs:  []inferenceserver.DataType{inferenceserver.DataType_TYPE_INVALID},
+		outputs: []inferenceserver.DataType{inferenceserver.DataType_TYPE_STRING, inferenceserver.DataType_TYPE_STRING},
+	},
+	modelPB.Model_TASK_TEXT_TO_IMAGE: {
+		inputs: []inferenceserver.DataType{
+			inferenceserver.DataType_TYPE_STRING, inferenceserver.DataType_TYPE_STRING, inferenceserver.DataType_TYPE_INVALID,
+			inferenceserver.DataType_TYPE_STRING, inferenceserver.DataType_TYPE_INVALID, inferenceserver.DataType_TYPE_INVALID,
+			inferenceserver.DataType_TYPE_INVALID,
+		},
+		outputs: []inferenceserver.DataType{inferenceserver.DataType_TYPE_FP32},
+	},
+	modelPB.Model_TASK_TEXT_GENERATION: {
+		inputs: []inferenceserver.DataType{
+			inferenceserver.DataType_TYPE_STRING, inferenceserver.DataType_TYPE_INVALID, inferenceserver.DataType_TYPE_STRING,
+			inferenceserver.DataType_TYPE_STRING, inferenceserver.DataType_TYPE_INVALID, inferenceserver.DataType_TYPE_INVALID,
+		},
+		outputs: []inferenceserver.DataType{inferenceserver.DataType_TYPE_STRING},
+	},
+}
+
+// getTritonModelFolderName returns the folder name a Triton model had in the model package
+func getTritonModelFolderName(tritonModelName string) string {
+	if parts := strings.Split(tritonModelName, "#"); len(parts) == 4 {
+		return parts[2]
+	}
+	return tritonModelName
+}
+
+func getTensorKind(dataType inferenceserver.DataType) string {
+	if dataType == inferenceserver.DataType_TYPE_STRING {
+		return "string"
+	}
+	return "numeric"
+}
+
+// getTritonModelVersions returns the version folders of a Triton model
+func getTritonModelVersions(modelDir string) map[int64]bool {
+	versions := map[int64]bool{}
+	entries, _ := os.ReadDir(modelDir)
+	for _, entry := range entries {
+		if v, err := strconv.ParseInt(entry.Name(), 10, 64); err == nil && entry.IsDir() && v > 0 {
+			versions[v] = true
+		}
+	}
+	return versions
+}
+
+// ValidateModelPackage lints the Triton models of a model written into the model repository by UpdateModelPath: every
+// config.pbtxt is parsed, version folders and ensemble steps are checked and the tensors of the served model are
+// matched against the pre and post-processing of the model task
+func ValidateModelPackage(modelRepository string, owner string, model *datamodel.Model) []ModelPackageViolation {
+	var modelConfiguration datamodel.GitHubModelConfiguration
+	_ = json.Unmarshal(model.Configuration, &modelConfiguration)
+	dirs, _ := filepath.Glob(filepath.Join(modelRepository, fmt.Sprintf("%s#%s#*#%s", owner, model.ID, modelConfiguration.Tag)))
+	sort.Strings(dirs)
+	tritonModelDirs := []string{}
+	for _, dir := range dirs {
+		if info, err := os.Stat(dir); err == nil && info.IsDir() {
+			tritonModelDirs = append(tritonModelDirs, dir)
+		}
+	}
+	dirs = tritonModelDirs
+
+	violations := []ModelPackageViolation{}
+	addViolation := func(violationType string, tritonModelName string, format string, a ...interface{}) {
+		violations = append(violations, ModelPackageViolation{
+			Type:        violationType,
+			Subject:     getTritonModelFolderName(tritonModelName),
+			Description: fmt.Sprintf(format, a...),
+		})
+	}
+	if len(dirs) == 0 {
+		addViolation(ModelPackageViolationPackage, model.ID, "the model package has no triton model folder")
+		return violations
+	}
+
+	modelConfigs := map[string]*inferenceserver.ModelConfig{}
+	modelVersions := map[string]map[int64]bool{}
+	for _, dir := range dirs {
+		name, err := filepath.Rel(modelRepository, dir)
+		if err != nil {
+			continue
+		}
+		modelVersions[name] = getTritonModelVersions(dir)
+		if len(modelVersions[name]) == 0 {
+			addViolation(ModelPackageViolationVersion, name, "the triton model has no version folder")
+		}
+
+		if _, err := os.Stat(filepath.Join(dir, "config.pbtxt")); err != nil {
+			addViolation(ModelPackageViolationConfig, name, "the triton model has no config.pbtxt")
+			continue
+		}
+		modelConfig, err := ReadTritonModelConfig(modelRepository, name)
+		if err != nil {
+			addViolation(ModelPackageViolationConfig, name, "%s", err)
+			continue
+		}
+		modelConfigs[name] = modelConfig
+		if modelConfig.Name != "" && modelConfig.Name != name {
+			addViolation(ModelPackageViolationConfig, name, "the name %q in config.pbtxt does not match the triton model folder", getTritonModelFolderName(modelConfig.Name))
+		}
+		if specific, ok := modelConfig.GetVersionPolicy().GetPolicyChoice().(*inferenceserver.ModelVersionPolicy_Specific_); ok {
+			for _, v := range specific.Specific.GetVersions() {
+				if !modelVersions[name][v] {
+					addViolation(ModelPackageViolationVersion, name, "the version %d of the version policy has no version folder", v)
+				}
+			}
+		}
+	}
+
+	for _, name := range sortedKeys(modelConfigs) {
+		ensemble := modelConfigs[name].GetEnsembleScheduling()
+		if modelConfigs[name].Platform != tritonEnsemblePlatform {
+			continue
+		}
+		if len(ensemble.GetStep()) == 0 {
+			addViolation(ModelPackageViolationEnsemble, name, "the ensemble has no step")
+		}
+		for i, step := range ensemble.GetStep() {
+			versions, ok := modelVersions[step.ModelName]
+			if !ok {
+				addViolation(ModelPackageViolationEnsemble, name, "the step %d references the triton model %q missing from the model package", i, getTritonModelFolderName(step.ModelName))
+				continue
+			}
+			if step.ModelVersion > 0 && !versions[step.ModelVersion] {
+				addViolation(ModelPackageViolationEnsemble, name, "the step %d references the version %d of the triton model %q which has no version folder", i, step.ModelVersion, getTritonModelFolderName(step.ModelName))
+			}
+			stepConfig, ok := modelConfigs[step.ModelName]
+			// the tensors of the models which let Triton complete their configuration cannot be checked
+			if !ok || len(stepConfig.Input) == 0 || len(stepConfig.Output) == 0 {
+				continue
+			}
+			inputs := map[string]bool{}
+			for _, input := range stepConfig.Input {
+				inputs[input.Name] = true
+			}
+			for _, tensor := range sortedKeys(step.InputMap) {
+				if !inputs[tensor] {
+					addViolation(ModelPackageViolationEnsemble, name, "the step %d maps the input %q which the triton model %q does not have", i, tensor, getTritonModelFolderName(step.ModelName))
+				}
+			}
+			outputs := map[string]bool{}
+			for _, output := range stepConfig.Output {
+				outputs[output.Name] = true
+			}
+			for _, tensor := range sortedKeys(step.OutputMap) {
+				if !outputs[tensor] {
+					addViolation(ModelPackageViolationEnsemble, name, "the step %d maps the output %q which the triton model %q does not have", i, tensor, getTritonModelFolderName(step.ModelName))
+				}
+			}
+		}
+	}
+
+	// the served model is the ensemble, or the only model of the package
+	served := ""
+	for _, tm := range model.TritonModels {
+		if tm.Platform == tritonEnsemblePlatform {
+			served = tm.Name
+		}
+	}
+	for _, name := range sortedKeys(modelConfigs) {
+		if modelConfigs[name].Platform == tritonEnsemblePlatform {
+			served = name
+		}
+	}
+	if served == "" && len(dirs) == 1 {
+		served, _ = filepath.Rel(modelRepository, dirs[0])
+	}
+	if served == "" {
+		addViolation(ModelPackageViolationPackage, model.ID, "the model package has several triton models but no ensemble model")
+		return violations
+	}
+
+	servedConfig, ok := modelConfigs[served]
+	tensors, hasTensors := taskTensors[modelPB.Model_Task(model.Task)]
+	// the tensors of a model which lets Triton complete its configuration are only known once loaded
+	if !ok || !hasTensors || (len(servedConfig.Input) == 0 && len(servedConfig.Output) == 0) {
+		return violations
+	}
+	task := modelPB.Model_Task(model.Task).String()
+	if len(servedConfig.Input) != len(tensors.inputs) {
+		addViolation(ModelPackageViolationTask, served, "the %s task sends %d input(s) but the model has %d", task, len(tensors.inputs), len(servedConfig.Input))
+	} else {
+		for i, dataType := range tensors.inputs {
+			if dataType != inferenceserver.DataType_TYPE_INVALID && getTensorKind(servedConfig.Input[i].DataType) != getTensorKind(dataType) {
+				addViolation(ModelPackageViolationTask, served, "the %s task sends the input %q as a %s tensor but the model has %s", task, servedConfig.Input[i].Name, getTensorKind(dataType), servedConfig.Input[i].DataType)
+			}
+		}
+	}
+	if len(servedConfig.Output) < len(tensors.outputs) {
+		addViolation(ModelPackageViolationTask, served, "the %s task reads %d output(s) but the model has %d", task, len(tensors.outputs), len(servedConfig.Output))
+	} else {
+		for i, dataType := range tensors.outputs {
+			if dataType != inferenceserver.DataType_TYPE_INVALID && getTensorKind(servedConfig.Output[i].DataType) != getTensorKind(dataType) {
+				addViolation(ModelPackageViolationTask, served, "the %s task reads the output %q as a %s tensor but the model has %s", task, servedConfig.Output[i].Name, getTensorKind(dataType), servedConfig.Output[i].DataType)
+			}
+		}
+	}
+
+	return violations
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
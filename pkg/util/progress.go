@@ -0,0 +1,122 @@
+package util
+
+import (
+	"bufio"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ProgressFunc receives the number of bytes downloaded so far and the number of bytes to download, which is 0 when
+// it is not known beforehand
+type ProgressFunc func(done int64, total int64)
+
+// DownloadProgressInterval is how often the size of a folder is measured while an external tool downloads into it
+var DownloadProgressInterval = 2 * time.Second
+
+// GetProgressPercentage returns the percentage of the bytes downloaded, 0 when the total is unknown
+func GetProgressPercentage(done int64, total int64) int32 {
+	switch {
+	case total <= 0:
+		return 0
+	case done >= total:
+		return 100
+	default:
+		return int32(done * 100 / total)
+	}
+}
+
+// progressWriter counts the bytes written through it, starting from the size of a resumed download
+type progressWriter struct {
+	w        io.Writer
+	written  int64
+	progress func(written int64)
+}
+
+func (p *progressWriter) Write(b []byte) (int, error) {
+	n, err := p.w.Write(b)
+	p.written += int64(n)
+	if n > 0 {
+		p.progress(p.written)
+	}
+	return n, err
+}
+
+// getDownloadedSize returns the size of the regular files of a folder, the Git and DVC internals excluded
+func getDownloadedSize(dir string) int64 {
+	var size int64
+	_ = filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if d.IsDir() && (d.Name() == ".git" || d.Name() == ".dvc") {
+			return filepath.SkipDir
+		}
+		if d.Type().IsRegular() {
+			if info, err := d.Info(); err == nil {
+				size += info.Size()
+			}
+		}
+		return nil
+	})
+	return size
+}
+
+// watchDownload reports the bytes an external tool writes into dir at every DownloadProgressInterval until it is
+// stopped, what dir holds when the watch starts is not counted
+func watchDownload(dir string, total int64, progress ProgressFunc) func() {
+	if progress == nil {
+		return func() {}
+	}
+	baseline := getDownloadedSize(dir)
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		ticker := time.NewTicker(DownloadProgressInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				n := getDownloadedSize(dir) - baseline
+				if n < 0 {
+					n = 0
+				}
+				if total > 0 && n > total {
+					n = total
+				}
+				progress(n, total)
+			}
+		}
+	}()
+	return func() {
+		close(done)
+		<-stopped
+	}
+}
+
+// getDVCSize returns the size of the outputs tracked by a .dvc file
+func getDVCSize(dvcPath string) int64 {
+	f, err := os.Open(dvcPath)
+	if err != nil {
+		return 0
+	}
+	defer f.Close()
+	var size int64
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimPrefix(strings.TrimSpace(scanner.Text()), "- ")
+		if strings.HasPrefix(line, "size:") {
+			if n, err := strconv.ParseInt(strings.TrimSpace(strings.TrimPrefix(line, "size:")), 10, 64); err == nil {
+				size += n
+			}
+		}
+	}
+	return size
+}
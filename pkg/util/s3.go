@@ -0,0 +1,349 @@
+package util
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"hash"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/instill-ai/model-backend/config"
+	"github.com/instill-ai/model-backend/pkg/datamodel"
+)
+
+const (
+	s3DefaultRegion    = "us-east-1"
+	s3EmptyPayloadHash = "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+	s3RequestTimeout   = 30 * time.Minute
+)
+
+// s3Client is a minimal S3 API client signing its requests with AWS Signature Version 4.
+// Requests are path-style so that it works against MinIO and the other S3 compatible storages.
+type s3Client struct {
+	endpoint   *url.URL
+	region     string
+	credential *config.S3CredentialConfig
+	httpClient *http.Client
+}
+
+type s3Object struct {
+	Key  string `xml:"Key"`
+	ETag string `xml:"ETag"`
+	Size int64  `xml:"Size"`
+}
+
+type s3ListBucketResult struct {
+	IsTruncated           bool       `xml:"IsTruncated"`
+	NextContinuationToken string     `xml:"NextContinuationToken"`
+	Contents              []s3Object `xml:"Contents"`
+}
+
+type s3Error struct {
+	Code    string `xml:"Code"`
+	Message string `xml:"Message"`
+}
+
+func newS3Client(modelConfig datamodel.S3ModelConfiguration) (*s3Client, error) {
+	endpoint, err := url.Parse(modelConfig.Endpoint)
+	if err != nil || (endpoint.Scheme != "http" && endpoint.Scheme != "https") || endpoint.Host == "" {
+		return nil, fmt.Errorf("invalid s3 endpoint %q", modelConfig.Endpoint)
+	}
+
+	c := &s3Client{
+		endpoint:   endpoint,
+		region:     modelConfig.Region,
+		httpClient: &http.Client{Timeout: s3RequestTimeout},
+	}
+	if c.region == "" {
+		c.region = s3DefaultRegion
+	}
+	// Without a credential reference the bucket is read anonymously
+	if modelConfig.CredentialRef != "" {
+		credential, ok := config.Config.S3.Credentials[modelConfig.CredentialRef]
+		if !ok {
+			return nil, fmt.Errorf("s3 credential %q is not configured", modelConfig.CredentialRef)
+		}
+		c.credential = &credential
+	}
+
+	return c, nil
+}
+
+// do sends a signed request and returns the response when its status is 200 OK
+func (c *s3Client) do(ctx context.Context, path string, query url.Values, header http.Header) (*http.Response, error) {
+	u := *c.endpoint
+	u.Path = strings.TrimSuffix(u.Path, "/") + path
+	u.RawPath = s3URIEncode(u.Path, false)
+	u.RawQuery = s3CanonicalQuery(query)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	if c.credential != nil {
+		c.sign(req, time.Now().UTC())
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+		var e s3Error
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1<<16))
+		if xml.Unmarshal(body, &e) == nil && e.Code != "" {
+			return nil, fmt.Errorf("s3 request %s failed: %s: %s", path, e.Code, e.Message)
+		}
+		return nil, fmt.Errorf("s3 request %s failed with status %s", path, resp.Status)
+	}
+
+	return resp, nil
+}
+
+// sign adds the AWS Signature Version 4 authorization header to a bodiless request
+func (c *s3Client) sign(req *http.Request, now time.Time) {
+	amzDate := now.Format("20060102T150405Z")
+	date := now.Format("20060102")
+
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", s3EmptyPayloadHash)
+	if c.credential.SessionToken != "" {
+		req.Header.Set("X-Amz-Security-Token", c.credential.SessionToken)
+	}
+
+	headers := map[string]string{"host": req.URL.Host}
+	for k := range req.Header {
+		headers[strings.ToLower(k)] = strings.TrimSpace(req.Header.Get(k))
+	}
+	names := make([]string, 0, len(headers))
+	for k := range headers {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+	var canonicalHeaders strings.Builder
+	for _, k := range names {
+		canonicalHeaders.WriteString(k + ":" + headers[k] + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		req.URL.RawQuery,
+		canonicalHeaders.String(),
+		signedHeaders,
+		s3EmptyPayloadHash,
+	}, "\n")
+
+	scope := fmt.Sprintf("%s/%s/s3/aws4_request", date, c.region)
+	requestHash := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := strings.Join([]string{"AWS4-HMAC-SHA256", amzDate, scope, hex.EncodeToString(requestHash[:])}, "\n")
+
+	key := s3HMAC([]byte("AWS4"+c.credential.SecretAccessKey), date)
+	key = s3HMAC(key, c.region)
+	key = s3HMAC(key, "s3")
+	key = s3HMAC(key, "aws4_request")
+	signature := hex.EncodeToString(s3HMAC(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		c.credential.AccessKeyID, scope, signedHeaders, signature))
+}
+
+func (c *s3Client) listObjects(ctx context.Context, bucket string, prefix string) ([]s3Object, error) {
+	objects := []s3Object{}
+	token := ""
+	for {
+		query := url.Values{"list-type": {"2"}, "prefix": {prefix}}
+		if token != "" {
+			query.Set("continuation-token", token)
+		}
+		resp, err := c.do(ctx, "/"+bucket, query, nil)
+		if err != nil {
+			return nil, err
+		}
+		var result s3ListBucketResult
+		err = xml.NewDecoder(resp.Body).Decode(&result)
+		resp.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		objects = append(objects, result.Contents...)
+		if !result.IsTruncated || result.NextContinuationToken == "" {
+			break
+		}
+		token = result.NextContinuationToken
+	}
+
+	return objects, nil
+}
+
+// getObject downloads an object to dst and verifies its size and checksum, the SHA-256 checksum when the object
+// was uploaded with one, otherwise the MD5 ETag of single part uploads
+func (c *s3Client) getObject(ctx context.Context, bucket string, object s3Object, dst string) error {
+	resp, err := c.do(ctx, "/"+bucket+"/"+object.Key, nil, http.Header{"X-Amz-Checksum-Mode": {"ENABLED"}})
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if err := os.MkdirAll(filepath.Dir(dst), os.ModePerm); err != nil {
+		return err
+	}
+	f, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	md5Hash, sha256Hash := md5.New(), sha256.New()
+	size, err := io.Copy(io.MultiWriter(f, md5Hash, sha256Hash), resp.Body)
+	if err != nil {
+		return err
+	}
+	if size != object.Size {
+		return fmt.Errorf("s3 object %s is %d bytes but %d bytes were downloaded", object.Key, object.Size, size)
+	}
+
+	return verifyS3Checksum(object.Key, resp.Header, md5Hash, sha256Hash)
+}
+
+func verifyS3Checksum(key string, header http.Header, md5Hash hash.Hash, sha256Hash hash.Hash) error {
+	// Checksums of multipart uploads are composite, suffixed with the number of parts, and cannot be recomputed
+	if checksum := header.Get("X-Amz-Checksum-Sha256"); checksum != "" && !strings.Contains(checksum, "-") {
+		if got := base64.StdEncoding.EncodeToString(sha256Hash.Sum(nil)); got != checksum {
+			return fmt.Errorf("sha256 checksum mismatch for s3 object %s", key)
+		}
+		return nil
+	}
+	if etag := strings.Trim(header.Get("ETag"), `"`); len(etag) == 32 && !strings.Contains(etag, "-") {
+		if got := hex.EncodeToString(md5Hash.Sum(nil)); got != strings.ToLower(etag) {
+			return fmt.Errorf("md5 checksum mismatch for s3 object %s", key)
+		}
+	}
+	return nil
+}
+
+// s3Prefix returns the prefix of the objects of a model folder
+func s3Prefix(folder string) string {
+	prefix := strings.Trim(folder, "/")
+	if prefix != "" {
+		prefix += "/"
+	}
+	return prefix
+}
+
+// CheckS3Source checks that the prefix of an S3 compatible bucket can be listed and holds objects, nothing is
+// downloaded
+func CheckS3Source(ctx context.Context, modelConfig datamodel.S3ModelConfiguration) error {
+	c, err := newS3Client(modelConfig)
+	if err != nil {
+		return err
+	}
+	prefix := s3Prefix(modelConfig.Prefix)
+	resp, err := c.do(ctx, "/"+modelConfig.Bucket, url.Values{"list-type": {"2"}, "prefix": {prefix}, "max-keys": {"1"}}, nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	var result s3ListBucketResult
+	if err := xml.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return err
+	}
+	if len(result.Contents) == 0 {
+		return fmt.Errorf("no object found in s3 bucket %s under prefix %q", modelConfig.Bucket, prefix)
+	}
+	return nil
+}
+
+// S3Download downloads the Triton model repository layout stored under the prefix of an S3 compatible bucket,
+// the model weight files are skipped unless withLargeFiles is set. The download stops when ctx is done.
+func S3Download(ctx context.Context, dir string, modelConfig datamodel.S3ModelConfiguration, withLargeFiles bool) error {
+	if err := os.MkdirAll(dir, os.ModePerm); err != nil {
+		return err
+	}
+	c, err := newS3Client(modelConfig)
+	if err != nil {
+		return err
+	}
+
+	prefix := s3Prefix(modelConfig.Prefix)
+
+	objects, err := c.listObjects(ctx, modelConfig.Bucket, prefix)
+	if err != nil {
+		return err
+	}
+	if len(objects) == 0 {
+		return fmt.Errorf("no object found in s3 bucket %s under prefix %q", modelConfig.Bucket, prefix)
+	}
+
+	root := filepath.Clean(dir) + string(os.PathSeparator)
+	for _, object := range objects {
+		name := strings.TrimPrefix(object.Key, prefix)
+		if name == "" || strings.HasSuffix(name, "/") { // folder placeholders
+			continue
+		}
+		if !withLargeFiles && isModelFile(filepath.Base(name)) {
+			continue
+		}
+		dst := filepath.Join(dir, name)
+		if !strings.HasPrefix(dst, root) {
+			return fmt.Errorf("s3 object %s is outside of the model folder", object.Key)
+		}
+		if err := c.getObject(ctx, modelConfig.Bucket, object, dst); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func s3HMAC(key []byte, data string) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(data))
+	return h.Sum(nil)
+}
+
+// s3URIEncode encodes a string as specified by Signature Version 4, slashes are kept unless encodeSlash is set
+func s3URIEncode(s string, encodeSlash bool) string {
+	var b strings.Builder
+	for _, c := range []byte(s) {
+		if (c >= 'A' && c <= 'Z') || (c >= 'a' && c <= 'z') || (c >= '0' && c <= '9') ||
+			c == '-' || c == '_' || c == '.' || c == '~' || (c == '/' && !encodeSlash) {
+			b.WriteByte(c)
+		} else {
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
+
+func s3CanonicalQuery(query url.Values) string {
+	keys := make([]string, 0, len(query))
+	for k := range query {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	pairs := []string{}
+	for _, k := range keys {
+		for _, v := range query[k] {
+			pairs = append(pairs, s3URIEncode(k, true)+"="+s3URIEncode(v, true))
+		}
+	}
+	return strings.Join(pairs, "&")
+}
@@ -0,0 +1,325 @@
+package util
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gofrs/uuid"
+
+	"github.com/instill-ai/model-backend/config"
+)
+
+// ErrUploadSessionNotFound is returned when an upload session does not exist or has expired
+var ErrUploadSessionNotFound = errors.New("upload session not found")
+
+// UploadRange is a received range of an upload, from Start included to End excluded
+type UploadRange struct {
+	Start int64 `json:"start"`
+	End   int64 `json:"end"`
+}
+
+// UploadSession is a resumable upload of a local model archive, whose chunks are written at their offset
+type UploadSession struct {
+	UID               string        `json:"uid"`
+	Owner             string        `json:"owner"`
+	ModelID           string        `json:"model_id"`
+	ModelDefinitionID string        `json:"model_definition_id"`
+	Visibility        string        `json:"visibility"`
+	Description       string        `json:"description"`
+	Filename          string        `json:"filename"`
+	Size              int64         `json:"size"`
+	Sha256            string        `json:"sha256,omitempty"`
+	ReceivedRanges    []UploadRange `json:"received_ranges"`
+	CreateTime        time.Time     `json:"create_time"`
+	ExpireTime        time.Time     `json:"expire_time"`
+}
+
+// ReceivedSize returns the number of bytes received
+func (s *UploadSession) ReceivedSize() int64 {
+	var size int64
+	for _, r := range s.ReceivedRanges {
+		size += r.End - r.Start
+	}
+	return size
+}
+
+// IsComplete returns whether every byte of the upload was received
+func (s *UploadSession) IsComplete() bool {
+	return len(s.ReceivedRanges) == 1 && s.ReceivedRanges[0].Start == 0 && s.ReceivedRanges[0].End == s.Size
+}
+
+// GetUploadDir returns where the upload sessions are stored. The folder is local to the API server, every chunk and the
+// finalization of a session must reach the same replica, e.g. with sticky sessions, or the folder must be a shared
+// volume mounted on every replica.
+func GetUploadDir() string {
+	return filepath.Join(TMP_DIR, "uploads")
+}
+
+func uploadSessionDir(uid string) (string, error) {
+	if _, err := uuid.FromString(uid); err != nil {
+		return "", ErrUploadSessionNotFound
+	}
+	return filepath.Join(GetUploadDir(), uid), nil
+}
+
+// GetUploadContentPath returns the file the chunks of an upload session are written to
+func GetUploadContentPath(uid string) string {
+	return filepath.Join(GetUploadDir(), uid, "content")
+}
+
+// LockUploadSession takes the lock of an upload session, held while its state is updated or it is finalized
+func LockUploadSession(uid string) (func(), error) {
+	dir, err := uploadSessionDir(uid)
+	if err != nil {
+		return nil, err
+	}
+	// the folder of a removed session is not created again by its lock
+	if _, err := os.Stat(dir); errors.Is(err, fs.ErrNotExist) {
+		return nil, ErrUploadSessionNotFound
+	}
+	return lockFile(filepath.Join(dir, "session.lock"), true)
+}
+
+func readUploadSession(dir string) (*UploadSession, error) {
+	b, err := os.ReadFile(filepath.Join(dir, "session.json"))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, ErrUploadSessionNotFound
+	} else if err != nil {
+		return nil, err
+	}
+	session := UploadSession{}
+	if err := json.Unmarshal(b, &session); err != nil {
+		return nil, err
+	}
+	return &session, nil
+}
+
+func writeUploadSession(dir string, session *UploadSession) error {
+	b, err := json.Marshal(session)
+	if err != nil {
+		return err
+	}
+	tmp := filepath.Join(dir, "session.json.tmp")
+	if err := os.WriteFile(tmp, b, 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, filepath.Join(dir, "session.json"))
+}
+
+// CreateUploadSession stores a new upload session and allocates its content file
+func CreateUploadSession(session *UploadSession) error {
+	if session.Size <= 0 {
+		return fmt.Errorf("the upload size must be positive")
+	}
+	if maxSize := config.Config.Upload.MaxSize; maxSize > 0 && session.Size > maxSize {
+		return fmt.Errorf("the upload size %d exceeds the limitation %d", session.Size, maxSize)
+	}
+	if session.Sha256 != "" {
+		if b, err := hex.DecodeString(session.Sha256); err != nil || len(b) != sha256.Size {
+			return fmt.Errorf("invalid sha256 checksum %q", session.Sha256)
+		}
+		session.Sha256 = strings.ToLower(session.Sha256)
+	}
+
+	uid, _ := uuid.NewV4()
+	session.UID = uid.String()
+	session.ReceivedRanges = []UploadRange{}
+	session.CreateTime = time.Now()
+	session.ExpireTime = session.CreateTime.Add(config.Config.Upload.SessionTTL)
+
+	dir := filepath.Join(GetUploadDir(), session.UID)
+	if err := os.MkdirAll(dir, os.ModePerm); err != nil {
+		return err
+	}
+	f, err := os.Create(filepath.Join(dir, "content"))
+	if err != nil {
+		return err
+	}
+	// the content file is sparse until every chunk is received
+	err = f.Truncate(session.Size)
+	if e := f.Close(); err == nil {
+		err = e
+	}
+	if err == nil {
+		err = writeUploadSession(dir, session)
+	}
+	if err != nil {
+		_ = os.RemoveAll(dir)
+	}
+	return err
+}
+
+// GetUploadSession returns an upload session of an owner
+func GetUploadSession(uid string, owner string) (*UploadSession, error) {
+	dir, err := uploadSessionDir(uid)
+	if err != nil {
+		return nil, err
+	}
+	session, err := readUploadSession(dir)
+	if err != nil {
+		return nil, err
+	}
+	if session.Owner != owner || time.Now().After(session.ExpireTime) {
+		return nil, ErrUploadSessionNotFound
+	}
+	return session, nil
+}
+
+// mergeUploadRanges adds a range to sorted non overlapping ranges, merging the overlapping and adjacent ones
+func mergeUploadRanges(ranges []UploadRange, r UploadRange) []UploadRange {
+	ranges = append(append([]UploadRange{}, ranges...), r)
+	sort.Slice(ranges, func(i, j int) bool { return ranges[i].Start < ranges[j].Start })
+	merged := []UploadRange{ranges[0]}
+	for _, r := range ranges[1:] {
+		last := &merged[len(merged)-1]
+		if r.Start <= last.End {
+			if r.End > last.End {
+				last.End = r.End
+			}
+			continue
+		}
+		merged = append(merged, r)
+	}
+	return merged
+}
+
+// WriteUploadChunk writes a chunk read from r at offset in the content of an upload session. The chunk is received
+// in a file of its own and only copied into the content when its sha256 checksum matches, so that a corrupt chunk sent
+// again over received bytes does not overwrite them. A failed chunk can be sent again.
+func WriteUploadChunk(uid string, owner string, offset int64, r io.Reader, checksum string) (*UploadSession, error) {
+	session, err := GetUploadSession(uid, owner)
+	if err != nil {
+		return nil, err
+	}
+	if offset < 0 || offset >= session.Size {
+		return nil, fmt.Errorf("the offset %d is out of the upload of %d bytes", offset, session.Size)
+	}
+
+	maxChunkSize := session.Size - offset
+	if limit := config.Config.Upload.MaxChunkSize; limit > 0 && limit < maxChunkSize {
+		maxChunkSize = limit
+	}
+	dir := filepath.Join(GetUploadDir(), uid)
+	chunk, err := os.CreateTemp(dir, "chunk-*")
+	if err != nil {
+		return nil, err
+	}
+	defer os.Remove(chunk.Name())
+	defer chunk.Close()
+	h := sha256.New()
+	// one more byte is read to detect the chunks going past the limitation
+	n, err := io.Copy(io.MultiWriter(chunk, h), io.LimitReader(r, maxChunkSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if n > maxChunkSize {
+		return nil, fmt.Errorf("the chunk at offset %d exceeds %d bytes", offset, maxChunkSize)
+	}
+	if n == 0 {
+		return nil, fmt.Errorf("the chunk at offset %d is empty", offset)
+	}
+	if sum := hex.EncodeToString(h.Sum(nil)); !strings.EqualFold(sum, checksum) {
+		return nil, fmt.Errorf("the chunk at offset %d has the sha256 checksum %s instead of %s", offset, sum, checksum)
+	}
+	if _, err := chunk.Seek(0, io.SeekStart); err != nil {
+		return nil, err
+	}
+
+	// the verified chunk is copied under the session lock, so that the content is never written concurrently
+	unlock, err := LockUploadSession(uid)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+	if session, err = readUploadSession(dir); err != nil {
+		return nil, err
+	}
+	f, err := os.OpenFile(GetUploadContentPath(uid), os.O_WRONLY, 0600)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	if _, err := f.Seek(offset, io.SeekStart); err != nil {
+		return nil, err
+	}
+	if _, err := io.Copy(f, chunk); err != nil {
+		return nil, err
+	}
+	if err := f.Sync(); err != nil {
+		return nil, err
+	}
+	session.ReceivedRanges = mergeUploadRanges(session.ReceivedRanges, UploadRange{Start: offset, End: offset + n})
+	session.ExpireTime = time.Now().Add(config.Config.Upload.SessionTTL)
+	if err := writeUploadSession(dir, session); err != nil {
+		return nil, err
+	}
+	return session, nil
+}
+
+// VerifyUploadSession checks that every byte of an upload session was received and, when a checksum was given at
+// its creation, that the content matches it
+func VerifyUploadSession(session *UploadSession) error {
+	if !session.IsComplete() {
+		return fmt.Errorf("the upload is incomplete, %d of %d bytes were received", session.ReceivedSize(), session.Size)
+	}
+	if session.Sha256 == "" {
+		return nil
+	}
+	sum, err := hashFile(GetUploadContentPath(session.UID))
+	if err != nil {
+		return err
+	}
+	if sum != session.Sha256 {
+		return fmt.Errorf("the upload has the sha256 checksum %s instead of %s", sum, session.Sha256)
+	}
+	return nil
+}
+
+// DeleteUploadSession removes an upload session and its content
+func DeleteUploadSession(uid string) error {
+	dir, err := uploadSessionDir(uid)
+	if err != nil {
+		return err
+	}
+	return os.RemoveAll(dir)
+}
+
+// CleanupUploadSessions removes the expired upload sessions and returns their number
+func CleanupUploadSessions() (int, error) {
+	entries, err := os.ReadDir(GetUploadDir())
+	if errors.Is(err, fs.ErrNotExist) {
+		return 0, nil
+	} else if err != nil {
+		return 0, err
+	}
+
+	removed := 0
+	for _, entry := range entries {
+		dir := filepath.Join(GetUploadDir(), entry.Name())
+		expireTime := time.Time{}
+		if session, err := readUploadSession(dir); err == nil {
+			expireTime = session.ExpireTime
+		} else if info, err := entry.Info(); err == nil {
+			// a session without a readable state expires with its folder
+			expireTime = info.ModTime().Add(config.Config.Upload.SessionTTL)
+		}
+		if time.Now().Before(expireTime) {
+			continue
+		}
+		if err := os.RemoveAll(dir); err != nil {
+			return removed, err
+		}
+		removed++
+	}
+	return removed, nil
+}
@@ -80,9 +80,7 @@ func findDVCPaths(dir string) []string {
 func findModelFiles(dir string) []string {
 	var modelPaths []string = []string{}
 	_ = filepath.Walk(dir, func(path string, f os.FileInfo, err error) error {
-		if strings.HasSuffix(f.Name(), ".onnx") || strings.HasSuffix(f.Name(), ".pt") || strings.HasSuffix(f.Name(), ".bias") ||
-			strings.HasSuffix(f.Name(), ".weight") || strings.HasSuffix(f.Name(), ".ini") || strings.HasSuffix(f.Name(), ".bin") ||
-			strings.HasPrefix(f.Name(), "onnx__") {
+		if isModelFile(f.Name()) {
 			modelPaths = append(modelPaths, path)
 		}
 		return nil
@@ -90,6 +88,12 @@ func findModelFiles(dir string) []string {
 	return modelPaths
 }
 
+func isModelFile(name string) bool {
+	return strings.HasSuffix(name, ".onnx") || strings.HasSuffix(name, ".pt") || strings.HasSuffix(name, ".bias") ||
+		strings.HasSuffix(name, ".weight") || strings.HasSuffix(name, ".ini") || strings.HasSuffix(name, ".bin") ||
+		strings.HasSuffix(name, ".safetensors") || strings.HasPrefix(name, "onnx__")
+}
+
 func AddMissingTritonModelFolder(ctx context.Context, dir string) {
 	logger, _ := logger.GetZapLogger(ctx)
 	_ = filepath.Walk(dir, func(path string, f os.FileInfo, err error) error {
@@ -123,109 +127,28 @@ func getInferModelConfigPath(modelRepository string, tritonModels []datamodel.Tr
 	return modelPath
 }
 
-type CacheModel struct {
-	ModelRepo string `json:"model_repo"`
-	State     string `json:"state"`
-}
-
-// GitHubClone clones a repository from GitHub.
-func GitHubClone(dir string, instanceConfig datamodel.GitHubModelConfiguration, isWithLargeFile bool) error {
-	urlRepo := instanceConfig.Repository
-
-	// Check in the cache first.
-	var cacheModels []CacheModel
-	if config.Config.Cache.Model {
-		_ = os.MkdirAll(MODEL_CACHE_DIR, os.ModePerm)
-		if _, err := os.Stat(MODEL_CACHE_DIR + "/" + MODEL_CACHE_FILE); !os.IsNotExist(err) {
-			f, err := os.ReadFile(MODEL_CACHE_DIR + "/" + MODEL_CACHE_FILE)
-			if err != nil {
-				return err
-			}
-			if err := json.Unmarshal([]byte(f), &cacheModels); err != nil {
-				return err
-			}
-			for _, cacheModel := range cacheModels {
-				if cacheModel.ModelRepo != (instanceConfig.Repository + instanceConfig.Tag) {
-					continue
-				}
-				if cacheModel.State == "done" { // everything is cached.
-					return nil
-				} else if cacheModel.State == "without_large_file" && !isWithLargeFile { // the GitHub repo is being cached.
-					return nil
-				}
-			}
-		}
+// GetGitHubURL returns the clone URL of a GitHub repository, given as owner/name or as its URL
+func GetGitHubURL(repository string) string {
+	urlRepo := repository
+	if !strings.HasPrefix(urlRepo, "https://github.com") {
+		urlRepo = "https://github.com/" + urlRepo
 	}
-	if !isWithLargeFile || isWithLargeFile && !config.Config.Cache.Model {
-		if !strings.HasPrefix(urlRepo, "https://github.com") {
-			urlRepo = "https://github.com/" + urlRepo
-		}
-		if !strings.HasSuffix(urlRepo, ".git") {
-			urlRepo = urlRepo + ".git"
-		}
-
-		extraFlag := ""
-		if !isWithLargeFile {
-			extraFlag = "GIT_LFS_SKIP_SMUDGE=1"
-		}
-		cmd := exec.Command("/bin/sh", "-c", fmt.Sprintf("%s git clone -b %s %s %s", extraFlag, instanceConfig.Tag, urlRepo, dir))
-		err := cmd.Run()
-		if err != nil {
-			return err
-		}
+	if !strings.HasSuffix(urlRepo, ".git") {
+		urlRepo = urlRepo + ".git"
 	}
+	return urlRepo
+}
 
-	var f *os.File
-	var err error
-	if config.Config.Cache.Model {
-		f, err = os.Create(MODEL_CACHE_DIR + "/" + MODEL_CACHE_FILE)
-		if err != nil {
-			return err
-		}
-		defer f.Close()
+// GitHubClone clones a repository from GitHub, with its Git LFS and DVC files when isWithLargeFile is set. The
+// progress callback, if any, receives the bytes of the Git LFS objects then of the DVC files downloaded. The clone
+// is interrupted when ctx is done.
+func GitHubClone(ctx context.Context, dir string, instanceConfig datamodel.GitHubModelConfiguration, isWithLargeFile bool, progress ProgressFunc) error {
+	if err := GitClone(ctx, dir, GetGitHubURL(instanceConfig.Repository), instanceConfig.Tag, instanceConfig.CredentialRef, isWithLargeFile, progress); err != nil {
+		return err
 	}
-
 	if isWithLargeFile {
-		dvcPaths := findDVCPaths(dir)
-		for _, dvcPath := range dvcPaths {
-			cmd := exec.Command("/bin/sh", "-c", fmt.Sprintf("cd %s; dvc pull %s", dir, dvcPath))
-			err = cmd.Run()
-			if err != nil {
-				return err
-			}
-		}
-		if config.Config.Cache.Model {
-			for i, cacheModel := range cacheModels {
-				if cacheModel.ModelRepo == (instanceConfig.Repository + instanceConfig.Tag) {
-					cacheModels[i].State = "done"
-					break
-				}
-			}
-			b, err := json.Marshal(cacheModels)
-			if err != nil {
-				return err
-			}
-			if _, err := f.Write(b); err != nil {
-				return err
-			}
-		}
-	} else {
-		if config.Config.Cache.Model {
-			cacheFile := CacheModel{
-				ModelRepo: instanceConfig.Repository + instanceConfig.Tag,
-				State:     "without_large_file",
-			}
-			cacheModels = append(cacheModels, cacheFile)
-			b, err := json.Marshal(cacheModels)
-			if err != nil {
-				return err
-			}
-			if _, err := f.Write(b); err != nil {
-				return err
-			}
-		}
+		return PullDVCFiles(ctx, dir, progress)
 	}
-
 	return nil
 }
 
@@ -266,6 +189,33 @@ func CopyModelFileToModelRepository(modelRepository string, dir string, tritonMo
 	return nil
 }
 
+// PullDVCFiles pulls the large files tracked by DVC in a cloned repository, the progress callback, if any, receives
+// the bytes written into the repository against the sizes recorded in the .dvc files
+func PullDVCFiles(ctx context.Context, dir string, progress ProgressFunc) error {
+	dvcPaths := findDVCPaths(dir)
+	if len(dvcPaths) == 0 {
+		return nil
+	}
+	var total int64
+	for _, dvcPath := range dvcPaths {
+		total += getDVCSize(dvcPath)
+	}
+	stop := watchDownload(dir, total, progress)
+	for _, dvcPath := range dvcPaths {
+		cmd := exec.CommandContext(ctx, "dvc", "pull", dvcPath)
+		cmd.Dir = dir
+		if err := cmd.Run(); err != nil {
+			stop()
+			return err
+		}
+	}
+	stop()
+	if progress != nil {
+		progress(total, total)
+	}
+	return nil
+}
+
 type Tag struct {
 	Name string `json:"name"`
 }
@@ -275,16 +225,18 @@ type GitHubInfo struct {
 	Tags        []Tag
 }
 
-func GetGitHubRepoInfo(repo string) (*GitHubInfo, error) {
+// GetGitHubRepoInfo gets the visibility and the tags of a GitHub repository, a private one requires the token of
+// a credential
+func GetGitHubRepoInfo(repo string, credentialRef string) (*GitHubInfo, error) {
 	if repo == "" {
 		return &GitHubInfo{}, fmt.Errorf("invalid repo URL")
 	}
-
-	resp, err := http.Get(fmt.Sprintf("https://api.github.com/repos/%v", repo))
+	credential, err := GetGitCredential(credentialRef)
 	if err != nil {
 		return &GitHubInfo{}, err
 	}
-	body, err := io.ReadAll(resp.Body)
+
+	body, err := getGitHubAPI(fmt.Sprintf("https://api.github.com/repos/%v", repo), credential)
 	if err != nil {
 		return &GitHubInfo{}, err
 	}
@@ -293,11 +245,7 @@ func GetGitHubRepoInfo(repo string) (*GitHubInfo, error) {
 	if err != nil {
 		return &GitHubInfo{}, err
 	}
-	resp, err = http.Get(fmt.Sprintf("https://api.github.com/repos/%v/tags", repo))
-	if err != nil {
-		return &GitHubInfo{}, err
-	}
-	body, err = io.ReadAll(resp.Body)
+	body, err = getGitHubAPI(fmt.Sprintf("https://api.github.com/repos/%v/tags", repo), credential)
 	if err != nil {
 		return &GitHubInfo{}, err
 	}
@@ -312,6 +260,25 @@ func GetGitHubRepoInfo(repo string) (*GitHubInfo, error) {
 	return &githubRepoInfo, nil
 }
 
+func getGitHubAPI(url string, credential *config.GitCredentialConfig) ([]byte, error) {
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	if credential != nil && credential.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+credential.Token)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("github api request %s failed with status %s", url, resp.Status)
+	}
+	return io.ReadAll(resp.Body)
+}
+
 func RemoveModelRepository(modelRepositoryRoot string, namespace string, modelName string, instanceName string) {
 	path := fmt.Sprintf("%v/%v#%v#*#%v", modelRepositoryRoot, namespace, modelName, instanceName)
 	if err := ValidateFilePath(path); err != nil {
@@ -541,7 +508,10 @@ func ArtiVCGetTags(dir string, config datamodel.ArtiVCModelConfiguration) ([]str
 	}
 }
 
-func ArtiVCClone(dir string, modelConfig datamodel.ArtiVCModelConfiguration, withLargeFiles bool) error {
+// ArtiVCClone gets the tag of an ArtiVC repository into dir, the large files are ignored unless withLargeFiles is set.
+// ArtiVC does not tell the size of a tag, the progress callback, if any, only receives the bytes downloaded. The
+// download is interrupted when ctx is done.
+func ArtiVCClone(ctx context.Context, dir string, modelConfig datamodel.ArtiVCModelConfiguration, withLargeFiles bool, progress ProgressFunc) error {
 	if err := os.MkdirAll(dir, os.ModePerm); err != nil {
 		return err
 	}
@@ -562,8 +532,10 @@ func ArtiVCClone(dir string, modelConfig datamodel.ArtiVCModelConfiguration, wit
 		}
 
 		// download other source file such as .py, config.pbtxt
-		cmd = exec.Command("/bin/sh", "-c", fmt.Sprintf("GOOGLE_APPLICATION_CREDENTIALS=%s avc get -o %s %s@%s", credentialFile, dir, url, modelConfig.Tag))
+		cmd = exec.CommandContext(ctx, "/bin/sh", "-c", fmt.Sprintf("exec env GOOGLE_APPLICATION_CREDENTIALS=%s avc get -o %s %s@%s", credentialFile, dir, url, modelConfig.Tag))
+		stop := watchDownload(dir, 0, progress)
 		err = cmd.Run()
+		stop()
 		if err != nil {
 			return err
 		}
@@ -574,28 +546,15 @@ func ArtiVCClone(dir string, modelConfig datamodel.ArtiVCModelConfiguration, wit
 	}
 }
 
-func HuggingFaceClone(dir string, modelConfig datamodel.HuggingFaceModelConfiguration) error {
-	if err := os.MkdirAll(dir, os.ModePerm); err != nil {
-		return err
-	}
-	cmd := exec.Command("/bin/sh", "-c", fmt.Sprintf("GIT_LFS_SKIP_SMUDGE=1 git clone https://huggingface.co/%s %s", modelConfig.RepoId, dir))
-	if err := cmd.Run(); err != nil {
-		return err
-	}
-	return nil
-}
+var artiVCCommitPattern = regexp.MustCompile(`^[0-9a-f]{40}$`)
 
-func HuggingFaceExport(dir string, modelConfig datamodel.HuggingFaceModelConfiguration, modelID string) error {
-	// export model to folder structure similar with triton to support copy the model into model repository later
-	if err := os.MkdirAll(fmt.Sprintf("%s/%s-infer/1", dir, modelID), os.ModePerm); err != nil {
-		return err
-	}
-	// atol 0.001 mean that accept difference with 0.1%
-	cmd := exec.Command("/bin/sh", "-c", fmt.Sprintf("python3 -m transformers.onnx --feature=image-classification --atol 0.001 --model=%s %s/%s-infer/1", modelConfig.RepoId, dir, modelID))
-	if err := cmd.Run(); err != nil {
-		return err
+// ResolveArtiVCRevision returns the revision of the version of an ArtiVC repository, only a commit hash is immutable
+// so a tag resolves to no revision
+func ResolveArtiVCRevision(tag string) string {
+	if artiVCCommitPattern.MatchString(tag) {
+		return tag
 	}
-	return nil
+	return ""
 }
 
 func UpdateConfigModelName(filePath string, oldModelName string, newModelName string) error {
@@ -623,11 +582,17 @@ func UpdateModelName(filePath string, oldModelName string, newModelName string)
 	return os.WriteFile(filePath, fileData, 0o600)
 }
 
-func GenerateHuggingFaceModel(confDir string, dest string, modelID string) error {
+// GenerateHuggingFaceModel generates the Triton models of a Hugging Face repository from the template of its pipeline,
+// the configuration files of the repository in confDir are copied next to the model code of the template
+func GenerateHuggingFaceModel(confDir string, dest string, modelID string, pipeline string) error {
+	template, err := GetHuggingFaceTemplate(pipeline)
+	if err != nil {
+		return err
+	}
 	if err := os.Mkdir(dest, os.ModePerm); err != nil {
 		return err
 	}
-	cmd := exec.Command("/bin/sh", "-c", fmt.Sprintf("cp -rf assets/huggingface-vit-template/* %s", dest))
+	cmd := exec.Command("/bin/sh", "-c", fmt.Sprintf("cp -rf %s/* %s", template.Dir, dest))
 	if err := cmd.Run(); err != nil {
 		return err
 	}
@@ -653,10 +618,21 @@ func GenerateHuggingFaceModel(confDir string, dest string, modelID string) error
 		return err
 	}
 
-	cmd = exec.Command("/bin/sh", "-c", fmt.Sprintf("cp %s/*.json %s/pre/1", confDir, dest))
+	// the ONNX templates preprocess with the feature extractor, the Python backend ones load the whole repository
+	confDest := fmt.Sprintf("%s/pre/1", dest)
+	if template.OnnxFeature == "" {
+		confDest = fmt.Sprintf("%s/%s-infer/1", dest, modelID)
+	}
+	cmd = exec.Command("/bin/sh", "-c", fmt.Sprintf("cp %s/*.json %s", confDir, confDest))
 	if err := cmd.Run(); err != nil {
 		return err
 	}
+	if template.OnnxFeature == "" {
+		// tokenizers may also come as vocabulary or sentencepiece files
+		for _, pattern := range []string{"*.txt", "*.model"} {
+			_ = exec.Command("/bin/sh", "-c", fmt.Sprintf("cp %s/%s %s 2>/dev/null", confDir, pattern, confDest)).Run()
+		}
+	}
 
 	if _, err := os.Stat(fmt.Sprintf("%s/README.md", confDir)); err != nil {
 		return fmt.Errorf("there is no README file")
@@ -687,6 +663,39 @@ func HasModelWeightFile(modelRepository string, tritonModels []datamodel.TritonM
 	return false
 }
 
+// RemoveModelFiles removes the weight files of Triton models from the model repository, so that a model whose files
+// were partially copied is fetched again by its next deployment
+func RemoveModelFiles(modelRepository string, tritonModelNames []string) error {
+	for _, name := range tritonModelNames {
+		dir := filepath.Join(modelRepository, name)
+		if _, err := os.Stat(dir); err != nil {
+			continue
+		}
+		for _, modelFile := range findModelFiles(dir) {
+			if err := os.Remove(modelFile); err != nil && !os.IsNotExist(err) {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// RemoveTritonModelFolders removes the folders of Triton models from the model repository, the names of the Triton
+// models are checked to stay in the model repository
+func RemoveTritonModelFolders(modelRepository string, tritonModelNames []string) error {
+	root := filepath.Clean(modelRepository) + string(os.PathSeparator)
+	for _, name := range tritonModelNames {
+		dir := filepath.Join(modelRepository, name)
+		if !strings.HasPrefix(dir, root) {
+			return fmt.Errorf("triton model %s is outside of the model repository", name)
+		}
+		if err := os.RemoveAll(dir); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func updateModelConfigModel(configFilePath string, oldStr string, newStr string) error {
 	if _, err := os.Stat(configFilePath); err != nil {
 		return err
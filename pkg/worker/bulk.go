@@ -0,0 +1,153 @@
+package worker
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/gofrs/uuid"
+	"go.temporal.io/sdk/temporal"
+	"go.temporal.io/sdk/workflow"
+
+	"github.com/instill-ai/model-backend/pkg/datamodel"
+)
+
+// DefaultBulkConcurrency is the number of models a bulk operation works on at once when not given
+const DefaultBulkConcurrency = 5
+
+// BulkParams are the parameters of a bulk operation, the action to run on the models it selected and how many of
+// them run at once. The models are given by their UID and ID only to keep the input of the workflow small, the bulk
+// operations started before are given by their Operations.
+type BulkParams struct {
+	Action      string
+	Owner       string
+	Models      []BulkModel
+	Operations  []OperationParams
+	Concurrency int
+}
+
+// BulkModel is a model selected by a bulk operation
+type BulkModel struct {
+	UID uuid.UUID
+	ID  string
+}
+
+// getBulkOperations returns the operation to run on every model selected by a bulk operation
+func getBulkOperations(param *BulkParams) []OperationParams {
+	if len(param.Models) == 0 {
+		return param.Operations
+	}
+	operations := make([]OperationParams, 0, len(param.Models))
+	for _, m := range param.Models {
+		model := datamodel.Model{ID: m.ID}
+		model.UID = m.UID
+		operations = append(operations, OperationParams{
+			Model:            model,
+			Owner:            param.Owner,
+			Kind:             param.Action,
+			Memo:             OperationMemo(param.Owner, &model),
+			SearchAttributes: OperationSearchAttributes(param.Owner, &model),
+		})
+	}
+	return operations
+}
+
+// BulkModelResult is the outcome of a bulk operation on a model, succeeded, skipped or failed
+type BulkModelResult struct {
+	Model     string `json:"model"`
+	State     string `json:"state"`
+	Operation string `json:"operation,omitempty"`
+	Skipped   string `json:"skipped,omitempty"`
+	Error     string `json:"error,omitempty"`
+}
+
+// BulkReport is the response of a bulk operation, with the outcome on every model in the order they were selected
+type BulkReport struct {
+	Action    string            `json:"action"`
+	Succeeded int               `json:"succeeded"`
+	Skipped   int               `json:"skipped"`
+	Failed    int               `json:"failed"`
+	Results   []BulkModelResult `json:"results"`
+}
+
+// getFailureMessage returns the message of the failure of an operation on a model without the wrapping of the child
+// workflow and the activity
+func getFailureMessage(err error) string {
+	var applicationErr *temporal.ApplicationError
+	var canceledErr *temporal.CanceledError
+	switch {
+	case errors.As(err, &applicationErr):
+		return applicationErr.Message()
+	case errors.As(err, &canceledErr):
+		return "operation was canceled"
+	default:
+		return err.Error()
+	}
+}
+
+// BulkModelWorkflow runs an operation on many models as child workflows, at most the concurrency of them at once. A
+// failure on a model is recorded in its result and does not stop the others.
+func (w *worker) BulkModelWorkflow(ctx workflow.Context, param *BulkParams) (*BulkReport, error) {
+	logger := workflow.GetLogger(ctx)
+	logger.Info("BulkModelWorkflow started")
+
+	concurrency := param.Concurrency
+	if concurrency <= 0 {
+		concurrency = DefaultBulkConcurrency
+	}
+
+	operations := getBulkOperations(param)
+	report := &BulkReport{
+		Action:  param.Action,
+		Results: make([]BulkModelResult, len(operations)),
+	}
+	workflowID := workflow.GetInfo(ctx).WorkflowExecution.ID
+	selector := workflow.NewSelector(ctx)
+	running := 0
+	for i := range operations {
+		if running >= concurrency {
+			selector.Select(ctx)
+			running--
+		}
+
+		i := i
+		operation := operations[i]
+		report.Results[i] = BulkModelResult{Model: fmt.Sprintf("models/%s", operation.Model.ID)}
+		cctx := workflow.WithChildOptions(ctx, workflow.ChildWorkflowOptions{
+			WorkflowID: fmt.Sprintf("%s-%s", workflowID, operation.Model.UID.String()),
+			TaskQueue:  TaskQueue,
+		})
+		selector.AddFuture(workflow.ExecuteChildWorkflow(cctx, w.ModelOperationWorkflow, &operation), func(f workflow.Future) {
+			var result OperationResult
+			switch err := f.Get(ctx, &result); {
+			case err != nil:
+				report.Results[i].State = "failed"
+				report.Results[i].Error = getFailureMessage(err)
+			case result.Skipped != "":
+				report.Results[i].State = "skipped"
+				report.Results[i].Skipped = result.Skipped
+			default:
+				report.Results[i].State = "succeeded"
+				report.Results[i].Operation = result.Operation
+			}
+		})
+		running++
+	}
+	for ; running > 0; running-- {
+		selector.Select(ctx)
+	}
+
+	for _, result := range report.Results {
+		switch result.State {
+		case "succeeded":
+			report.Succeeded++
+		case "skipped":
+			report.Skipped++
+		default:
+			report.Failed++
+		}
+	}
+
+	logger.Info(fmt.Sprintf("BulkModelWorkflow completed, %d succeeded, %d skipped and %d failed", report.Succeeded, report.Skipped, report.Failed))
+
+	return report, nil
+}
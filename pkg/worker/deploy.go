@@ -0,0 +1,642 @@
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"go.opentelemetry.io/otel/trace"
+	"go.temporal.io/sdk/activity"
+	"go.temporal.io/sdk/temporal"
+
+	"github.com/instill-ai/model-backend/config"
+	"github.com/instill-ai/model-backend/pkg/datamodel"
+	"github.com/instill-ai/model-backend/pkg/triton"
+	"github.com/instill-ai/model-backend/pkg/util"
+
+	controllerPB "github.com/instill-ai/protogen-go/model/controller/v1alpha"
+	modelPB "github.com/instill-ai/protogen-go/model/model/v1alpha"
+)
+
+// ModelStateParams are the parameters of the activity publishing the state of a model
+type ModelStateParams struct {
+	Model datamodel.Model
+	Owner string
+	State modelPB.Model_State
+}
+
+// The markers written into the staging folder of a model once a deploy stage is done with it
+const (
+	fetchedMarker = ".fetched"
+	stagedMarker  = ".staged"
+)
+
+// missingFetchedFilesError is the type of the error of a stage not finding the files of the fetch, which ran on
+// another worker host
+const missingFetchedFilesError = "MissingFetchedFiles"
+
+// heartbeatInterval is how often the long running deploy stages record a heartbeat
+const heartbeatInterval = 10 * time.Second
+
+// getDeployStagingDir returns where the files of a model are fetched to before being staged into the model
+// repository, it is named after the model so that a retried deployment finds the files fetched by a failed one
+func getDeployStagingDir(modelUID uuid.UUID) string {
+	return fmt.Sprintf("%s/%s", util.TMP_DIR, uuid.NewV5(modelUID, "deploy").String())
+}
+
+// startHeartbeat records a heartbeat with the details returned by details, if any, at every heartbeat interval
+// until it is stopped, so that a stage blocked on a long download or load is not timed out
+func startHeartbeat(ctx context.Context, details func() interface{}) func() {
+	done := make(chan struct{})
+	go func() {
+		ticker := time.NewTicker(heartbeatInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if details != nil {
+					activity.RecordHeartbeat(ctx, details())
+				} else {
+					activity.RecordHeartbeat(ctx)
+				}
+			}
+		}
+	}()
+	return func() { close(done) }
+}
+
+func (w *worker) getDeployModel(param *ModelParams) (*datamodel.Model, *datamodel.ModelDefinition, []datamodel.TritonModel, error) {
+	dbModel, err := w.repository.GetModelByUID(param.Owner, param.Model.UID, modelPB.View_VIEW_FULL)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	modelDef, err := w.repository.GetModelDefinitionByUID(dbModel.ModelDefinitionUid)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	tritonModels, err := w.repository.GetTritonModels(dbModel.UID)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	return &dbModel, &modelDef, tritonModels, nil
+}
+
+// needsFetch tells whether the weights of a model have to be downloaded from its source, the models of the
+// integration tests are never downloaded but the Hugging Face one which is exported from the local assets
+func needsFetch(modelDefinitionID string, tritonModels []datamodel.TritonModel) bool {
+	switch modelDefinitionID {
+	case "huggingface":
+	case "github", "artivc", "git", "s3":
+		if config.Config.Server.ItMode.Enabled {
+			return false
+		}
+	default:
+		return false
+	}
+	return !util.HasModelWeightFile(config.Config.TritonServer.ModelStore, tritonModels)
+}
+
+// resolveRevision resolves the revision a model artifact is cached at, the source is only queried when the model
+// cache is enabled
+func resolveRevision(resolve func() (string, error)) (string, error) {
+	if !config.Config.Cache.Model {
+		return "", nil
+	}
+	return resolve()
+}
+
+// fetchModel downloads the model package of a model from its source into dir
+func (w *worker) fetchModel(ctx context.Context, dbModel *datamodel.Model, modelDefinitionID string, dir string, progress util.ProgressFunc) error {
+	switch modelDefinitionID {
+	case "github":
+		var modelConfig datamodel.GitHubModelConfiguration
+		if err := json.Unmarshal(dbModel.Configuration, &modelConfig); err != nil {
+			return err
+		}
+		revision, err := resolveRevision(func() (string, error) {
+			return util.ResolveGitRevision(ctx, util.GetGitHubURL(modelConfig.Repository), modelConfig.Tag, modelConfig.CredentialRef)
+		})
+		if err != nil {
+			return err
+		}
+		_, err = util.FetchModelArtifact(ctx, "github:"+modelConfig.Repository, revision, dir, func(dir string) error {
+			return util.GitHubClone(ctx, dir, modelConfig, true, progress)
+		})
+		return err
+	case "huggingface":
+		var modelConfig datamodel.HuggingFaceModelConfiguration
+		if err := json.Unmarshal(dbModel.Configuration, &modelConfig); err != nil {
+			return err
+		}
+		if config.Config.Server.ItMode.Enabled { // use local model to remove internet connection issue while integration testing
+			return util.HuggingFaceExport(ctx, dir, datamodel.HuggingFaceModelConfiguration{
+				RepoId: "assets/tiny-vit-random",
+			}, dbModel.ID, progress)
+		}
+		// the artifact is exported under the template name so that the models of every owner share it
+		source, revision := util.GetHuggingFaceArtifact(modelConfig)
+		if _, err := util.FetchModelArtifact(ctx, source, revision, dir, func(dir string) error {
+			return util.HuggingFaceExport(ctx, dir, modelConfig, "huggingface", progress)
+		}); err != nil {
+			return err
+		}
+		return os.Rename(dir+"/huggingface-infer", fmt.Sprintf("%s/%s-infer", dir, dbModel.ID))
+	case "artivc":
+		var modelConfig datamodel.ArtiVCModelConfiguration
+		if err := json.Unmarshal(dbModel.Configuration, &modelConfig); err != nil {
+			return err
+		}
+		_, err := util.FetchModelArtifact(ctx, "artivc:"+modelConfig.Url, util.ResolveArtiVCRevision(modelConfig.Tag), dir, func(dir string) error {
+			return util.ArtiVCClone(ctx, dir, modelConfig, true, progress)
+		})
+		return err
+	case "git":
+		var modelConfig datamodel.GitModelConfiguration
+		if err := json.Unmarshal(dbModel.Configuration, &modelConfig); err != nil {
+			return err
+		}
+		revision, err := resolveRevision(func() (string, error) {
+			return util.ResolveGitRevision(ctx, modelConfig.Url, modelConfig.Tag, modelConfig.CredentialRef)
+		})
+		if err != nil {
+			return err
+		}
+		_, err = util.FetchModelArtifact(ctx, "git:"+modelConfig.Url, revision, dir, func(dir string) error {
+			if err := util.GitClone(ctx, dir, modelConfig.Url, modelConfig.Tag, modelConfig.CredentialRef, true, progress); err != nil {
+				return err
+			}
+			return util.PullDVCFiles(ctx, dir, progress)
+		})
+		return err
+	case "s3":
+		var modelConfig datamodel.S3ModelConfiguration
+		if err := json.Unmarshal(dbModel.Configuration, &modelConfig); err != nil {
+			return err
+		}
+		return util.S3Download(ctx, dir, modelConfig, true)
+	}
+	return nil
+}
+
+// DeployModelActivity fetches, stages and loads a model in one activity and publishes its state, for the deployments
+// started before the deploy stages, which replay it
+func (w *worker) DeployModelActivity(ctx context.Context, param *ModelParams) error {
+
+	ctx, span := tracer.Start(ctx, "DeployModelActivity",
+		trace.WithSpanKind(trace.SpanKindServer))
+	defer span.End()
+
+	logger := activity.GetLogger(ctx)
+
+	logger.Info("DeployModelActivity started")
+
+	state := modelPB.Model_STATE_ONLINE
+	var err error
+	for _, stage := range []func(context.Context, *ModelParams) error{w.FetchModelActivity, w.StageModelActivity, w.LoadModelActivity} {
+		if err = stage(ctx, param); err != nil {
+			state = modelPB.Model_STATE_ERROR
+			break
+		}
+	}
+	if e := w.PublishModelStateActivity(ctx, &ModelStateParams{Model: param.Model, Owner: param.Owner, State: state}); e != nil && err == nil {
+		err = e
+	}
+	if err != nil {
+		return err
+	}
+
+	logger.Info("DeployModelActivity completed")
+
+	return nil
+}
+
+// CheckCompatibilityActivity checks the Triton models of a model against the backends and the extensions of the
+// Triton server before anything is downloaded or loaded, an incompatible model fails the deployment without retry
+func (w *worker) CheckCompatibilityActivity(ctx context.Context, param *ModelParams) error {
+
+	ctx, span := tracer.Start(ctx, "CheckCompatibilityActivity",
+		trace.WithSpanKind(trace.SpanKindServer))
+	defer span.End()
+
+	logger := activity.GetLogger(ctx)
+
+	logger.Info("CheckCompatibilityActivity started")
+
+	dbModel, _, tritonModels, err := w.getDeployModel(param)
+	if err != nil {
+		return err
+	}
+
+	serverMetadata := w.triton.ServerMetadataRequest()
+	if serverMetadata == nil {
+		return fmt.Errorf("unable to get the triton server metadata")
+	}
+
+	hasDeployConfig := len(dbModel.DeployConfig) > 0 && string(dbModel.DeployConfig) != "null"
+	if err := util.CheckTritonCompatibility(config.Config.TritonServer.ModelStore, tritonModels, modelPB.Model_Task(dbModel.Task), hasDeployConfig, serverMetadata.Extensions, config.Config.TritonServer.Backends); err != nil {
+		return temporal.NewNonRetryableApplicationError(fmt.Sprintf("%s (triton %s)", err, serverMetadata.Version), "IncompatibleModel", err)
+	}
+
+	logger.Info("CheckCompatibilityActivity completed")
+
+	return nil
+}
+
+// FetchModelActivity downloads the weights of a model into its staging folder. The files fetched by a previous
+// attempt are kept, a partial download is started over.
+func (w *worker) FetchModelActivity(ctx context.Context, param *ModelParams) error {
+
+	ctx, span := tracer.Start(ctx, "FetchModelActivity",
+		trace.WithSpanKind(trace.SpanKindServer))
+	defer span.End()
+
+	logger := activity.GetLogger(ctx)
+
+	logger.Info("FetchModelActivity started")
+
+	dbModel, modelDef, tritonModels, err := w.getDeployModel(param)
+	if err != nil {
+		return err
+	}
+
+	dir := getDeployStagingDir(dbModel.UID)
+	if _, err := os.Stat(filepath.Join(dir, fetchedMarker)); err == nil {
+		logger.Info("FetchModelActivity skipped, the model files were fetched by a previous attempt")
+		return nil
+	}
+	if !needsFetch(modelDef.ID, tritonModels) {
+		logger.Info("FetchModelActivity skipped, the model files are in the model repository")
+		return nil
+	}
+
+	if err := os.RemoveAll(dir); err != nil {
+		return err
+	}
+	release, err := registerStagingDir(ctx, dir, nil)
+	if err != nil {
+		return err
+	}
+	defer release()
+	reporter := w.newProgressReporter(ctx, dbModel.UID, OperationStages["FetchModelActivity"])
+	stop := startHeartbeat(ctx, reporter.details)
+	defer stop()
+	if err := w.fetchModel(ctx, dbModel, modelDef.ID, dir, reporter.download); err != nil {
+		_ = os.RemoveAll(dir)
+		return err
+	}
+	// the fetchers stop when the deployment is canceled, what a fetch completed before the cancel is dropped as well
+	if ctx.Err() != nil {
+		_ = os.RemoveAll(dir)
+		return ctx.Err()
+	}
+	if err := os.WriteFile(filepath.Join(dir, fetchedMarker), nil, 0644); err != nil {
+		return err
+	}
+	reporter.done()
+
+	logger.Info("FetchModelActivity completed")
+
+	return nil
+}
+
+// StageModelActivity copies the fetched weights of a model into the model repository and removes its staging
+// folder, the configurations of the exported ONNX models are updated only once
+func (w *worker) StageModelActivity(ctx context.Context, param *ModelParams) error {
+
+	ctx, span := tracer.Start(ctx, "StageModelActivity",
+		trace.WithSpanKind(trace.SpanKindServer))
+	defer span.End()
+
+	logger := activity.GetLogger(ctx)
+
+	logger.Info("StageModelActivity started")
+
+	dbModel, modelDef, tritonModels, err := w.getDeployModel(param)
+	if err != nil {
+		return err
+	}
+
+	dir := getDeployStagingDir(dbModel.UID)
+	if _, err := os.Stat(filepath.Join(dir, fetchedMarker)); err != nil {
+		if !needsFetch(modelDef.ID, tritonModels) {
+			logger.Info("StageModelActivity skipped, the model files are in the model repository")
+			return nil
+		}
+		return temporal.NewApplicationError(fmt.Sprintf("the fetched files of model %s are not on this worker host", dbModel.ID), missingFetchedFilesError)
+	}
+
+	release, err := registerStagingDir(ctx, dir, tritonModels)
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	if _, err := os.Stat(filepath.Join(dir, stagedMarker)); err != nil {
+		stop := startHeartbeat(ctx, nil)
+		defer stop()
+		if err := util.CopyModelFileToModelRepository(config.Config.TritonServer.ModelStore, dir, tritonModels); err != nil {
+			return err
+		}
+
+		// only the ONNX exported models have their input and output dimensions updated
+		if modelDef.ID == "huggingface" {
+			var modelConfig datamodel.HuggingFaceModelConfiguration
+			if err := json.Unmarshal(dbModel.Configuration, &modelConfig); err != nil {
+				return err
+			}
+			template, err := util.GetHuggingFaceTemplate(modelConfig.Pipeline)
+			if err != nil {
+				return temporal.NewNonRetryableApplicationError(err.Error(), "UnsupportedPipeline", err)
+			}
+			if template.OnnxFeature != "" {
+				if err := util.UpdateModelConfig(config.Config.TritonServer.ModelStore, tritonModels); err != nil {
+					return err
+				}
+			}
+		}
+		if err := os.WriteFile(filepath.Join(dir, stagedMarker), nil, 0644); err != nil {
+			return err
+		}
+	}
+
+	if err := os.RemoveAll(dir); err != nil {
+		return err
+	}
+
+	logger.Info("StageModelActivity completed")
+
+	return nil
+}
+
+// ValidateModelActivity checks the Triton configurations of a model and its deploy configuration before any of its
+// Triton models is loaded, an invalid model fails the deployment without retry
+func (w *worker) ValidateModelActivity(ctx context.Context, param *ModelParams) error {
+
+	ctx, span := tracer.Start(ctx, "ValidateModelActivity",
+		trace.WithSpanKind(trace.SpanKindServer))
+	defer span.End()
+
+	logger := activity.GetLogger(ctx)
+
+	logger.Info("ValidateModelActivity started")
+
+	dbModel, modelDef, tritonModels, err := w.getDeployModel(param)
+	if err != nil {
+		return err
+	}
+
+	// the Hugging Face models are exported from a template, their package is not linted when they are created either
+	if modelDef.ID != "huggingface" {
+		if violations := util.ValidateModelPackage(config.Config.TritonServer.ModelStore, dbModel.Owner, dbModel); len(violations) > 0 {
+			descriptions := []string{}
+			for _, v := range violations {
+				descriptions = append(descriptions, fmt.Sprintf("%s: %s", v.Subject, v.Description))
+			}
+			return temporal.NewNonRetryableApplicationError(fmt.Sprintf("invalid model package: %s", strings.Join(descriptions, "; ")), "InvalidModelPackage", nil)
+		}
+	}
+
+	if len(dbModel.DeployConfig) > 0 && string(dbModel.DeployConfig) != "null" {
+		var deployConfig datamodel.ModelDeployConfiguration
+		if err := json.Unmarshal(dbModel.DeployConfig, &deployConfig); err != nil {
+			return temporal.NewNonRetryableApplicationError(err.Error(), "InvalidDeployConfig", err)
+		}
+		if err := util.ValidateModelDeployConfig(config.Config.TritonServer.ModelStore, tritonModels, dbModel.Task, deployConfig); err != nil {
+			return temporal.NewNonRetryableApplicationError(err.Error(), "InvalidDeployConfig", err)
+		}
+	}
+
+	logger.Info("ValidateModelActivity completed")
+
+	return nil
+}
+
+// LoadModelActivity loads the Triton models of a model, the ensemble model last. The number of Triton models loaded
+// is recorded in the heartbeat details so that a retried attempt skips those still ready, and reported as progress.
+func (w *worker) LoadModelActivity(ctx context.Context, param *ModelParams) error {
+
+	ctx, span := tracer.Start(ctx, "LoadModelActivity",
+		trace.WithSpanKind(trace.SpanKindServer))
+	defer span.End()
+
+	logger := activity.GetLogger(ctx)
+
+	logger.Info("LoadModelActivity started")
+
+	dbModel, _, tritonModels, err := w.getDeployModel(param)
+	if err != nil {
+		return err
+	}
+
+	tEnsembleModel, _ := w.repository.GetTritonEnsembleModel(dbModel.UID)
+	order := []datamodel.TritonModel{}
+	for _, tModel := range tritonModels {
+		if tEnsembleModel.Name == "" || tEnsembleModel.Name != tModel.Name { // load ensemble model last.
+			order = append(order, tModel)
+		}
+	}
+	if tEnsembleModel.Name != "" {
+		order = append(order, tEnsembleModel)
+	}
+
+	var previous OperationProgress
+	if activity.HasHeartbeatDetails(ctx) {
+		_ = activity.GetHeartbeatDetails(ctx, &previous)
+	}
+	reporter := w.newProgressReporter(ctx, dbModel.UID, OperationStages["LoadModelActivity"])
+	reporter.load(0, len(order))
+	stop := startHeartbeat(ctx, reporter.details)
+	defer stop()
+	for i, tModel := range order {
+		if i < previous.LoadedModels {
+			if resp := w.triton.ModelReadyRequest(ctx, tModel.Name, fmt.Sprint(tModel.Version)); resp != nil && resp.Ready {
+				reporter.load(i+1, len(order))
+				continue
+			}
+		}
+		if err := w.loadTritonModel(*dbModel, tModel.Name); err != nil {
+			return err
+		}
+		reporter.load(i+1, len(order))
+		activity.RecordHeartbeat(ctx, reporter.details())
+	}
+
+	logger.Info("LoadModelActivity completed")
+
+	return nil
+}
+
+// WarmupModelActivity runs an inference on a sample input through the ensemble of a loaded model, so that the first
+// request does not pay for the lazy initialization and a model Triton reports ready but cannot infer fails the
+// deployment. It runs when the deploy configuration of the model or else the server default turns it on.
+func (w *worker) WarmupModelActivity(ctx context.Context, param *ModelParams) error {
+
+	ctx, span := tracer.Start(ctx, "WarmupModelActivity",
+		trace.WithSpanKind(trace.SpanKindServer))
+	defer span.End()
+
+	logger := activity.GetLogger(ctx)
+
+	logger.Info("WarmupModelActivity started")
+
+	dbModel, _, _, err := w.getDeployModel(param)
+	if err != nil {
+		return err
+	}
+
+	var warmup *datamodel.ModelDeployWarmup
+	if len(dbModel.DeployConfig) > 0 && string(dbModel.DeployConfig) != "null" {
+		var deployConfig datamodel.ModelDeployConfiguration
+		if err := json.Unmarshal(dbModel.DeployConfig, &deployConfig); err != nil {
+			return temporal.NewNonRetryableApplicationError(err.Error(), "InvalidDeployConfig", err)
+		}
+		warmup = deployConfig.Warmup
+	}
+	enabled := config.Config.Warmup.Enabled
+	if warmup != nil && warmup.Enabled != nil {
+		enabled = *warmup.Enabled
+	}
+	if !enabled {
+		logger.Info("WarmupModelActivity skipped, the warm-up is turned off")
+		return nil
+	}
+
+	tEnsembleModel, err := w.repository.GetTritonEnsembleModel(dbModel.UID)
+	if err != nil {
+		logger.Info("WarmupModelActivity skipped, the model has no ensemble to infer with")
+		return nil
+	}
+	task := modelPB.Model_Task(dbModel.Task)
+	sample, err := util.ReadWarmupSample(config.Config.TritonServer.ModelStore, tEnsembleModel.Name, config.Config.Warmup.SampleDir, task, warmup)
+	if err != nil {
+		return temporal.NewNonRetryableApplicationError(fmt.Sprintf("unable to read the warm-up sample: %s", err), "WarmupFailed", err)
+	}
+	if sample == nil {
+		logger.Info(fmt.Sprintf("WarmupModelActivity skipped, there is no warm-up sample for %s", task))
+		return nil
+	}
+
+	var inferInput triton.InferInput
+	switch task {
+	case modelPB.Model_TASK_TEXT_TO_IMAGE:
+		inferInput = &triton.TextToImageInput{
+			Prompt:   strings.TrimSpace(string(sample)),
+			Steps:    util.TEXT_TO_IMAGE_STEPS,
+			CfgScale: util.IMAGE_TO_TEXT_CFG_SCALE,
+			Seed:     util.IMAGE_TO_TEXT_SEED,
+			Samples:  util.IMAGE_TO_TEXT_SAMPLES,
+		}
+	case modelPB.Model_TASK_TEXT_GENERATION:
+		inferInput = &triton.TextGenerationInput{
+			Prompt:    strings.TrimSpace(string(sample)),
+			OutputLen: util.TEXT_GENERATION_OUTPUT_LEN,
+			TopK:      util.TEXT_GENERATION_TOP_K,
+			Seed:      util.TEXT_GENERATION_SEED,
+		}
+	default:
+		inferInput = [][]byte{sample}
+	}
+
+	version := fmt.Sprint(tEnsembleModel.Version)
+	modelMetadata := w.triton.ModelMetadataRequest(tEnsembleModel.Name, version)
+	modelConfig := w.triton.ModelConfigRequest(tEnsembleModel.Name, version)
+	if modelMetadata == nil || modelConfig == nil {
+		return temporal.NewNonRetryableApplicationError(fmt.Sprintf("warm-up failed: triton model %s is not available", tEnsembleModel.Name), "WarmupFailed", nil)
+	}
+
+	stop := startHeartbeat(ctx, nil)
+	defer stop()
+	inferResponse, err := w.triton.ModelInferRequest(task, inferInput, tEnsembleModel.Name, version, modelMetadata, modelConfig)
+	if err != nil {
+		return temporal.NewNonRetryableApplicationError(fmt.Sprintf("warm-up inference failed: %s", err), "WarmupFailed", err)
+	}
+	if _, err := w.triton.PostProcess(inferResponse, modelMetadata, task); err != nil {
+		return temporal.NewNonRetryableApplicationError(fmt.Sprintf("warm-up inference returned an invalid output: %s", err), "WarmupFailed", err)
+	}
+
+	logger.Info("WarmupModelActivity completed")
+
+	return nil
+}
+
+// PublishModelStateActivity sets the state of a deployed model in the controller, an online model is no longer
+// scaled to zero
+func (w *worker) PublishModelStateActivity(ctx context.Context, param *ModelStateParams) error {
+
+	ctx, span := tracer.Start(ctx, "PublishModelStateActivity",
+		trace.WithSpanKind(trace.SpanKindServer))
+	defer span.End()
+
+	logger := activity.GetLogger(ctx)
+
+	logger.Info("PublishModelStateActivity started")
+
+	if param.State == modelPB.Model_STATE_ONLINE {
+		if err := w.repository.UpdateModelScaledToZero(param.Model.UID, false); err != nil {
+			return err
+		}
+	}
+
+	if _, err := w.controllerClient.UpdateResource(ctx, &controllerPB.UpdateResourceRequest{
+		Resource: &controllerPB.Resource{
+			ResourcePermalink: util.ConvertModelToResourcePermalink(param.Model.UID.String()),
+			State: &controllerPB.Resource_ModelState{
+				ModelState: param.State,
+			},
+			Progress: nil,
+		},
+	}); err != nil {
+		return err
+	}
+
+	logger.Info("PublishModelStateActivity completed")
+
+	return nil
+}
+
+// CleanupDeployActivity removes the staging folder of a canceled deployment or of a model failing to warm up, with its
+// partially downloaded files, and unloads the Triton models it may have loaded
+func (w *worker) CleanupDeployActivity(ctx context.Context, param *ModelParams) error {
+
+	ctx, span := tracer.Start(ctx, "CleanupDeployActivity",
+		trace.WithSpanKind(trace.SpanKindServer))
+	defer span.End()
+
+	logger := activity.GetLogger(ctx)
+
+	logger.Info("CleanupDeployActivity started")
+
+	dir := getDeployStagingDir(param.Model.UID)
+	if err := os.RemoveAll(dir); err != nil {
+		return err
+	}
+	if err := os.Remove(getStagingLeasePath(dir)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	tritonModels, err := w.repository.GetTritonModels(param.Model.UID)
+	if err != nil {
+		return err
+	}
+	for _, tModel := range tritonModels {
+		if resp := w.triton.ModelReadyRequest(ctx, tModel.Name, fmt.Sprint(tModel.Version)); resp == nil || !resp.Ready {
+			continue
+		}
+		if _, err := w.triton.UnloadModelRequest(tModel.Name); err != nil {
+			return err
+		}
+	}
+
+	logger.Info("CleanupDeployActivity completed")
+
+	return nil
+}
@@ -2,15 +2,14 @@ package worker
 
 import (
 	"context"
-	"encoding/json"
+	"errors"
 	"fmt"
-	"os"
 	"time"
 
-	"github.com/gofrs/uuid"
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/trace"
 	"go.temporal.io/sdk/activity"
+	"go.temporal.io/sdk/temporal"
 	"go.temporal.io/sdk/workflow"
 
 	"github.com/instill-ai/model-backend/config"
@@ -28,181 +27,162 @@ type ModelParams struct {
 
 var tracer = otel.Tracer("model-backend.temporal.tracer")
 
+// deployRetryPolicy retries a failed deploy stage, which resumes from what the previous attempts left behind
+var deployRetryPolicy = &temporal.RetryPolicy{
+	InitialInterval:    10 * time.Second,
+	BackoffCoefficient: 2,
+	MaximumInterval:    5 * time.Minute,
+	MaximumAttempts:    3,
+}
+
+// deploySessionAttempts is how many sessions the fetch and the stage of a model are run in when their worker host is
+// lost or the fetched files are not found
+const deploySessionAttempts = 3
+
+// deployStage is a deploy activity with its timeouts
+type deployStage struct {
+	activity         interface{}
+	startToClose     time.Duration
+	heartbeatTimeout time.Duration
+}
+
 func (w *worker) DeployModelWorkflow(ctx workflow.Context, param *ModelParams) error {
 	logger := workflow.GetLogger(ctx)
 	logger.Info("DeployModelWorkflow started")
 
-	ao := workflow.ActivityOptions{
-		TaskQueue:           TaskQueue,
-		StartToCloseTimeout: 300 * time.Minute,
+	// the deployments started before the deploy stages replay the single activity deploying the whole model
+	if workflow.GetVersion(ctx, "deploy-stages", workflow.DefaultVersion, 1) == workflow.DefaultVersion {
+		ctx = workflow.WithActivityOptions(ctx, workflow.ActivityOptions{
+			TaskQueue:           TaskQueue,
+			StartToCloseTimeout: 300 * time.Minute,
+		})
+		if err := workflow.ExecuteActivity(ctx, w.DeployModelActivity, param).Get(ctx, nil); err != nil {
+			return err
+		}
+		logger.Info("DeployModelWorkflow completed")
+		return nil
 	}
-	ctx = workflow.WithActivityOptions(ctx, ao)
 
-	if err := workflow.ExecuteActivity(ctx, w.DeployModelActivity, param).Get(ctx, nil); err != nil {
-		return err
+	// the deployments started before the compatibility check replay without it
+	var err error
+	if workflow.GetVersion(ctx, "compatibility-check", workflow.DefaultVersion, 1) == 1 {
+		err = w.executeDeployStage(ctx, deployStage{w.CheckCompatibilityActivity, time.Minute, 0}, param)
 	}
-
-	logger.Info("DeployModelWorkflow completed")
-
-	return nil
-}
-
-func (w *worker) DeployModelActivity(ctx context.Context, param *ModelParams) error {
-
-	ctx, span := tracer.Start(ctx, "DeployModelActivity",
-		trace.WithSpanKind(trace.SpanKindServer))
-	defer span.End()
-
-	logger := activity.GetLogger(ctx)
-
-	logger.Info("DeployModelActivity started")
-
-	dbModel, err := w.repository.GetModelByUID(param.Owner, param.Model.UID, modelPB.View_VIEW_FULL)
-	if err != nil {
-		return err
+	if err == nil {
+		err = w.fetchAndStageModel(ctx, param)
+	}
+	for _, stage := range []deployStage{
+		{w.ValidateModelActivity, 5 * time.Minute, 0},
+		{w.LoadModelActivity, 60 * time.Minute, time.Minute},
+	} {
+		if err != nil {
+			break
+		}
+		err = w.executeDeployStage(ctx, stage, param)
+	}
+	// the deployments started before the warm-up replay without it
+	warmupFailed := false
+	if err == nil && workflow.GetVersion(ctx, "warmup-stage", workflow.DefaultVersion, 1) == 1 {
+		err = w.executeDeployStage(ctx, deployStage{w.WarmupModelActivity, 15 * time.Minute, time.Minute}, param)
+		warmupFailed = err != nil && !temporal.IsCanceledError(err)
 	}
-
-	modelDef, err := w.repository.GetModelDefinitionByUID(dbModel.ModelDefinitionUid)
 	if err != nil {
+		// a canceled deployment is cleaned up and left offline, a model failing to warm up is unloaded so that it
+		// does not hold the memory of the Triton server, and another failed one is kept for a retry to resume it
+		state := modelPB.Model_STATE_ERROR
+		dctx, _ := workflow.NewDisconnectedContext(ctx)
+		if temporal.IsCanceledError(err) {
+			state = modelPB.Model_STATE_OFFLINE
+		}
+		if state == modelPB.Model_STATE_OFFLINE || warmupFailed {
+			cctx := workflow.WithActivityOptions(dctx, workflow.ActivityOptions{
+				TaskQueue:           TaskQueue,
+				StartToCloseTimeout: 5 * time.Minute,
+				RetryPolicy:         deployRetryPolicy,
+			})
+			if err := workflow.ExecuteActivity(cctx, w.CleanupDeployActivity, param).Get(cctx, nil); err != nil {
+				logger.Error(fmt.Sprintf("failed to clean up the deployment of model %s: %s", param.Model.ID, err))
+			}
+		}
+		if err := w.publishModelState(dctx, param, state); err != nil {
+			logger.Error(fmt.Sprintf("failed to publish the %s state of model %s: %s", state, param.Model.ID, err))
+		}
 		return err
 	}
 
-	var tritonModels []datamodel.TritonModel
-	if tritonModels, err = w.repository.GetTritonModels(dbModel.UID); err != nil {
+	if err := w.publishModelState(ctx, param, modelPB.Model_STATE_ONLINE); err != nil {
 		return err
 	}
 
-	resourcePermalink := util.ConvertModelToResourcePermalink(dbModel.UID.String())
-
-	updateResourceReq := controllerPB.UpdateResourceRequest{
-		Resource: &controllerPB.Resource{
-			ResourcePermalink: resourcePermalink,
-			State:             &controllerPB.Resource_ModelState{},
-			Progress:          nil,
-		},
-	}
-
-	// downloading model weight when making inference
-	rdid, _ := uuid.NewV4()
-	modelSrcDir := fmt.Sprintf("/tmp/%s", rdid.String())
-	switch modelDef.ID {
-	case "github":
-		if !config.Config.Server.ItMode.Enabled && !util.HasModelWeightFile(config.Config.TritonServer.ModelStore, tritonModels) {
-			var modelConfig datamodel.GitHubModelConfiguration
-			if err := json.Unmarshal(dbModel.Configuration, &modelConfig); err != nil {
-				return err
-			}
-
-			if config.Config.Cache.Model { // cache model into ~/.cache/instill/models
-				modelSrcDir = util.MODEL_CACHE_DIR + "/" + modelConfig.Repository + modelConfig.Tag
-			}
-
-			if err := util.GitHubClone(modelSrcDir, modelConfig, true); err != nil {
-				_ = os.RemoveAll(modelSrcDir)
-				return err
-			}
-			if err := util.CopyModelFileToModelRepository(config.Config.TritonServer.ModelStore, modelSrcDir, tritonModels); err != nil {
-				_ = os.RemoveAll(modelSrcDir)
-				return err
-			}
-		}
-	case "huggingface":
-		if !util.HasModelWeightFile(config.Config.TritonServer.ModelStore, tritonModels) {
-			var modelConfig datamodel.HuggingFaceModelConfiguration
-			if err := json.Unmarshal(dbModel.Configuration, &modelConfig); err != nil {
-				return err
-			}
-
-			if config.Config.Cache.Model { // cache model into ~/.cache/instill/models
-				modelSrcDir = util.MODEL_CACHE_DIR + "/" + modelConfig.RepoId
-			}
+	logger.Info("DeployModelWorkflow completed")
 
-			if config.Config.Server.ItMode.Enabled { // use local model to remove internet connection issue while integration testing
-				if err = util.HuggingFaceExport(modelSrcDir, datamodel.HuggingFaceModelConfiguration{
-					RepoId: "assets/tiny-vit-random",
-				}, dbModel.ID); err != nil {
-					_ = os.RemoveAll(modelSrcDir)
-					return err
-				}
-			} else {
-				if err = util.HuggingFaceExport(modelSrcDir, modelConfig, dbModel.ID); err != nil {
-					_ = os.RemoveAll(modelSrcDir)
-					return err
-				}
-			}
+	return nil
+}
 
-			if err := util.CopyModelFileToModelRepository(config.Config.TritonServer.ModelStore, modelSrcDir, tritonModels); err != nil {
-				_ = os.RemoveAll(modelSrcDir)
-				return err
-			}
+// executeDeployStage runs a deploy activity on the task queue of the worker, or on the host of the session of ctx
+func (w *worker) executeDeployStage(ctx workflow.Context, stage deployStage, param *ModelParams) error {
+	ctx = workflow.WithActivityOptions(ctx, workflow.ActivityOptions{
+		TaskQueue:           TaskQueue,
+		StartToCloseTimeout: stage.startToClose,
+		HeartbeatTimeout:    stage.heartbeatTimeout,
+		RetryPolicy:         deployRetryPolicy,
+		WaitForCancellation: true,
+	})
+	return workflow.ExecuteActivity(ctx, stage.activity, param).Get(ctx, nil)
+}
 
-			if err := util.UpdateModelConfig(config.Config.TritonServer.ModelStore, tritonModels); err != nil {
-				return err
-			}
+// fetchAndStageModel runs the fetch and the stage of a model in a session, so that the stage runs on the worker host
+// the files were fetched to. When the host is lost or the fetched files are not found, both run again in a new session.
+func (w *worker) fetchAndStageModel(ctx workflow.Context, param *ModelParams) error {
+	var err error
+	for attempt := 1; attempt <= deploySessionAttempts; attempt++ {
+		var sctx workflow.Context
+		sctx, err = workflow.CreateSession(ctx, &workflow.SessionOptions{
+			CreationTimeout:  10 * time.Minute,
+			ExecutionTimeout: 360 * time.Minute,
+			HeartbeatTimeout: time.Minute,
+		})
+		if err != nil {
+			return err
 		}
-	case "artivc":
-		if !config.Config.Server.ItMode.Enabled && !util.HasModelWeightFile(config.Config.TritonServer.ModelStore, tritonModels) {
-			var modelConfig datamodel.ArtiVCModelConfiguration
-			err = json.Unmarshal([]byte(dbModel.Configuration), &modelConfig)
-			if err != nil {
-				return err
-			}
-
-			err = util.ArtiVCClone(modelSrcDir, modelConfig, true)
-			if err != nil {
-				_ = os.RemoveAll(modelSrcDir)
-				return err
-			}
-			if err := util.CopyModelFileToModelRepository(config.Config.TritonServer.ModelStore, modelSrcDir, tritonModels); err != nil {
-				_ = os.RemoveAll(modelSrcDir)
-				return err
+		for _, stage := range []deployStage{
+			{w.FetchModelActivity, 300 * time.Minute, time.Minute},
+			{w.StageModelActivity, 60 * time.Minute, time.Minute},
+		} {
+			if err = w.executeDeployStage(sctx, stage, param); err != nil {
+				break
 			}
 		}
-	}
-
-	if !config.Config.Cache.Model {
-		_ = os.RemoveAll(modelSrcDir)
-	}
+		lost := workflow.GetSessionInfo(sctx).SessionState == workflow.SessionStateFailed
+		workflow.CompleteSession(sctx)
 
-	tEnsembleModel, _ := w.repository.GetTritonEnsembleModel(param.Model.UID)
-	for _, tModel := range tritonModels {
-		if tEnsembleModel.Name != "" && tEnsembleModel.Name == tModel.Name { // load ensemble model last.
-			continue
-		}
-		if _, err = w.triton.LoadModelRequest(tModel.Name); err == nil {
-			continue
-		}
-		updateResourceReq.Resource.State = &controllerPB.Resource_ModelState{
-			ModelState: modelPB.Model_STATE_ERROR,
-		}
-		if _, err := w.controllerClient.UpdateResource(ctx, &updateResourceReq); err != nil {
+		var appErr *temporal.ApplicationError
+		missing := errors.As(err, &appErr) && appErr.Type() == missingFetchedFilesError
+		if err == nil || ctx.Err() != nil || !(lost || missing) {
 			return err
 		}
-		return err
+		workflow.GetLogger(ctx).Warn(fmt.Sprintf("fetching model %s again in a new session: %s", param.Model.ID, err))
 	}
+	return err
+}
 
-	if tEnsembleModel.Name != "" { // load ensemble model.
-		if _, err = w.triton.LoadModelRequest(tEnsembleModel.Name); err != nil {
-			updateResourceReq.Resource.State = &controllerPB.Resource_ModelState{
-				ModelState: modelPB.Model_STATE_ERROR,
-			}
-			if _, err := w.controllerClient.UpdateResource(ctx, &updateResourceReq); err != nil {
-				return err
-			}
-			return err
-		}
-	}
-
-	updateResourceReq.Resource.State = &controllerPB.Resource_ModelState{
-		ModelState: modelPB.Model_STATE_ONLINE,
-	}
-	if _, err := w.controllerClient.UpdateResource(ctx, &updateResourceReq); err != nil {
-		return err
-	}
-
-	logger.Info("DeployModelActivity completed")
-
-	return nil
+// publishModelState runs the activity publishing the state of a model
+func (w *worker) publishModelState(ctx workflow.Context, param *ModelParams, state modelPB.Model_State) error {
+	ctx = workflow.WithActivityOptions(ctx, workflow.ActivityOptions{
+		TaskQueue:           TaskQueue,
+		StartToCloseTimeout: time.Minute,
+		RetryPolicy: &temporal.RetryPolicy{
+			InitialInterval:    time.Second,
+			BackoffCoefficient: 2,
+			MaximumInterval:    time.Minute,
+			MaximumAttempts:    5,
+		},
+	})
+	return workflow.ExecuteActivity(ctx, w.PublishModelStateActivity, &ModelStateParams{
+		Model: param.Model,
+		Owner: param.Owner,
+		State: state,
+	}).Get(ctx, nil)
 }
 
 func (w *worker) UnDeployModelWorkflow(ctx workflow.Context, param *ModelParams) error {
@@ -268,6 +248,10 @@ func (w *worker) UnDeployModelActivity(ctx context.Context, param *ModelParams)
 		}
 	}
 
+	if err := w.repository.UpdateModelScaledToZero(dbModel.UID, false); err != nil {
+		return err
+	}
+
 	updateResourceReq.Resource.State = &controllerPB.Resource_ModelState{
 		ModelState: modelPB.Model_STATE_OFFLINE,
 	}
@@ -285,6 +269,23 @@ func (w *worker) CreateModelWorkflow(ctx workflow.Context, param *ModelParams) e
 	logger := workflow.GetLogger(ctx)
 	logger.Info("CreateModelWorkflow started")
 
+	// nothing is created when the operation is canceled before it starts, the files the handler wrote into the
+	// model repository are removed by an activity, the creations canceled before it replay without it
+	if ctx.Err() != nil {
+		if workflow.GetVersion(ctx, "create-cleanup", workflow.DefaultVersion, 1) == 1 {
+			dctx, _ := workflow.NewDisconnectedContext(ctx)
+			cctx := workflow.WithActivityOptions(dctx, workflow.ActivityOptions{
+				TaskQueue:           TaskQueue,
+				StartToCloseTimeout: 5 * time.Minute,
+				RetryPolicy:         deployRetryPolicy,
+			})
+			if err := workflow.ExecuteActivity(cctx, w.CleanupCreateActivity, param).Get(cctx, nil); err != nil {
+				logger.Error(fmt.Sprintf("unable to remove the model files of a canceled creation: %v", err))
+			}
+		}
+		return temporal.NewCanceledError()
+	}
+
 	updateResourceReq := controllerPB.UpdateResourceRequest{
 		Resource: &controllerPB.Resource{
 			ResourcePermalink: "",
@@ -334,3 +335,49 @@ func (w *worker) CreateModelWorkflow(ctx workflow.Context, param *ModelParams) e
 
 	return nil
 }
+
+// CleanupCreateActivity removes the folders the handler wrote into the model repository for a creation canceled
+// before it started
+func (w *worker) CleanupCreateActivity(ctx context.Context, param *ModelParams) error {
+
+	ctx, span := tracer.Start(ctx, "CleanupCreateActivity",
+		trace.WithSpanKind(trace.SpanKindServer))
+	defer span.End()
+
+	logger := activity.GetLogger(ctx)
+
+	logger.Info("CleanupCreateActivity started")
+
+	tritonModelNames := []string{}
+	for _, tritonModel := range param.Model.TritonModels {
+		tritonModelNames = append(tritonModelNames, tritonModel.Name)
+	}
+	if err := util.RemoveTritonModelFolders(config.Config.TritonServer.ModelStore, tritonModelNames); err != nil {
+		return err
+	}
+
+	logger.Info("CleanupCreateActivity completed")
+
+	return nil
+}
+
+// DeleteModelReleaseSignal is the signal the service sends to the workflow of a delete operation once it is done
+const DeleteModelReleaseSignal = "release"
+
+// DeleteModelLease is how long the workflow of a delete operation holds the operation workflow ID of a model when
+// the service never releases it
+const DeleteModelLease = 10 * time.Minute
+
+// DeleteModelWorkflow holds the operation workflow ID of a model while the service deletes it, so that no other
+// operation starts on the model meanwhile. It completes on the release signal of the service.
+func (w *worker) DeleteModelWorkflow(ctx workflow.Context, param *ModelParams) error {
+
+	logger := workflow.GetLogger(ctx)
+	logger.Info("DeleteModelWorkflow started")
+
+	workflow.GetSignalChannel(ctx, DeleteModelReleaseSignal).Receive(ctx, nil)
+
+	logger.Info("DeleteModelWorkflow completed")
+
+	return nil
+}
@@ -0,0 +1,193 @@
+package worker_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.temporal.io/sdk/temporal"
+	"go.temporal.io/sdk/testsuite"
+
+	uuid "github.com/gofrs/uuid"
+	sdkworker "go.temporal.io/sdk/worker"
+
+	"github.com/instill-ai/model-backend/pkg/worker"
+
+	datamodel "github.com/instill-ai/model-backend/pkg/datamodel"
+	modelPB "github.com/instill-ai/protogen-go/model/model/v1alpha"
+)
+
+const OWNER = "users/909c3278-f7d1-461c-9352-87741bef1ds1"
+
+// deployTest runs the deploy workflow with every activity mocked, and records the activities it ran and the states
+// it published in order
+type deployTest struct {
+	*testsuite.TestWorkflowEnvironment
+	mu     sync.Mutex
+	calls  []string
+	states []modelPB.Model_State
+}
+
+func (d *deployTest) record(name string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.calls = append(d.calls, name)
+}
+
+// newDeployTest mocks the activities of the deploy workflow, an activity succeeds unless the stages give it another
+// implementation
+func newDeployTest(stages map[string]func(ctx context.Context, d *deployTest) error) (*deployTest, worker.Worker) {
+	var s testsuite.WorkflowTestSuite
+	d := &deployTest{TestWorkflowEnvironment: s.NewTestWorkflowEnvironment()}
+	d.SetWorkerOptions(sdkworker.Options{EnableSessionWorker: true})
+
+	cw := worker.NewWorker(nil, nil, nil, nil)
+	stage := func(name string) func(context.Context, *worker.ModelParams) error {
+		return func(ctx context.Context, _ *worker.ModelParams) error {
+			d.record(name)
+			if f, ok := stages[name]; ok {
+				return f(ctx, d)
+			}
+			return nil
+		}
+	}
+	d.OnActivity(cw.CheckCompatibilityActivity, mock.Anything, mock.Anything).Return(stage("CheckCompatibilityActivity"))
+	d.OnActivity(cw.FetchModelActivity, mock.Anything, mock.Anything).Return(stage("FetchModelActivity"))
+	d.OnActivity(cw.StageModelActivity, mock.Anything, mock.Anything).Return(stage("StageModelActivity"))
+	d.OnActivity(cw.ValidateModelActivity, mock.Anything, mock.Anything).Return(stage("ValidateModelActivity"))
+	d.OnActivity(cw.LoadModelActivity, mock.Anything, mock.Anything).Return(stage("LoadModelActivity"))
+	d.OnActivity(cw.WarmupModelActivity, mock.Anything, mock.Anything).Return(stage("WarmupModelActivity"))
+	d.OnActivity(cw.CleanupDeployActivity, mock.Anything, mock.Anything).Return(stage("CleanupDeployActivity"))
+	d.OnActivity(cw.PublishModelStateActivity, mock.Anything, mock.Anything).Return(
+		func(_ context.Context, param *worker.ModelStateParams) error {
+			d.record("PublishModelStateActivity")
+			d.mu.Lock()
+			defer d.mu.Unlock()
+			d.states = append(d.states, param.State)
+			return nil
+		})
+
+	return d, cw
+}
+
+func newDeployParams() *worker.ModelParams {
+	model := datamodel.Model{ID: "modelID", Owner: OWNER}
+	model.UID = uuid.Must(uuid.NewV4())
+	return &worker.ModelParams{Model: model, Owner: OWNER}
+}
+
+func TestDeployModelWorkflow(t *testing.T) {
+	t.Run("RunsTheStagesInOrder", func(t *testing.T) {
+		d, cw := newDeployTest(nil)
+
+		d.ExecuteWorkflow(cw.DeployModelWorkflow, newDeployParams())
+
+		assert.True(t, d.IsWorkflowCompleted())
+		assert.NoError(t, d.GetWorkflowError())
+		assert.Equal(t, []string{
+			"CheckCompatibilityActivity",
+			"FetchModelActivity",
+			"StageModelActivity",
+			"ValidateModelActivity",
+			"LoadModelActivity",
+			"WarmupModelActivity",
+			"PublishModelStateActivity",
+		}, d.calls)
+		assert.Equal(t, []modelPB.Model_State{modelPB.Model_STATE_ONLINE}, d.states)
+	})
+	t.Run("CleansUpACanceledDeployment", func(t *testing.T) {
+		d, cw := newDeployTest(map[string]func(context.Context, *deployTest) error{
+			"FetchModelActivity": func(ctx context.Context, d *deployTest) error {
+				d.CancelWorkflow()
+				<-ctx.Done()
+				return ctx.Err()
+			},
+		})
+
+		d.ExecuteWorkflow(cw.DeployModelWorkflow, newDeployParams())
+
+		assert.True(t, d.IsWorkflowCompleted())
+		var canceledErr *temporal.CanceledError
+		assert.True(t, errors.As(d.GetWorkflowError(), &canceledErr))
+		assert.Equal(t, []string{
+			"CheckCompatibilityActivity",
+			"FetchModelActivity",
+			"CleanupDeployActivity",
+			"PublishModelStateActivity",
+		}, d.calls)
+		assert.Equal(t, []modelPB.Model_State{modelPB.Model_STATE_OFFLINE}, d.states)
+	})
+	t.Run("UnloadsAModelFailingToWarmUp", func(t *testing.T) {
+		d, cw := newDeployTest(map[string]func(context.Context, *deployTest) error{
+			"WarmupModelActivity": func(context.Context, *deployTest) error {
+				return temporal.NewNonRetryableApplicationError("warm-up inference failed", "WarmupFailed", nil)
+			},
+		})
+
+		d.ExecuteWorkflow(cw.DeployModelWorkflow, newDeployParams())
+
+		assert.True(t, d.IsWorkflowCompleted())
+		var applicationErr *temporal.ApplicationError
+		assert.True(t, errors.As(d.GetWorkflowError(), &applicationErr))
+		assert.Equal(t, "WarmupFailed", applicationErr.Type())
+		assert.Equal(t, []string{
+			"CheckCompatibilityActivity",
+			"FetchModelActivity",
+			"StageModelActivity",
+			"ValidateModelActivity",
+			"LoadModelActivity",
+			"WarmupModelActivity",
+			"CleanupDeployActivity",
+			"PublishModelStateActivity",
+		}, d.calls)
+		assert.Equal(t, []modelPB.Model_State{modelPB.Model_STATE_ERROR}, d.states)
+	})
+	t.Run("FailsAnIncompatibleModelBeforeTheFetch", func(t *testing.T) {
+		d, cw := newDeployTest(map[string]func(context.Context, *deployTest) error{
+			"CheckCompatibilityActivity": func(context.Context, *deployTest) error {
+				return temporal.NewNonRetryableApplicationError("backend tensorflow is not installed", "IncompatibleModel", nil)
+			},
+		})
+
+		d.ExecuteWorkflow(cw.DeployModelWorkflow, newDeployParams())
+
+		assert.True(t, d.IsWorkflowCompleted())
+		var applicationErr *temporal.ApplicationError
+		assert.True(t, errors.As(d.GetWorkflowError(), &applicationErr))
+		assert.Equal(t, "IncompatibleModel", applicationErr.Type())
+		assert.True(t, applicationErr.NonRetryable())
+		assert.Equal(t, []string{
+			"CheckCompatibilityActivity",
+			"PublishModelStateActivity",
+		}, d.calls)
+		assert.Equal(t, []modelPB.Model_State{modelPB.Model_STATE_ERROR}, d.states)
+	})
+}
+
+func TestCreateModelWorkflow(t *testing.T) {
+	t.Run("CleansUpACreationCanceledBeforeItStarts", func(t *testing.T) {
+		var s testsuite.WorkflowTestSuite
+		env := s.NewTestWorkflowEnvironment()
+		cw := worker.NewWorker(nil, nil, nil, nil)
+		param := newDeployParams()
+		param.Model.TritonModels = []datamodel.TritonModel{{Name: "modelID-infer"}}
+
+		cleaned := []datamodel.TritonModel{}
+		env.OnActivity(cw.CleanupCreateActivity, mock.Anything, mock.Anything).Return(
+			func(_ context.Context, param *worker.ModelParams) error {
+				cleaned = param.Model.TritonModels
+				return nil
+			})
+
+		env.RegisterDelayedCallback(env.CancelWorkflow, 0)
+		env.ExecuteWorkflow(cw.CreateModelWorkflow, param)
+
+		assert.True(t, env.IsWorkflowCompleted())
+		var canceledErr *temporal.CanceledError
+		assert.True(t, errors.As(env.GetWorkflowError(), &canceledErr))
+		assert.Equal(t, param.Model.TritonModels, cleaned)
+	})
+}
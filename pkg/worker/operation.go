@@ -0,0 +1,275 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.opentelemetry.io/otel/trace"
+	"go.temporal.io/api/enums/v1"
+	"go.temporal.io/sdk/activity"
+	"go.temporal.io/sdk/temporal"
+	"go.temporal.io/sdk/workflow"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/instill-ai/model-backend/pkg/datamodel"
+	"github.com/instill-ai/model-backend/pkg/util"
+
+	controllerPB "github.com/instill-ai/protogen-go/model/controller/v1alpha"
+	modelPB "github.com/instill-ai/protogen-go/model/model/v1alpha"
+)
+
+// OperationParams are the parameters of an operation on a model started by a schedule or a bulk operation, the memo
+// and the search attributes are set on the deploy or undeploy operation it runs
+type OperationParams struct {
+	Model            datamodel.Model
+	Owner            string
+	Kind             string
+	Memo             map[string]interface{}
+	SearchAttributes map[string]interface{}
+}
+
+// OperationResult is the outcome of an operation on a model, the deploy or undeploy operation it ran or the reason it
+// did nothing
+type OperationResult struct {
+	Operation string `json:"operation,omitempty"`
+	Skipped   string `json:"skipped,omitempty"`
+}
+
+// modelOperationRetryPolicy retries the activities preparing and running an operation on a model
+var modelOperationRetryPolicy = &temporal.RetryPolicy{
+	InitialInterval:    time.Second,
+	BackoffCoefficient: 2,
+	MaximumInterval:    time.Minute,
+	MaximumAttempts:    3,
+}
+
+// The memo of a ModelOperationWorkflow holds its result once it is done, so that the runs of a schedule are read
+// without their history
+const (
+	OperationResultMemoOperation = "result_operation"
+	OperationResultMemoSkipped   = "result_skipped"
+)
+
+// ModelOperationWorkflow runs an operation on a model. A deploy or undeploy runs as a child workflow with the ID of
+// the operation and is skipped when the model is already in the state it leads to, a delete, publish or unpublish
+// runs as an activity.
+func (w *worker) ModelOperationWorkflow(ctx workflow.Context, param *OperationParams) (*OperationResult, error) {
+	result, err := w.runModelOperation(ctx, param)
+	if err != nil {
+		return nil, err
+	}
+	if workflow.GetVersion(ctx, "operation-result-memo", workflow.DefaultVersion, 1) == 1 {
+		if err := workflow.UpsertMemo(ctx, map[string]interface{}{
+			OperationResultMemoOperation: result.Operation,
+			OperationResultMemoSkipped:   result.Skipped,
+		}); err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
+func (w *worker) runModelOperation(ctx workflow.Context, param *OperationParams) (*OperationResult, error) {
+	logger := workflow.GetLogger(ctx)
+	logger.Info("ModelOperationWorkflow started")
+
+	actx := workflow.WithActivityOptions(ctx, workflow.ActivityOptions{
+		TaskQueue:           TaskQueue,
+		StartToCloseTimeout: 5 * time.Minute,
+		RetryPolicy:         modelOperationRetryPolicy,
+	})
+
+	var operationWorkflow interface{}
+	switch param.Kind {
+	case "deploy":
+		operationWorkflow = w.DeployModelWorkflow
+	case "undeploy":
+		operationWorkflow = w.UnDeployModelWorkflow
+	case "delete", "publish", "unpublish":
+		var skipped string
+		if err := workflow.ExecuteActivity(actx, w.ModelActionActivity, param).Get(actx, &skipped); err != nil {
+			return nil, err
+		}
+		logger.Info("ModelOperationWorkflow completed")
+		return &OperationResult{Skipped: skipped}, nil
+	default:
+		return nil, temporal.NewNonRetryableApplicationError(fmt.Sprintf("unknown operation kind %q", param.Kind), "InvalidArgument", nil)
+	}
+
+	var skipped string
+	if err := workflow.ExecuteActivity(actx, w.PrepareModelOperationActivity, param).Get(actx, &skipped); err != nil {
+		return nil, err
+	}
+	if skipped != "" {
+		logger.Info(fmt.Sprintf("%s of model %s skipped: %s", param.Kind, param.Model.ID, skipped))
+		return &OperationResult{Skipped: skipped}, nil
+	}
+
+	workflowID := OperationWorkflowID(param.Model.UID)
+	cctx := workflow.WithChildOptions(ctx, workflow.ChildWorkflowOptions{
+		WorkflowID:            workflowID,
+		TaskQueue:             TaskQueue,
+		WorkflowIDReusePolicy: enums.WORKFLOW_ID_REUSE_POLICY_ALLOW_DUPLICATE,
+		Memo:                  param.Memo,
+		SearchAttributes:      param.SearchAttributes,
+	})
+	future := workflow.ExecuteChildWorkflow(cctx, operationWorkflow, &ModelParams{
+		Model: param.Model,
+		Owner: param.Owner,
+	})
+	var execution workflow.Execution
+	err := future.GetChildWorkflowExecution().Get(cctx, &execution)
+	if err == nil {
+		err = future.Get(cctx, nil)
+	}
+	if err != nil {
+		var alreadyStarted *temporal.ChildWorkflowExecutionAlreadyStartedError
+		if errors.As(err, &alreadyStarted) {
+			return nil, temporal.NewNonRetryableApplicationError("another operation is running on the model", "Aborted", err)
+		}
+		return nil, err
+	}
+
+	logger.Info("ModelOperationWorkflow completed")
+
+	return &OperationResult{Operation: fmt.Sprintf("operations/%s", OperationID(workflowID, execution.RunID))}, nil
+}
+
+// PrepareModelOperationActivity sets the desired state of the model of a deploy or undeploy and marks it as in
+// operation, as the deploy and undeploy endpoints do. It returns why the operation is skipped when the model is
+// already in the state, and fails when the model cannot reach the state.
+func (w *worker) PrepareModelOperationActivity(ctx context.Context, param *OperationParams) (string, error) {
+
+	ctx, span := tracer.Start(ctx, "PrepareModelOperationActivity",
+		trace.WithSpanKind(trace.SpanKindServer))
+	defer span.End()
+
+	logger := activity.GetLogger(ctx)
+
+	logger.Info("PrepareModelOperationActivity started")
+
+	dbModel, err := w.repository.GetModelByUID(param.Owner, param.Model.UID, modelPB.View_VIEW_BASIC)
+	if err != nil {
+		return "", temporal.NewNonRetryableApplicationError(fmt.Sprintf("model %s not found", param.Model.ID), "NotFound", err)
+	}
+
+	resourcePermalink := util.ConvertModelToResourcePermalink(dbModel.UID.String())
+	state := w.getModelState(ctx, dbModel)
+
+	desired := modelPB.Model_STATE_ONLINE
+	from := modelPB.Model_STATE_OFFLINE
+	if param.Kind == "undeploy" {
+		desired, from = from, desired
+	}
+	switch state {
+	case desired:
+		return fmt.Sprintf("the model is already %s", desired), nil
+	case modelPB.Model_STATE_UNSPECIFIED:
+		return "", temporal.NewNonRetryableApplicationError("another operation is running on the model", "Aborted", nil)
+	case from:
+	default:
+		return "", temporal.NewNonRetryableApplicationError(fmt.Sprintf("a %s model cannot be %sed", state, param.Kind), "FailedPrecondition", nil)
+	}
+
+	if err := w.repository.UpdateModelState(dbModel.UID, datamodel.ModelState(desired)); err != nil {
+		return "", err
+	}
+
+	workflowID := OperationWorkflowID(dbModel.UID)
+	if _, err := w.controllerClient.UpdateResource(ctx, &controllerPB.UpdateResourceRequest{
+		Resource: &controllerPB.Resource{
+			ResourcePermalink: resourcePermalink,
+			State: &controllerPB.Resource_ModelState{
+				ModelState: modelPB.Model_STATE_UNSPECIFIED,
+			},
+		},
+		WorkflowId: &workflowID,
+	}); err != nil {
+		return "", err
+	}
+
+	logger.Info("PrepareModelOperationActivity completed")
+
+	return "", nil
+}
+
+// getModelState returns the state of a model, the controller holds the latest state and the database is the fallback
+// when it is unknown to the controller
+func (w *worker) getModelState(ctx context.Context, dbModel datamodel.Model) modelPB.Model_State {
+	if resp, err := w.controllerClient.GetResource(ctx, &controllerPB.GetResourceRequest{
+		ResourcePermalink: util.ConvertModelToResourcePermalink(dbModel.UID.String()),
+	}); err == nil {
+		return resp.Resource.GetModelState()
+	}
+	return modelPB.Model_State(dbModel.State)
+}
+
+// ModelActionActivity deletes, publishes or unpublishes a model as the endpoints of the actions do. It returns why the
+// action is skipped when there is nothing to do, and fails on a model being deployed or undeployed.
+func (w *worker) ModelActionActivity(ctx context.Context, param *OperationParams) (string, error) {
+
+	ctx, span := tracer.Start(ctx, "ModelActionActivity",
+		trace.WithSpanKind(trace.SpanKindServer))
+	defer span.End()
+
+	logger := activity.GetLogger(ctx)
+
+	logger.Info("ModelActionActivity started")
+
+	dbModel, err := w.repository.GetModelByUID(param.Owner, param.Model.UID, modelPB.View_VIEW_BASIC)
+	if err != nil {
+		if param.Kind == "delete" {
+			return "the model is already deleted", nil
+		}
+		return "", temporal.NewNonRetryableApplicationError(fmt.Sprintf("model %s not found", param.Model.ID), "NotFound", err)
+	}
+
+	switch param.Kind {
+	case "publish", "unpublish":
+		visibility := datamodel.ModelVisibility(modelPB.Model_VISIBILITY_PUBLIC)
+		if param.Kind == "unpublish" {
+			visibility = datamodel.ModelVisibility(modelPB.Model_VISIBILITY_PRIVATE)
+		}
+		if dbModel.Visibility == visibility {
+			return fmt.Sprintf("the model is already %s", modelPB.Model_Visibility(visibility)), nil
+		}
+		if err := w.repository.UpdateModel(dbModel.UID, datamodel.Model{
+			ID:         dbModel.ID,
+			Visibility: visibility,
+		}); err != nil {
+			return "", err
+		}
+	case "delete":
+		if err := w.modelDeleter.DeleteModel(ctx, param.Owner, dbModel.ID); err != nil {
+			if status.Code(err) == codes.NotFound {
+				return "the model is already deleted", nil
+			}
+			return "", getServiceError(err)
+		}
+	default:
+		return "", temporal.NewNonRetryableApplicationError(fmt.Sprintf("unknown model action %q", param.Kind), "InvalidArgument", nil)
+	}
+
+	logger.Info("ModelActionActivity completed")
+
+	return "", nil
+}
+
+// getServiceError makes an error of the service that a retry does not fix non-retryable, with the message and the code
+// of its status
+func getServiceError(err error) error {
+	st, ok := status.FromError(err)
+	if !ok {
+		return err
+	}
+	switch st.Code() {
+	case codes.InvalidArgument, codes.NotFound, codes.AlreadyExists, codes.PermissionDenied, codes.Unauthenticated,
+		codes.FailedPrecondition, codes.Aborted:
+		return temporal.NewNonRetryableApplicationError(st.Message(), st.Code().String(), err)
+	default:
+		return err
+	}
+}
@@ -0,0 +1,156 @@
+package worker
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/gofrs/uuid"
+	"go.temporal.io/sdk/activity"
+
+	"github.com/instill-ai/model-backend/pkg/util"
+
+	controllerPB "github.com/instill-ai/protogen-go/model/controller/v1alpha"
+	modelPB "github.com/instill-ai/protogen-go/model/model/v1alpha"
+)
+
+// OperationProgress is the progress of the stage a model operation is at, it is recorded as the heartbeat details of
+// the stage activity and exposed in the metadata of the operation
+type OperationProgress struct {
+	Stage           string `json:"stage"`
+	Progress        int32  `json:"progress"`
+	DownloadedBytes int64  `json:"downloaded_bytes,omitempty"`
+	TotalBytes      int64  `json:"total_bytes,omitempty"`
+	LoadedModels    int    `json:"loaded_models,omitempty"`
+	TotalModels     int    `json:"total_models,omitempty"`
+}
+
+// OperationKinds are the kinds of the model operations by the name of the workflow running them
+var OperationKinds = map[string]string{
+	"CreateModelWorkflow":   "create",
+	"DeployModelWorkflow":   "deploy",
+	"UnDeployModelWorkflow": "undeploy",
+	"DeleteModelWorkflow":   "delete",
+	"BulkModelWorkflow":     "bulk",
+}
+
+// OperationWorkflowID returns the workflow ID of every operation on a model, Temporal runs a single workflow per ID
+// so that an operation cannot start while another one is running on the model
+func OperationWorkflowID(modelUID uuid.UUID) string {
+	return fmt.Sprintf("model-%s", modelUID.String())
+}
+
+// OperationID returns the ID of the operation run by a workflow run, the operations on a model share its workflow ID
+// so the run tells them apart
+func OperationID(workflowID string, runID string) string {
+	return fmt.Sprintf("%s.%s", workflowID, runID)
+}
+
+// ParseOperationID returns the workflow ID and the run ID of an operation, the run ID is empty for an operation named
+// by its workflow ID only, which stands for the latest run
+func ParseOperationID(operationID string) (string, string) {
+	if i := strings.LastIndex(operationID, "."); i >= 0 {
+		if _, err := uuid.FromString(operationID[i+1:]); err == nil {
+			return operationID[:i], operationID[i+1:]
+		}
+	}
+	return operationID, ""
+}
+
+// OperationStages are the stages of the model operations by the name of the activity running them
+var OperationStages = map[string]string{
+	"CheckCompatibilityActivity": "check",
+	"FetchModelActivity":         "fetch",
+	"StageModelActivity":         "stage",
+	"ValidateModelActivity":      "validate",
+	"LoadModelActivity":          "load",
+	"WarmupModelActivity":        "warmup",
+	"PublishModelStateActivity":  "publish",
+	"UnDeployModelActivity":      "unload",
+	"CleanupDeployActivity":      "cleanup",
+	"CleanupCreateActivity":      "cleanup",
+}
+
+// progressReporter keeps the progress of a stage for its heartbeats and forwards its percentage to the controller
+// whenever it changes, a failed report does not fail the stage
+type progressReporter struct {
+	ctx               context.Context
+	w                 *worker
+	resourcePermalink string
+	workflowID        string
+
+	mu       sync.Mutex
+	progress OperationProgress
+	reported int32
+}
+
+func (w *worker) newProgressReporter(ctx context.Context, modelUID uuid.UUID, stage string) *progressReporter {
+	return &progressReporter{
+		ctx:               ctx,
+		w:                 w,
+		resourcePermalink: util.ConvertModelToResourcePermalink(modelUID.String()),
+		workflowID:        activity.GetInfo(ctx).WorkflowExecution.ID,
+		progress:          OperationProgress{Stage: stage},
+		reported:          -1,
+	}
+}
+
+// details returns the heartbeat details of the stage
+func (r *progressReporter) details() interface{} {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.progress
+}
+
+// download is the util.ProgressFunc of the fetchers, an unknown total only updates the bytes downloaded
+func (r *progressReporter) download(done int64, total int64) {
+	r.update(func(p *OperationProgress) {
+		p.DownloadedBytes = done
+		p.TotalBytes = total
+		if total > 0 {
+			p.Progress = util.GetProgressPercentage(done, total)
+		}
+	})
+}
+
+// load records the number of Triton models loaded out of the Triton models of the model
+func (r *progressReporter) load(loaded int, total int) {
+	r.update(func(p *OperationProgress) {
+		p.LoadedModels = loaded
+		p.TotalModels = total
+		if total > 0 {
+			p.Progress = int32(loaded * 100 / total)
+		}
+	})
+}
+
+// done sets the stage to 100%
+func (r *progressReporter) done() {
+	r.update(func(p *OperationProgress) {
+		p.Progress = 100
+	})
+}
+
+func (r *progressReporter) update(f func(p *OperationProgress)) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	f(&r.progress)
+	if r.progress.Progress == r.reported {
+		return
+	}
+	r.reported = r.progress.Progress
+	progress := r.progress.Progress
+	if _, err := r.w.controllerClient.UpdateResource(r.ctx, &controllerPB.UpdateResourceRequest{
+		Resource: &controllerPB.Resource{
+			ResourcePermalink: r.resourcePermalink,
+			State: &controllerPB.Resource_ModelState{
+				ModelState: modelPB.Model_STATE_UNSPECIFIED,
+			},
+			Progress: &progress,
+		},
+		WorkflowId: &r.workflowID,
+	}); err != nil {
+		activity.GetLogger(r.ctx).Warn(fmt.Sprintf("failed to report the %s progress of %s: %s", r.progress.Stage, r.resourcePermalink, err))
+	}
+}
@@ -0,0 +1,351 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.opentelemetry.io/otel/trace"
+	"go.temporal.io/api/enums/v1"
+	"go.temporal.io/api/serviceerror"
+	"go.temporal.io/sdk/activity"
+	"go.temporal.io/sdk/client"
+	"go.temporal.io/sdk/converter"
+	"go.temporal.io/sdk/workflow"
+
+	"github.com/instill-ai/model-backend/config"
+	"github.com/instill-ai/model-backend/pkg/datamodel"
+	"github.com/instill-ai/model-backend/pkg/repository"
+	"github.com/instill-ai/model-backend/pkg/util"
+
+	controllerPB "github.com/instill-ai/protogen-go/model/controller/v1alpha"
+	modelPB "github.com/instill-ai/protogen-go/model/model/v1alpha"
+)
+
+// ReconcileWorkflowID is the fixed workflow ID of the periodic reconciliation cron workflow
+const ReconcileWorkflowID = "model-backend-reconciler"
+
+// Reconciliation actions
+const (
+	ReconcileActionReload    = "reload"
+	ReconcileActionUnload    = "unload"
+	ReconcileActionMarkError = "mark-error"
+	ReconcileActionRemoveDir = "remove-dir"
+)
+
+// Triton model states reported by the repository index
+const (
+	tritonModelStateReady     = "READY"
+	tritonModelStateLoading   = "LOADING"
+	tritonModelStateUnloading = "UNLOADING"
+)
+
+// ReconcileParams are the parameters of a reconciliation pass, the drifts are only reported in dry-run mode
+type ReconcileParams struct {
+	DryRun bool
+}
+
+// ReconcileAction is a single drift found between the model states and Triton
+type ReconcileAction struct {
+	Action      string `json:"action"`
+	ModelUID    string `json:"model_uid,omitempty"`
+	Owner       string `json:"owner,omitempty"`
+	ModelID     string `json:"model_id,omitempty"`
+	TritonModel string `json:"triton_model,omitempty"`
+	Reason      string `json:"reason"`
+	Error       string `json:"error,omitempty"`
+}
+
+// ReconcileReport lists the drifts found in one reconciliation pass, actions are not applied in dry-run mode
+type ReconcileReport struct {
+	DryRun  bool              `json:"dry_run"`
+	Actions []ReconcileAction `json:"actions"`
+}
+
+// The memo keys of the reconciliation cron workflow recording the settings it was started with
+const (
+	reconcileMemoSchedule = "schedule"
+	reconcileMemoDryRun   = "dry_run"
+)
+
+// StartReconcileWorkflow starts the reconciliation cron workflow with the reconciler settings. A running one started
+// with the same settings is kept as is, one started with other settings is restarted and one running while the
+// reconciler is disabled is terminated.
+func StartReconcileWorkflow(ctx context.Context, c client.Client) error {
+	running := false
+	resp, err := c.DescribeWorkflowExecution(ctx, ReconcileWorkflowID, "")
+	if err == nil {
+		running = resp.GetWorkflowExecutionInfo().GetStatus() == enums.WORKFLOW_EXECUTION_STATUS_RUNNING
+	} else {
+		var notFound *serviceerror.NotFound
+		if !errors.As(err, &notFound) {
+			return err
+		}
+	}
+
+	settings := config.Config.Reconciler
+	if !settings.Enabled {
+		if running {
+			return c.TerminateWorkflow(ctx, ReconcileWorkflowID, "", "the reconciler is disabled")
+		}
+		return nil
+	}
+
+	if running {
+		var schedule string
+		var dryRun bool
+		fields := resp.GetWorkflowExecutionInfo().GetMemo().GetFields()
+		dataConverter := converter.GetDefaultDataConverter()
+		if fields[reconcileMemoSchedule] != nil && fields[reconcileMemoDryRun] != nil &&
+			dataConverter.FromPayload(fields[reconcileMemoSchedule], &schedule) == nil &&
+			dataConverter.FromPayload(fields[reconcileMemoDryRun], &dryRun) == nil &&
+			schedule == settings.Schedule && dryRun == settings.DryRun {
+			return nil
+		}
+	}
+
+	_, err = c.ExecuteWorkflow(
+		ctx,
+		client.StartWorkflowOptions{
+			ID:                    ReconcileWorkflowID,
+			TaskQueue:             TaskQueue,
+			CronSchedule:          settings.Schedule,
+			WorkflowIDReusePolicy: enums.WORKFLOW_ID_REUSE_POLICY_TERMINATE_IF_RUNNING,
+			Memo: map[string]interface{}{
+				reconcileMemoSchedule: settings.Schedule,
+				reconcileMemoDryRun:   settings.DryRun,
+			},
+		},
+		"ReconcileModelsWorkflow",
+		&ReconcileParams{
+			DryRun: settings.DryRun,
+		},
+	)
+	return err
+}
+
+func (w *worker) ReconcileModelsWorkflow(ctx workflow.Context, param *ReconcileParams) (*ReconcileReport, error) {
+	logger := workflow.GetLogger(ctx)
+	logger.Info("ReconcileModelsWorkflow started")
+
+	ao := workflow.ActivityOptions{
+		TaskQueue:           TaskQueue,
+		StartToCloseTimeout: 30 * time.Minute,
+	}
+	ctx = workflow.WithActivityOptions(ctx, ao)
+
+	var report ReconcileReport
+	if err := workflow.ExecuteActivity(ctx, w.ReconcileModelsActivity, param).Get(ctx, &report); err != nil {
+		return nil, err
+	}
+
+	logger.Info(fmt.Sprintf("ReconcileModelsWorkflow completed with %d action(s)", len(report.Actions)))
+
+	return &report, nil
+}
+
+func (w *worker) ReconcileModelsActivity(ctx context.Context, param *ReconcileParams) (*ReconcileReport, error) {
+
+	ctx, span := tracer.Start(ctx, "ReconcileModelsActivity",
+		trace.WithSpanKind(trace.SpanKindServer))
+	defer span.End()
+
+	logger := activity.GetLogger(ctx)
+
+	logger.Info("ReconcileModelsActivity started")
+
+	indexResp := w.triton.ListModelsRequest()
+	if indexResp == nil {
+		return nil, fmt.Errorf("unable to list models from the triton repository index")
+	}
+	tritonStates := map[string]string{}
+	for _, m := range indexResp.Models {
+		tritonStates[m.Name] = m.State
+	}
+
+	report := &ReconcileReport{
+		DryRun:  param.DryRun,
+		Actions: []ReconcileAction{},
+	}
+	knownTritonModels := map[string]bool{}
+
+	pageToken := ""
+	for {
+		dbModels, nextPageToken, _, err := w.repository.ListModelsAdmin(modelPB.View_VIEW_BASIC, repository.MaxPageSize, pageToken)
+		if err != nil {
+			return nil, err
+		}
+		for _, dbModel := range dbModels {
+			tritonModels, err := w.repository.GetTritonModels(dbModel.UID)
+			if err != nil {
+				return nil, err
+			}
+			for _, tm := range tritonModels {
+				knownTritonModels[tm.Name] = true
+			}
+			report.Actions = append(report.Actions, w.reconcileModel(ctx, param.DryRun, dbModel, tritonModels, tritonStates)...)
+		}
+		if nextPageToken == "" {
+			break
+		}
+		pageToken = nextPageToken
+	}
+
+	// Models still served by Triton without any owning model record, the Triton server may be shared with other
+	// backends so that only the models named after the scheme of this backend are unloaded
+	for name, state := range tritonStates {
+		if knownTritonModels[name] || state != tritonModelStateReady || !util.IsTritonModelName(name) {
+			continue
+		}
+		action := ReconcileAction{
+			Action:      ReconcileActionUnload,
+			TritonModel: name,
+			Reason:      "loaded in Triton but not owned by any model",
+		}
+		if !param.DryRun {
+			if _, err := w.triton.UnloadModelRequest(name); err != nil {
+				action.Error = err.Error()
+			}
+		}
+		report.Actions = append(report.Actions, action)
+	}
+
+	orphans, err := util.FindOrphanedModelDirs(config.Config.TritonServer.ModelStore, knownTritonModels, config.Config.Reconciler.GracePeriod)
+	if err != nil {
+		return nil, err
+	}
+	for _, name := range orphans {
+		action := ReconcileAction{
+			Action:      ReconcileActionRemoveDir,
+			TritonModel: name,
+			Reason:      "model store directory not owned by any model",
+		}
+		if !param.DryRun {
+			if err := util.RemoveUnder(config.Config.TritonServer.ModelStore, name); err != nil {
+				action.Error = err.Error()
+			}
+		}
+		report.Actions = append(report.Actions, action)
+	}
+
+	logger.Info("ReconcileModelsActivity completed")
+
+	return report, nil
+}
+
+// reconcileModel compares the desired state of a model with the state of its Triton models and fixes the drift
+func (w *worker) reconcileModel(ctx context.Context, dryRun bool, dbModel datamodel.Model, tritonModels []datamodel.TritonModel, tritonStates map[string]string) []ReconcileAction {
+	actions := []ReconcileAction{}
+	if len(tritonModels) == 0 {
+		return actions
+	}
+
+	resourcePermalink := util.ConvertModelToResourcePermalink(dbModel.UID.String())
+
+	// The controller holds the latest state, fall back to the database when it is unknown to the controller
+	state := modelPB.Model_State(dbModel.State)
+	if resp, err := w.controllerClient.GetResource(ctx, &controllerPB.GetResourceRequest{
+		ResourcePermalink: resourcePermalink,
+	}); err == nil {
+		state = resp.Resource.GetModelState()
+	}
+
+	newAction := func(action string, tritonModel string, reason string) ReconcileAction {
+		return ReconcileAction{
+			Action:      action,
+			ModelUID:    dbModel.UID.String(),
+			Owner:       dbModel.Owner,
+			ModelID:     dbModel.ID,
+			TritonModel: tritonModel,
+			Reason:      reason,
+		}
+	}
+
+	var notReady, ready []string
+	for _, tm := range tritonModels {
+		switch tritonStates[tm.Name] {
+		case tritonModelStateLoading, tritonModelStateUnloading:
+			// A deployment or undeployment is in flight, leave it to the running workflow
+			return actions
+		case tritonModelStateReady:
+			ready = append(ready, tm.Name)
+		default:
+			notReady = append(notReady, tm.Name)
+		}
+	}
+
+	switch state {
+	case modelPB.Model_STATE_ONLINE:
+		// Scaled-to-zero models are unloaded on purpose and loaded back by their next inference
+		if len(notReady) == 0 || dbModel.ScaledToZero {
+			return actions
+		}
+		action := newAction(ReconcileActionReload, "", fmt.Sprintf("model is %s but Triton model(s) %v are not ready", state, notReady))
+		if !dryRun {
+			if err := w.loadTritonModels(dbModel, tritonModels); err != nil {
+				action.Error = err.Error()
+			}
+		}
+		actions = append(actions, action)
+		if action.Error == "" {
+			return actions
+		}
+
+		errAction := newAction(ReconcileActionMarkError, "", "reloading the Triton models failed")
+		if !dryRun {
+			if _, err := w.controllerClient.UpdateResource(ctx, &controllerPB.UpdateResourceRequest{
+				Resource: &controllerPB.Resource{
+					ResourcePermalink: resourcePermalink,
+					State: &controllerPB.Resource_ModelState{
+						ModelState: modelPB.Model_STATE_ERROR,
+					},
+				},
+			}); err != nil {
+				errAction.Error = err.Error()
+			}
+		}
+		actions = append(actions, errAction)
+	case modelPB.Model_STATE_OFFLINE:
+		for _, name := range ready {
+			action := newAction(ReconcileActionUnload, name, fmt.Sprintf("model is %s but the Triton model is ready", state))
+			if !dryRun {
+				if _, err := w.triton.UnloadModelRequest(name); err != nil {
+					action.Error = err.Error()
+				}
+			}
+			actions = append(actions, action)
+		}
+	}
+
+	return actions
+}
+
+// loadTritonModels loads all the Triton models of a model, the ensemble model is loaded last
+func (w *worker) loadTritonModels(dbModel datamodel.Model, tritonModels []datamodel.TritonModel) error {
+	tEnsembleModel, _ := w.repository.GetTritonEnsembleModel(dbModel.UID)
+	for _, tModel := range tritonModels {
+		if tEnsembleModel.Name != "" && tEnsembleModel.Name == tModel.Name {
+			continue
+		}
+		if err := w.loadTritonModel(dbModel, tModel.Name); err != nil {
+			return err
+		}
+	}
+	if tEnsembleModel.Name != "" {
+		if err := w.loadTritonModel(dbModel, tEnsembleModel.Name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// loadTritonModel loads a Triton model with the deploy configuration of its model applied
+func (w *worker) loadTritonModel(dbModel datamodel.Model, tritonModelName string) error {
+	modelConfig, err := util.GetModelConfigOverride(config.Config.TritonServer.ModelStore, tritonModelName, dbModel.DeployConfig)
+	if err != nil {
+		return err
+	}
+	_, err = w.triton.LoadModelWithConfigRequest(tritonModelName, modelConfig)
+	return err
+}
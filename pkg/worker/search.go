@@ -0,0 +1,83 @@
+package worker
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/gofrs/uuid"
+	"go.temporal.io/api/enums/v1"
+	"go.temporal.io/api/operatorservice/v1"
+	"go.temporal.io/sdk/client"
+
+	"github.com/instill-ai/model-backend/pkg/datamodel"
+)
+
+// The search attributes of the workflows of the model operations, so that they are listed by owner and model in the
+// visibility query. A model being created has no UID yet and is searched by its ID.
+const (
+	SearchAttributeOwner    = "ModelOwner"
+	SearchAttributeModelID  = "ModelID"
+	SearchAttributeModelUID = "ModelUID"
+)
+
+// The memo of the workflow of a model operation tells the model it is about
+const (
+	OperationMemoOwner    = "owner"
+	OperationMemoModelID  = "model_id"
+	OperationMemoModelUID = "model_uid"
+)
+
+// OperationMemo returns the memo of the workflow of a model operation, the UID of a model being created is not known
+// yet
+func OperationMemo(owner string, model *datamodel.Model) map[string]interface{} {
+	memo := map[string]interface{}{
+		OperationMemoOwner:   owner,
+		OperationMemoModelID: model.ID,
+	}
+	if model.UID != uuid.Nil {
+		memo[OperationMemoModelUID] = model.UID.String()
+	}
+	return memo
+}
+
+// OperationSearchAttributes returns the search attributes of the workflow of a model operation, the operations of a
+// model being created are searched by the ID of the model
+func OperationSearchAttributes(owner string, model *datamodel.Model) map[string]interface{} {
+	searchAttributes := map[string]interface{}{
+		SearchAttributeOwner:   owner,
+		SearchAttributeModelID: model.ID,
+	}
+	if model.UID != uuid.Nil {
+		searchAttributes[SearchAttributeModelUID] = model.UID.String()
+	}
+	return searchAttributes
+}
+
+// RegisterSearchAttributes registers the search attributes of the model operations missing from a namespace, a
+// workflow cannot be started with a search attribute the namespace does not know
+func RegisterSearchAttributes(ctx context.Context, temporalClient client.Client, namespace string) error {
+	resp, err := temporalClient.OperatorService().ListSearchAttributes(ctx, &operatorservice.ListSearchAttributesRequest{
+		Namespace: namespace,
+	})
+	if err != nil {
+		return fmt.Errorf("unable to list the search attributes: %w", err)
+	}
+
+	missing := map[string]enums.IndexedValueType{}
+	for _, name := range []string{SearchAttributeOwner, SearchAttributeModelID, SearchAttributeModelUID} {
+		if _, ok := resp.CustomAttributes[name]; !ok {
+			missing[name] = enums.INDEXED_VALUE_TYPE_KEYWORD
+		}
+	}
+	if len(missing) == 0 {
+		return nil
+	}
+
+	if _, err := temporalClient.OperatorService().AddSearchAttributes(ctx, &operatorservice.AddSearchAttributesRequest{
+		SearchAttributes: missing,
+		Namespace:        namespace,
+	}); err != nil {
+		return fmt.Errorf("unable to add the search attributes: %w", err)
+	}
+	return nil
+}
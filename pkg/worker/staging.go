@@ -0,0 +1,209 @@
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"time"
+
+	"go.temporal.io/api/enums/v1"
+	"go.temporal.io/api/serviceerror"
+	"go.temporal.io/sdk/activity"
+	"go.temporal.io/sdk/client"
+	"go.uber.org/zap"
+
+	"github.com/instill-ai/model-backend/config"
+	"github.com/instill-ai/model-backend/pkg/datamodel"
+	"github.com/instill-ai/model-backend/pkg/util"
+)
+
+// getStagingRegistryDir returns where the leases of the staging folders in use are kept, its name is not a UUID so
+// that the garbage collector leaves it alone
+func getStagingRegistryDir() string {
+	return filepath.Join(util.TMP_DIR, "model-backend-staging")
+}
+
+// stagingLease tells which activity of which worker process uses a staging folder, and the Triton models it copies
+// files into, so that what a dead process left behind can be cleaned up
+type stagingLease struct {
+	Dir          string    `json:"dir"`
+	Pid          int       `json:"pid"`
+	WorkflowID   string    `json:"workflow_id"`
+	RunID        string    `json:"run_id"`
+	Activity     string    `json:"activity"`
+	TritonModels []string  `json:"triton_models,omitempty"`
+	CreateTime   time.Time `json:"create_time"`
+}
+
+func getStagingLeasePath(dir string) string {
+	return filepath.Join(getStagingRegistryDir(), filepath.Base(dir)+".json")
+}
+
+// registerStagingDir records that the running activity uses a staging folder, and copies files into the Triton
+// models if any, until the returned release is called
+func registerStagingDir(ctx context.Context, dir string, tritonModels []datamodel.TritonModel) (func(), error) {
+	info := activity.GetInfo(ctx)
+	lease := stagingLease{
+		Dir:        dir,
+		Pid:        os.Getpid(),
+		WorkflowID: info.WorkflowExecution.ID,
+		RunID:      info.WorkflowExecution.RunID,
+		Activity:   info.ActivityType.Name,
+		CreateTime: time.Now(),
+	}
+	for _, tm := range tritonModels {
+		lease.TritonModels = append(lease.TritonModels, tm.Name)
+	}
+	b, err := json.Marshal(lease)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(getStagingRegistryDir(), os.ModePerm); err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(getStagingLeasePath(dir), b, 0644); err != nil {
+		return nil, err
+	}
+	return func() { _ = os.Remove(getStagingLeasePath(dir)) }, nil
+}
+
+// isProcessAlive tells whether another process with the pid runs on this host, the current process is not another
+// one since a lease found at startup was written by a previous process with the same pid
+func isProcessAlive(pid int) bool {
+	if pid <= 0 || pid == os.Getpid() {
+		return false
+	}
+	err := syscall.Kill(pid, 0)
+	return err == nil || errors.Is(err, syscall.EPERM)
+}
+
+// isWorkflowRunning tells whether a workflow run still runs, a run that cannot be described is taken as running
+func isWorkflowRunning(ctx context.Context, c client.Client, workflowID string, runID string) bool {
+	resp, err := c.DescribeWorkflowExecution(ctx, workflowID, runID)
+	if err != nil {
+		var notFound *serviceerror.NotFound
+		return !errors.As(err, &notFound)
+	}
+	return resp.GetWorkflowExecutionInfo().GetStatus() == enums.WORKFLOW_EXECUTION_STATUS_RUNNING
+}
+
+// isLatestRunFailed tells whether a workflow run is the latest run of its workflow ID and ended without completing. The
+// model store is shared by the worker hosts, the files a dead stage copied are kept once its deployment completed on
+// another host or a later operation ran on the model, and when the workflow cannot be described.
+func isLatestRunFailed(ctx context.Context, c client.Client, workflowID string, runID string) bool {
+	resp, err := c.DescribeWorkflowExecution(ctx, workflowID, "")
+	if err != nil {
+		return false
+	}
+	info := resp.GetWorkflowExecutionInfo()
+	if info.GetExecution().GetRunId() != runID {
+		return false
+	}
+	switch info.GetStatus() {
+	case enums.WORKFLOW_EXECUTION_STATUS_RUNNING, enums.WORKFLOW_EXECUTION_STATUS_COMPLETED:
+		return false
+	default:
+		return true
+	}
+}
+
+// CleanupStaleStagingDirs removes what the activities of dead worker processes left behind, to be called when the
+// worker starts. A staging folder is kept only when its files were fetched and its deployment still runs, so that a
+// retried activity resumes from them, and the files a dead stage partially copied into the model repository are
+// removed once its deployment failed and no later operation ran on the model, so that the next deployment fetches
+// them again.
+func CleanupStaleStagingDirs(ctx context.Context, c client.Client, logger *zap.Logger) error {
+	entries, err := os.ReadDir(getStagingRegistryDir())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+
+	for _, entry := range entries {
+		if !strings.HasSuffix(entry.Name(), ".json") {
+			continue
+		}
+		leasePath := filepath.Join(getStagingRegistryDir(), entry.Name())
+		b, err := os.ReadFile(leasePath)
+		if err != nil {
+			return err
+		}
+		var lease stagingLease
+		if err := json.Unmarshal(b, &lease); err != nil {
+			logger.Warn(fmt.Sprintf("removing the unreadable staging lease %s: %s", leasePath, err))
+			_ = os.Remove(leasePath)
+			continue
+		}
+		if isProcessAlive(lease.Pid) {
+			continue
+		}
+
+		running := isWorkflowRunning(ctx, c, lease.WorkflowID, lease.RunID)
+		_, fetchedErr := os.Stat(filepath.Join(lease.Dir, fetchedMarker))
+		_, stagedErr := os.Stat(filepath.Join(lease.Dir, stagedMarker))
+		if fetchedErr != nil || !running {
+			logger.Info(fmt.Sprintf("removing the staging folder %s left by %s of workflow %s", lease.Dir, lease.Activity, lease.WorkflowID))
+			if err := os.RemoveAll(lease.Dir); err != nil {
+				return err
+			}
+		}
+		if !running && stagedErr != nil && len(lease.TritonModels) > 0 && isLatestRunFailed(ctx, c, lease.WorkflowID, lease.RunID) {
+			logger.Info(fmt.Sprintf("removing the model files partially copied by %s of workflow %s", lease.Activity, lease.WorkflowID))
+			if err := util.RemoveModelFiles(config.Config.TritonServer.ModelStore, lease.TritonModels); err != nil {
+				return err
+			}
+		}
+		if err := os.Remove(leasePath); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+// IsStagingDir tells whether an entry of the temporary folder is the staging folder of a deployment, it holds a lease
+// or the markers of its fetch and stage, so that only the worker hosts clean it up
+func IsStagingDir(name string) bool {
+	for _, path := range []string{
+		getStagingLeasePath(name),
+		filepath.Join(util.TMP_DIR, name, fetchedMarker),
+		filepath.Join(util.TMP_DIR, name, stagedMarker),
+	} {
+		if _, err := os.Stat(path); err == nil {
+			return true
+		}
+	}
+	return false
+}
+
+// CollectStaleTmpEntries removes the staged archives, clones and staging folders older than the grace period from
+// the temporary folder of the worker host, except for the staging folders a live worker process still uses
+func CollectStaleTmpEntries(logger *zap.Logger) {
+	names, err := util.FindStaleTmpEntries(util.TMP_DIR, config.Config.GarbageCollector.GracePeriod)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			logger.Error(fmt.Sprintf("unable to list the temporary files: %s", err))
+		}
+		return
+	}
+
+	for _, name := range names {
+		if b, err := os.ReadFile(getStagingLeasePath(name)); err == nil {
+			var lease stagingLease
+			if err := json.Unmarshal(b, &lease); err == nil && (lease.Pid == os.Getpid() || isProcessAlive(lease.Pid)) {
+				continue
+			}
+		}
+		if err := util.RemoveUnder(util.TMP_DIR, name); err != nil {
+			logger.Error(fmt.Sprintf("unable to remove %s/%s: %s", util.TMP_DIR, name, err))
+			continue
+		}
+		logger.Info(fmt.Sprintf("removed the stale temporary file %s/%s", util.TMP_DIR, name))
+	}
+}
@@ -0,0 +1,47 @@
+package worker_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	uuid "github.com/gofrs/uuid"
+
+	"github.com/instill-ai/model-backend/pkg/util"
+	"github.com/instill-ai/model-backend/pkg/worker"
+)
+
+func TestIsStagingDir(t *testing.T) {
+	newDir := func(t *testing.T, files ...string) string {
+		name := uuid.Must(uuid.NewV4()).String()
+		dir := filepath.Join(util.TMP_DIR, name)
+		assert.NoError(t, os.MkdirAll(dir, os.ModePerm))
+		t.Cleanup(func() { _ = os.RemoveAll(dir) })
+		for _, f := range files {
+			assert.NoError(t, os.WriteFile(filepath.Join(dir, f), nil, 0644))
+		}
+		return name
+	}
+
+	t.Run("SkipsAFolderWithTheMarkerOfItsFetch", func(t *testing.T) {
+		assert.True(t, worker.IsStagingDir(newDir(t, "model.onnx", ".fetched")))
+	})
+	t.Run("SkipsAFolderWithTheMarkerOfItsStage", func(t *testing.T) {
+		assert.True(t, worker.IsStagingDir(newDir(t, ".staged")))
+	})
+	t.Run("SkipsAFolderWithALease", func(t *testing.T) {
+		name := newDir(t)
+		registry := filepath.Join(util.TMP_DIR, "model-backend-staging")
+		assert.NoError(t, os.MkdirAll(registry, os.ModePerm))
+		lease := filepath.Join(registry, name+".json")
+		assert.NoError(t, os.WriteFile(lease, []byte(`{"pid":1}`), 0644))
+		t.Cleanup(func() { _ = os.Remove(lease) })
+
+		assert.True(t, worker.IsStagingDir(name))
+	})
+	t.Run("LeavesAnArchiveToTheGarbageCollector", func(t *testing.T) {
+		assert.False(t, worker.IsStagingDir(newDir(t, "model.zip")))
+	})
+}
@@ -23,9 +23,31 @@ const TaskQueue = "model-backend"
 type Worker interface {
 	DeployModelWorkflow(ctx workflow.Context, param *ModelParams) error
 	DeployModelActivity(ctx context.Context, param *ModelParams) error
+	CheckCompatibilityActivity(ctx context.Context, param *ModelParams) error
+	FetchModelActivity(ctx context.Context, param *ModelParams) error
+	StageModelActivity(ctx context.Context, param *ModelParams) error
+	ValidateModelActivity(ctx context.Context, param *ModelParams) error
+	LoadModelActivity(ctx context.Context, param *ModelParams) error
+	WarmupModelActivity(ctx context.Context, param *ModelParams) error
+	PublishModelStateActivity(ctx context.Context, param *ModelStateParams) error
+	CleanupDeployActivity(ctx context.Context, param *ModelParams) error
 	UnDeployModelWorkflow(ctx workflow.Context, param *ModelParams) error
 	UnDeployModelActivity(ctx context.Context, param *ModelParams) error
 	CreateModelWorkflow(ctx workflow.Context, param *ModelParams) error
+	CleanupCreateActivity(ctx context.Context, param *ModelParams) error
+	DeleteModelWorkflow(ctx workflow.Context, param *ModelParams) error
+	ModelOperationWorkflow(ctx workflow.Context, param *OperationParams) (*OperationResult, error)
+	PrepareModelOperationActivity(ctx context.Context, param *OperationParams) (string, error)
+	ModelActionActivity(ctx context.Context, param *OperationParams) (string, error)
+	BulkModelWorkflow(ctx workflow.Context, param *BulkParams) (*BulkReport, error)
+	ReconcileModelsWorkflow(ctx workflow.Context, param *ReconcileParams) (*ReconcileReport, error)
+	ReconcileModelsActivity(ctx context.Context, param *ReconcileParams) (*ReconcileReport, error)
+}
+
+// ModelDeleter deletes a model, the service implements it so that a bulk or scheduled delete runs the same checks and
+// clean-up as the endpoint
+type ModelDeleter interface {
+	DeleteModel(ctx context.Context, owner string, modelID string) error
 }
 
 // worker represents resources required to run Temporal workflow and activity
@@ -34,10 +56,11 @@ type worker struct {
 	repository       repository.Repository
 	triton           triton.Triton
 	controllerClient controllerPB.ControllerPrivateServiceClient
+	modelDeleter     ModelDeleter
 }
 
 // NewWorker initiates a temporal worker for workflow and activity definition
-func NewWorker(r repository.Repository, t triton.Triton, c controllerPB.ControllerPrivateServiceClient) Worker {
+func NewWorker(r repository.Repository, t triton.Triton, c controllerPB.ControllerPrivateServiceClient, d ModelDeleter) Worker {
 	cache, _ := bigcache.NewBigCache(bigcache.DefaultConfig(60 * time.Minute))
 
 	return &worker{
@@ -45,5 +68,6 @@ func NewWorker(r repository.Repository, t triton.Triton, c controllerPB.Controll
 		repository:       r,
 		triton:           t,
 		controllerClient: c,
+		modelDeleter:     d,
 	}
 }